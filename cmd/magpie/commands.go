@@ -0,0 +1,300 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	magpie "github.com/blazeroni/magpie/pkg"
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+// drawFlags are the flags shared by blend and composite.
+type drawFlags struct {
+	rect    rectFlag
+	sp      pointFlag
+	model   string
+	jobs    int
+	out     string
+	batch   bool
+	quality int
+}
+
+func (f *drawFlags) register(fs *flag.FlagSet) {
+	fs.Var(&f.rect, "rect", "region of dst to draw, as `x0,y0,x1,y1` (default: the bounds of dst)")
+	fs.Var(&f.sp, "sp", "point of src aligned with the top-left corner of the region, as `x,y` (default: the top-left corner of src)")
+	fs.StringVar(&f.model, "model", "", "color model of the output: nrgba or rgba (default: rgba for RGBA inputs, nrgba otherwise)")
+	fs.IntVar(&f.jobs, "j", 0, "number of goroutines (default: half the available CPUs)")
+	fs.StringVar(&f.out, "o", "", "output file, or output directory with -batch (required)")
+	fs.BoolVar(&f.batch, "batch", false, "treat dst as a directory or glob pattern and draw src onto every matching image")
+	fs.IntVar(&f.quality, "quality", 90, "JPEG quality")
+}
+
+func runBlend(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("blend", "dst src", stderr)
+	var f drawFlags
+	f.register(fs)
+	mode := fs.String("mode", "normal", "blend mode, e.g. multiply or soft-light-w3c; CSS keywords are accepted with -css")
+	css := fs.Bool("css", false, "interpret -mode as a CSS mix-blend-mode keyword")
+	compositing := fs.String("compositing", "all", "compositing: all, blend-only, blend-and-dst or blend-and-src")
+	seed := fs.Uint64("seed", 0, "noise seed of the dissolve mode")
+	fs.Usage = drawUsage(fs, "blend", stderr, blendModeNames())
+	if err := parseFlags(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	parse := op.ParseBlendMode
+	if *css {
+		parse = op.ParseCSSBlendMode
+	}
+	m, err := parse(*mode)
+	if err != nil {
+		return err
+	}
+	c, err := op.ParseBlendCompositing(*compositing)
+	if err != nil {
+		return err
+	}
+	return f.draw(fs.Arg(0), fs.Arg(1), op.BlendOp{Mode: m, Compositing: c, Seed: *seed}, stdout)
+}
+
+func runComposite(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("composite", "dst src", stderr)
+	var f drawFlags
+	f.register(fs)
+	mode := fs.String("mode", "source-over", "composite mode, e.g. source-in or destination-out")
+	fs.Usage = drawUsage(fs, "composite", stderr, compositeModeNames())
+	if err := parseFlags(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	m, err := op.ParseCompositeMode(*mode)
+	if err != nil {
+		return err
+	}
+	return f.draw(fs.Arg(0), fs.Arg(1), op.CompositeOp{Mode: m}, stdout)
+}
+
+// drawUsage returns a usage function that also lists the available modes.
+func drawUsage(fs *flag.FlagSet, name string, stderr io.Writer, modes []string) func() {
+	return func() {
+		fmt.Fprintf(stderr, "Usage: magpie %s [flags] dst src\n\nFlags:\n", name)
+		fs.PrintDefaults()
+		fmt.Fprintf(stderr, "\nModes:\n  %s\n", strings.Join(modes, "\n  "))
+	}
+}
+
+// draw applies o to the dst and src files, or to every dst file in batch mode.
+func (f *drawFlags) draw(dstPath, srcPath string, o core.Op, stdout io.Writer) error {
+	if f.out == "" {
+		return errors.New("an output is required; use -o")
+	}
+	model, err := parseModel(f.model)
+	if err != nil {
+		return err
+	}
+	ctx := newContext(f.jobs)
+	src, err := loadImage(srcPath)
+	if err != nil {
+		return err
+	}
+
+	if !f.batch {
+		return f.drawFile(ctx, dstPath, src, o, model, f.out)
+	}
+	files, err := listImages(dstPath)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no images match %s", dstPath)
+	}
+	if err := os.MkdirAll(f.out, 0o755); err != nil {
+		return err
+	}
+	// A failing file doesn't stop the batch; the failures are reported at the end.
+	var errs []error
+	for _, file := range files {
+		out := filepath.Join(f.out, filepath.Base(file))
+		if err := f.drawFile(ctx, file, src, o, model, out); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		fmt.Fprintln(stdout, out)
+	}
+	return errors.Join(errs...)
+}
+
+func (f *drawFlags) drawFile(ctx magpie.Context, dstPath string, src image.Image, o core.Op, model color.Model, outPath string) error {
+	img, err := loadImage(dstPath)
+	if err != nil {
+		return err
+	}
+	dst := newCanvas(model, img)
+	r := dst.Bounds()
+	if f.rect.set {
+		r = f.rect.r
+	}
+	sp := src.Bounds().Min
+	if f.sp.set {
+		sp = f.sp.p
+	}
+	if _, err := ctx.Draw(dst, r, src, sp, o, magpie.ToDst()); err != nil {
+		return err
+	}
+	return saveImage(outPath, dst, f.quality)
+}
+
+func runRecipe(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("recipe", "recipe.json name=file...", stderr)
+	save := namedFiles{}
+	fs.Var(save, "save", "save the image `name=file` after the recipe ran; may be repeated (required)")
+	jobs := fs.Int("j", 0, "number of goroutines (default: half the available CPUs)")
+	quality := fs.Int("quality", 90, "JPEG quality")
+	if err := parseFlags(fs, args, 1, -1); err != nil {
+		return err
+	}
+	if len(save) == 0 {
+		return errors.New("nothing to save; use -save name=file")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	recipe, err := magpie.LoadRecipe(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	inputs := namedFiles{}
+	for _, arg := range fs.Args()[1:] {
+		if err := inputs.Set(arg); err != nil {
+			return err
+		}
+	}
+	images := make(map[string]image.Image, len(inputs))
+	for name, file := range inputs {
+		if images[name], err = loadImage(file); err != nil {
+			return err
+		}
+	}
+
+	result, err := recipe.Execute(newContext(*jobs), images)
+	if err != nil {
+		return err
+	}
+	for name, file := range save {
+		img, ok := result[name]
+		if !ok {
+			return fmt.Errorf("the recipe has no image %q", name)
+		}
+		if err := saveImage(file, img, *quality); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, file)
+	}
+	return nil
+}
+
+func runInfo(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("info", "file...", stderr)
+	if err := parseFlags(fs, args, 1, -1); err != nil {
+		return err
+	}
+	var errs []error
+	for _, path := range fs.Args() {
+		if err := printInfo(path, stdout); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func printInfo(path string, stdout io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	cfg, format, err := image.DecodeConfig(f)
+	if err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+	fmt.Fprintf(stdout, "%s: %s %dx%d %s\n", path, format, cfg.Width, cfg.Height, colorModelName(cfg.ColorModel))
+	return nil
+}
+
+// newContext returns a context with the given concurrency, or the default context for 0.
+func newContext(jobs int) magpie.Context {
+	if jobs <= 0 {
+		return magpie.DefaultContext()
+	}
+	return magpie.NewContext(magpie.WithPixelIterator(jobs))
+}
+
+// parseModel returns the color model with the given name, or nil for an empty name.
+func parseModel(name string) (color.Model, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return nil, nil
+	case "nrgba":
+		return color.NRGBAModel, nil
+	case "rgba":
+		return color.RGBAModel, nil
+	default:
+		return nil, fmt.Errorf("unknown color model %q; use nrgba or rgba", name)
+	}
+}
+
+// newCanvas returns img in the color model m. A nil model keeps RGBA images and converts
+// the others to NRGBA. The CLI draws on the canvas so that the output file holds the whole
+// dst image, with only the drawn region changed.
+func newCanvas(m color.Model, img image.Image) image.Image {
+	if m == nil {
+		if _, ok := img.(*image.RGBA); ok {
+			return img
+		}
+		m = color.NRGBAModel
+	}
+	if m == color.RGBAModel {
+		return magpie.AsRGBA(img)
+	}
+	return magpie.AsNRGBA(img)
+}
+
+// blendModeNames lists the names of all blend modes in alphabetical order.
+func blendModeNames() []string {
+	var names []string
+	for m := op.BlendMode(0); ; m++ {
+		name, err := m.MarshalText()
+		if err != nil {
+			slices.Sort(names)
+			return names
+		}
+		names = append(names, string(name))
+	}
+}
+
+// compositeModeNames lists the names of all composite modes.
+func compositeModeNames() []string {
+	var names []string
+	for m := op.CompositeMode(0); ; m++ {
+		name, err := m.MarshalText()
+		if err != nil {
+			return names
+		}
+		names = append(names, string(name))
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// imageExts are the file extensions of the supported formats.
+var imageExts = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true}
+
+// loadImage decodes a PNG, JPEG or GIF file.
+func loadImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// saveImage encodes img in the format given by the extension of path.
+func saveImage(path string, img image.Image, quality int) (err error) {
+	var encode func(f *os.File) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		encode = func(f *os.File) error { return png.Encode(f, img) }
+	case ".jpg", ".jpeg":
+		encode = func(f *os.File) error { return jpeg.Encode(f, img, &jpeg.Options{Quality: quality}) }
+	case ".gif":
+		encode = func(f *os.File) error { return gif.Encode(f, img, nil) }
+	default:
+		return fmt.Errorf("unsupported output format %q; use .png, .jpg or .gif", filepath.Ext(path))
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	return encode(f)
+}
+
+// listImages returns the image files matched by pattern: all images of a directory, or the
+// files matching a glob pattern. The files are sorted by name.
+func listImages(pattern string) ([]string, error) {
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		entries, err := os.ReadDir(pattern)
+		if err != nil {
+			return nil, err
+		}
+		var files []string
+		for _, e := range entries {
+			if !e.IsDir() && imageExts[strings.ToLower(filepath.Ext(e.Name()))] {
+				files = append(files, filepath.Join(pattern, e.Name()))
+			}
+		}
+		return files, nil
+	}
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// colorModelName returns a short name for the color models of the standard library.
+func colorModelName(m color.Model) string {
+	switch m {
+	case color.NRGBAModel:
+		return "NRGBA"
+	case color.RGBAModel:
+		return "RGBA"
+	case color.NRGBA64Model:
+		return "NRGBA64"
+	case color.RGBA64Model:
+		return "RGBA64"
+	case color.GrayModel:
+		return "Gray"
+	case color.Gray16Model:
+		return "Gray16"
+	case color.AlphaModel:
+		return "Alpha"
+	case color.Alpha16Model:
+		return "Alpha16"
+	case color.YCbCrModel:
+		return "YCbCr"
+	case color.CMYKModel:
+		return "CMYK"
+	}
+	if p, ok := m.(color.Palette); ok {
+		return fmt.Sprintf("Paletted (%d colors)", len(p))
+	}
+	return fmt.Sprintf("%T", m)
+}
+
+// rectFlag is a flag.Value for a rectangle written as "x0,y0,x1,y1".
+type rectFlag struct {
+	r   image.Rectangle
+	set bool
+}
+
+func (f *rectFlag) String() string {
+	if !f.set {
+		return ""
+	}
+	return fmt.Sprintf("%d,%d,%d,%d", f.r.Min.X, f.r.Min.Y, f.r.Max.X, f.r.Max.Y)
+}
+
+func (f *rectFlag) Set(s string) error {
+	v, err := parseInts(s, 4)
+	if err != nil {
+		return err
+	}
+	f.r, f.set = image.Rect(v[0], v[1], v[2], v[3]), true
+	return nil
+}
+
+// pointFlag is a flag.Value for a point written as "x,y".
+type pointFlag struct {
+	p   image.Point
+	set bool
+}
+
+func (f *pointFlag) String() string {
+	if !f.set {
+		return ""
+	}
+	return fmt.Sprintf("%d,%d", f.p.X, f.p.Y)
+}
+
+func (f *pointFlag) Set(s string) error {
+	v, err := parseInts(s, 2)
+	if err != nil {
+		return err
+	}
+	f.p, f.set = image.Pt(v[0], v[1]), true
+	return nil
+}
+
+// parseInts parses n comma separated integers.
+func parseInts(s string, n int) ([]int, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return nil, fmt.Errorf("expected %d comma separated integers", n)
+	}
+	v := make([]int, n)
+	for i, p := range parts {
+		x, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return nil, err
+		}
+		v[i] = x
+	}
+	return v, nil
+}
+
+// namedFiles is a flag.Value collecting repeated "name=file" arguments.
+type namedFiles map[string]string
+
+func (f namedFiles) String() string {
+	pairs := make([]string, 0, len(f))
+	for name, file := range f {
+		pairs = append(pairs, name+"="+file)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+func (f namedFiles) Set(s string) error {
+	name, file, ok := strings.Cut(s, "=")
+	if !ok || name == "" || file == "" {
+		return fmt.Errorf("expected name=file, got %q", s)
+	}
+	f[name] = file
+	return nil
+}
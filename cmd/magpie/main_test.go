@@ -0,0 +1,251 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	magpie "github.com/blazeroni/magpie/pkg"
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+// writeTestImage writes a gradient PNG and returns the image.
+func writeTestImage(t *testing.T, path string, w, h int, shift uint8) *image.NRGBA {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x*16) + shift, uint8(y * 16), shift, uint8(128 + x*8)})
+		}
+	}
+	if err := saveImage(path, img, 0); err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func runCommand(t *testing.T, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	code = run(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func readPNG(t *testing.T, path string) image.Image {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func assertSamePixels(t *testing.T, got, want image.Image) {
+	t.Helper()
+	if got.Bounds() != want.Bounds() {
+		t.Fatalf("bounds = %v, want %v", got.Bounds(), want.Bounds())
+	}
+	b := want.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			g := color.NRGBAModel.Convert(got.At(x, y))
+			w := color.NRGBAModel.Convert(want.At(x, y))
+			if g != w {
+				t.Fatalf("pixel (%d,%d) = %v, want %v", x, y, g, w)
+			}
+		}
+	}
+}
+
+func TestBlend(t *testing.T) {
+	tests := []struct {
+		rect, jobs string
+		r          image.Rectangle
+	}{
+		{"2,2,6,6", "1", image.Rect(2, 2, 6, 6)},
+		{"2,2,6,6", "4", image.Rect(2, 2, 6, 6)},
+		{"2,10,6,16", "4", image.Rect(2, 10, 6, 16)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rect+" -j "+tt.jobs, func(t *testing.T) {
+			dir := t.TempDir()
+			dst := writeTestImage(t, filepath.Join(dir, "dst.png"), 8, 20, 0)
+			src := writeTestImage(t, filepath.Join(dir, "src.png"), 8, 20, 40)
+			out := filepath.Join(dir, "out.png")
+
+			code, _, stderr := runCommand(t, "blend", "-mode", "multiply", "-rect", tt.rect, "-sp", "1,1", "-j", tt.jobs,
+				"-o", out, filepath.Join(dir, "dst.png"), filepath.Join(dir, "src.png"))
+			if code != 0 {
+				t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+			}
+
+			// Only the region changes; the rest of dst is kept.
+			if _, err := magpie.Draw(dst, tt.r, src, image.Pt(1, 1),
+				op.BlendOp{Mode: op.Multiply, Compositing: op.CompositeAll}, magpie.ToDst()); err != nil {
+				t.Fatal(err)
+			}
+			assertSamePixels(t, readPNG(t, out), dst)
+		})
+	}
+}
+
+func TestComposite(t *testing.T) {
+	dir := t.TempDir()
+	dst := writeTestImage(t, filepath.Join(dir, "dst.png"), 8, 8, 0)
+	src := writeTestImage(t, filepath.Join(dir, "src.png"), 8, 8, 40)
+	out := filepath.Join(dir, "out.png")
+
+	code, _, stderr := runCommand(t, "composite", "-mode", "xor", "-model", "rgba", "-j", "2",
+		"-o", out, filepath.Join(dir, "dst.png"), filepath.Join(dir, "src.png"))
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+
+	want, err := magpie.Draw(magpie.AsRGBA(dst), dst.Bounds(), src, image.Point{}, op.CompositeOp{Mode: op.Xor}, magpie.ToDst())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSamePixels(t, readPNG(t, out), want)
+}
+
+func TestBatch(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in")
+	if err := os.Mkdir(in, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.png", "b.png"} {
+		writeTestImage(t, filepath.Join(in, name), 4, 4, 0)
+	}
+	if err := os.WriteFile(filepath.Join(in, "notes.txt"), []byte("not an image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeTestImage(t, filepath.Join(dir, "src.png"), 4, 4, 40)
+	out := filepath.Join(dir, "out")
+
+	code, stdout, stderr := runCommand(t, "blend", "-mode", "screen", "-batch", "-o", out, in, filepath.Join(dir, "src.png"))
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+	for _, name := range []string{"a.png", "b.png"} {
+		path := filepath.Join(out, name)
+		if !strings.Contains(stdout, path) {
+			t.Errorf("stdout should list %s: %q", path, stdout)
+		}
+		readPNG(t, path)
+	}
+
+	// A glob matching nothing is an error.
+	code, _, _ = runCommand(t, "blend", "-batch", "-o", out, filepath.Join(dir, "*.jpg"), filepath.Join(dir, "src.png"))
+	if code != 1 {
+		t.Errorf("exit code for an empty batch = %d, want 1", code)
+	}
+}
+
+func TestRecipe(t *testing.T) {
+	dir := t.TempDir()
+	bg := writeTestImage(t, filepath.Join(dir, "bg.png"), 8, 8, 0)
+	fg := writeTestImage(t, filepath.Join(dir, "fg.png"), 8, 8, 40)
+	recipe := filepath.Join(dir, "recipe.json")
+	if err := os.WriteFile(recipe, []byte(`{
+  "version": 1,
+  "steps": [
+    {"dst": "bg", "src": "fg", "op": {"blend": "overlay"}, "output": {"mode": "new", "name": "result"}}
+  ]
+}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "result.png")
+
+	code, _, stderr := runCommand(t, "recipe", "-save", "result="+out, recipe,
+		"bg="+filepath.Join(dir, "bg.png"), "fg="+filepath.Join(dir, "fg.png"))
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+	want, err := magpie.Draw(bg, bg.Bounds(), fg, image.Point{}, op.BlendOp{Mode: op.Overlay, Compositing: op.CompositeAll}, core.ToNewImage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSamePixels(t, readPNG(t, out), want)
+}
+
+func TestInfo(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "img.png")
+	writeTestImage(t, path, 5, 3, 0)
+
+	code, stdout, stderr := runCommand(t, "info", path)
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+	if want := path + ": png 5x3 NRGBA\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+
+	if code, _, _ := runCommand(t, "info", filepath.Join(dir, "missing.png")); code != 1 {
+		t.Errorf("exit code for a missing file = %d, want 1", code)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"No command", nil, 2},
+		{"Unknown command", []string{"resize"}, 2},
+		{"Help", []string{"help"}, 0},
+		{"Command help", []string{"blend", "-h"}, 0},
+		{"Missing arguments", []string{"blend", "-o", "out.png", "dst.png"}, 2},
+		{"Unknown flag", []string{"composite", "-bogus", "dst.png", "src.png"}, 2},
+		{"Bad rectangle", []string{"blend", "-rect", "1,2,3", "dst.png", "src.png"}, 2},
+		{"Unknown mode", []string{"blend", "-mode", "bogus", "-o", "out.png", "dst.png", "src.png"}, 1},
+		{"Missing output", []string{"blend", "dst.png", "src.png"}, 1},
+		{"Missing save", []string{"recipe", "recipe.json"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _, _ := runCommand(t, tt.args...); code != tt.code {
+				t.Errorf("exit code = %d, want %d", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestModeNames(t *testing.T) {
+	names := blendModeNames()
+	if !contains(names, "normal") || !contains(names, "soft-light-w3c") {
+		t.Errorf("blend mode names = %v, should include normal and soft-light-w3c", names)
+	}
+	if !slices.IsSorted(names) {
+		t.Errorf("blend mode names = %v, should be sorted", names)
+	}
+	if !contains(compositeModeNames(), "source-over") {
+		t.Errorf("composite mode names = %v, should include source-over", compositeModeNames())
+	}
+}
+
+func contains(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Command magpied is an HTTP server that draws layers, such as badges and watermarks, onto images.
+//
+// Usage:
+//
+//	magpied [flags]
+//
+// A draw is requested with POST /draw, either as a JSON body or as a multipart form whose
+// "request" field holds the JSON and whose file parts are the images. The response is the
+// encoded result:
+//
+//	{
+//	  "dst": "product",
+//	  "layers": [
+//	    {"src": "badge", "op": {"composite": "source-over"}, "at": {"X": 10, "Y": 10}},
+//	    {"src": "watermark", "op": {"blend": "screen"}}
+//	  ],
+//	  "inputs": {"badge": "file:badges/sale.png", "watermark": "file:watermark.png"},
+//	  "format": "jpeg"
+//	}
+//
+// Layer operations use the names of magpie.RecipeOp. Inputs refer to files below the directory
+// given with -root. The pixels of the inputs, the duration and the body size of a request are
+// limited, as are expressions (see op.MaxExpressionLength), and all requests share a pool of
+// -workers goroutines.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	root := flag.String("root", "", "directory of local inputs (default: local inputs are disabled)")
+	maxPixels := flag.Int("max-pixels", 50_000_000, "maximum number of pixels of the inputs of a request")
+	maxBody := flag.Int64("max-body", 64<<20, "maximum size of a request body in bytes")
+	timeout := flag.Duration("timeout", 30*time.Second, "maximum duration of a request")
+	workers := flag.Int("workers", runtime.GOMAXPROCS(0), "number of goroutines drawing, shared by all requests")
+	flag.Parse()
+
+	cfg := serverConfig{
+		MaxPixels:   *maxPixels,
+		MaxBodySize: *maxBody,
+		Timeout:     *timeout,
+		Workers:     *workers,
+	}
+	if *root != "" {
+		if info, err := os.Stat(*root); err != nil || !info.IsDir() {
+			log.Fatalf("magpied: -root %s is not a directory", *root)
+		}
+		cfg.Files = os.DirFS(*root)
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newServer(cfg),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("magpied: listening on %s", *addr)
+	log.Fatal(srv.ListenAndServe())
+}
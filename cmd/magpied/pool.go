@@ -0,0 +1,104 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"image/color"
+	"sync"
+	"sync/atomic"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// workerPool bounds the number of goroutines processing rows across all requests.
+type workerPool struct {
+	slots chan struct{}
+}
+
+func newWorkerPool(workers int) *workerPool {
+	return &workerPool{slots: make(chan struct{}, max(workers, 1))}
+}
+
+// tryAcquire takes a slot if one is free.
+func (p *workerPool) tryAcquire() bool {
+	select {
+	case p.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// acquire waits for a free slot until ctx is done.
+func (p *workerPool) acquire(ctx context.Context) error {
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire or tryAcquire.
+func (p *workerPool) release() {
+	<-p.slots
+}
+
+// iterator returns a PixelIterator that runs on the pool and stops processing rows once ctx is
+// done. Callers must check ctx after iterating since the result may then be incomplete.
+func (p *workerPool) iterator(ctx context.Context) core.PixelIterator {
+	return poolIterator{pool: p, ctx: ctx}
+}
+
+// poolIterator distributes the rows of an image over the slots of a workerPool. It waits for one
+// slot and takes as many more as are free, so that a busy server still makes progress on every
+// request without exceeding the pool size. Rows are passed to Calculate relative to the top of the
+// rectangle, as by core.SerialPixelIterator.
+type poolIterator struct {
+	pool *workerPool
+	ctx  context.Context
+}
+
+func (it poolIterator) Iterate(pixCalc core.PixRowCalculator, fn func(dst, src, out []uint8)) {
+	rows := pixCalc.Rect().Dy()
+	var next atomic.Int32
+	work := func() {
+		defer it.pool.release()
+		for it.ctx.Err() == nil {
+			row := int(next.Add(1)) - 1
+			if row >= rows {
+				return
+			}
+			fn(pixCalc.Calculate(row))
+		}
+	}
+
+	if it.pool.acquire(it.ctx) != nil {
+		return
+	}
+	var wg sync.WaitGroup
+	for n := 1; n < min(rows, cap(it.pool.slots)) && it.pool.tryAcquire(); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work()
+		}()
+	}
+	work()
+	wg.Wait()
+}
+
+// requestConfig is the core.Config of a single request.
+type requestConfig struct {
+	pixIter core.PixelIterator
+}
+
+func (c requestConfig) PixelIterator() core.PixelIterator { return c.pixIter }
+
+func (c requestConfig) DefaultOutputMode() core.DefaultOutputMode {
+	return core.DefaultOutputToNewImage
+}
+
+func (c requestConfig) DefaultColorModel() color.Model { return color.NRGBAModel }
@@ -0,0 +1,391 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	magpie "github.com/blazeroni/magpie/pkg"
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+// drawRequest describes a draw: the layers are applied in order on top of the dst image, and the
+// result is encoded in Format.
+//
+// Images are referred to by name. A name is either the name of a file part of a multipart request,
+// or a key of Inputs whose value is the URL of a local file: "file:products/1.png" or a plain
+// relative path. Local files are resolved against the root directory of the server.
+type drawRequest struct {
+	Dst     string            `json:"dst"`
+	Layers  []drawLayer       `json:"layers"`
+	Inputs  map[string]string `json:"inputs,omitempty"`
+	Format  string            `json:"format,omitempty"`
+	Quality int               `json:"quality,omitempty"`
+	Model   string            `json:"model,omitempty"`
+}
+
+// drawLayer draws the image Src with Op, its top-left corner placed at At of the dst image.
+// Pixels outside of Src are treated as transparent.
+type drawLayer struct {
+	Src string          `json:"src"`
+	Op  magpie.RecipeOp `json:"op"`
+	At  image.Point     `json:"at"`
+}
+
+// serverConfig holds the limits of a server.
+type serverConfig struct {
+	// Files holds the local inputs; nil disables them.
+	Files fs.FS
+	// MaxPixels is the maximum number of pixels of the inputs of a request, summed over all of them.
+	MaxPixels int
+	// MaxBodySize is the maximum size of a request body in bytes.
+	MaxBodySize int64
+	// Timeout is the maximum duration of a request, from reading the inputs to drawing.
+	Timeout time.Duration
+	// Workers is the number of goroutines processing rows, shared by all requests.
+	Workers int
+	// Logger logs the errors that can't be reported to the client; nil uses the log package.
+	Logger *log.Logger
+}
+
+// server is the http.Handler of magpied.
+type server struct {
+	cfg  serverConfig
+	pool *workerPool
+	mux  *http.ServeMux
+}
+
+func newServer(cfg serverConfig) *server {
+	s := &server{cfg: cfg, pool: newWorkerPool(cfg.Workers), mux: http.NewServeMux()}
+	if s.cfg.Logger == nil {
+		s.cfg.Logger = log.Default()
+	}
+	s.mux.HandleFunc("POST /draw", s.handleDraw)
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		io.WriteString(w, "ok\n")
+	})
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// statusError is an error reported to the client with an HTTP status code.
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e *statusError) Error() string { return e.err.Error() }
+
+func (e *statusError) Unwrap() error { return e.err }
+
+func errorf(code int, format string, args ...any) error {
+	return &statusError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// writeError reports err to the client.
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	var se *statusError
+	var mbe *http.MaxBytesError
+	switch {
+	case errors.As(err, &se):
+		code = se.code
+	case errors.As(err, &mbe):
+		code = http.StatusRequestEntityTooLarge
+	case errors.Is(err, context.DeadlineExceeded):
+		code = http.StatusServiceUnavailable
+		err = errors.New("request timed out")
+	}
+	http.Error(w, err.Error(), code)
+}
+
+func (s *server) handleDraw(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.cfg.Timeout)
+	defer cancel()
+	r.Body = http.MaxBytesReader(w, r.Body, s.cfg.MaxBodySize)
+
+	img, enc, err := s.draw(ctx, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", enc.contentType)
+	if err := enc.encode(w, img); err != nil {
+		// The status has been sent already.
+		s.cfg.Logger.Printf("magpied: encoding the response: %v", err)
+	}
+}
+
+// draw reads the request, draws it and returns the result with its encoder.
+func (s *server) draw(ctx context.Context, r *http.Request) (image.Image, encoder, error) {
+	req, inputs, err := s.readRequest(r)
+	if err != nil {
+		return nil, encoder{}, err
+	}
+	enc, err := newEncoder(req.Format, req.Quality)
+	if err != nil {
+		return nil, encoder{}, err
+	}
+	output, err := newOutput(req.Model)
+	if err != nil {
+		return nil, encoder{}, err
+	}
+	if req.Dst == "" {
+		return nil, encoder{}, errorf(http.StatusBadRequest, "dst is required")
+	}
+	ops, err := s.buildOps(ctx, req.Layers)
+	if err != nil {
+		return nil, encoder{}, err
+	}
+
+	// Only the images in use are decoded, each one once.
+	loader := imageLoader{inputs: inputs, budget: s.cfg.MaxPixels, images: map[string]image.Image{}}
+	dst, err := loader.load(req.Dst)
+	if err != nil {
+		return nil, encoder{}, err
+	}
+	p := magpie.NewPipeline()
+	for i, l := range req.Layers {
+		src, err := loader.load(l.Src)
+		if err != nil {
+			return nil, encoder{}, err
+		}
+		// The point of src aligned with the top-left corner of dst.
+		p.Draw(ops[i], src, src.Bounds().Min.Sub(l.At.Sub(dst.Bounds().Min)))
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, encoder{}, err
+	}
+
+	img, err := p.RunWith(requestConfig{pixIter: s.pool.iterator(ctx)}, dst, dst.Bounds(), output)
+	if err != nil {
+		return nil, encoder{}, errorf(http.StatusBadRequest, "%v", err)
+	}
+	// The pool stops processing rows once the context is done, leaving the image incomplete.
+	if err := ctx.Err(); err != nil {
+		return nil, encoder{}, err
+	}
+	return img, enc, nil
+}
+
+// buildOps returns the operations of the layers. Compiling expressions takes CPU time, so the
+// expressions are checked against op.MaxExpressionLength first, and the operations are built on
+// a slot of the pool until the deadline of the request.
+func (s *server) buildOps(ctx context.Context, layers []drawLayer) ([]core.Op, error) {
+	for i, l := range layers {
+		if len(l.Op.Expression) > op.MaxExpressionLength {
+			return nil, errorf(http.StatusBadRequest, "layer %d: the expression is longer than %d bytes", i, op.MaxExpressionLength)
+		}
+	}
+	if err := s.pool.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer s.pool.release()
+	ops := make([]core.Op, len(layers))
+	for i, l := range layers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var err error
+		if ops[i], err = l.Op.Build(); err != nil {
+			return nil, errorf(http.StatusBadRequest, "layer %d: %v", i, err)
+		}
+		if !ops[i].IsValid() {
+			return nil, errorf(http.StatusBadRequest, "layer %d: invalid operation", i)
+		}
+	}
+	return ops, nil
+}
+
+// readRequest parses a JSON or multipart request and returns the openers of its inputs by name.
+func (s *server) readRequest(r *http.Request) (*drawRequest, map[string]opener, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil, errorf(http.StatusUnsupportedMediaType, "invalid content type: %v", err)
+	}
+	var req drawRequest
+	inputs := map[string]opener{}
+	switch mediaType {
+	case "application/json":
+		if err := decodeJSON(r.Body, &req); err != nil {
+			return nil, nil, err
+		}
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(s.cfg.MaxBodySize); err != nil {
+			var mbe *http.MaxBytesError
+			if errors.As(err, &mbe) {
+				return nil, nil, err
+			}
+			return nil, nil, errorf(http.StatusBadRequest, "invalid multipart body: %v", err)
+		}
+		if err := decodeJSON(strings.NewReader(r.FormValue("request")), &req); err != nil {
+			return nil, nil, err
+		}
+		for name, files := range r.MultipartForm.File {
+			fh := files[0]
+			inputs[name] = func() (io.ReadCloser, error) { return fh.Open() }
+		}
+	default:
+		return nil, nil, errorf(http.StatusUnsupportedMediaType, "unsupported content type %q; use application/json or multipart/form-data", mediaType)
+	}
+
+	for name, ref := range req.Inputs {
+		if _, ok := inputs[name]; ok {
+			return nil, nil, errorf(http.StatusBadRequest, "input %q is given twice", name)
+		}
+		p, err := s.localPath(ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		inputs[name] = func() (io.ReadCloser, error) { return s.cfg.Files.Open(p) }
+	}
+	return &req, inputs, nil
+}
+
+func decodeJSON(r io.Reader, req *drawRequest) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(req); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			return err
+		}
+		return errorf(http.StatusBadRequest, "invalid request: %v", err)
+	}
+	return nil
+}
+
+// localPath returns the path in Files of a local input URL.
+func (s *server) localPath(ref string) (string, error) {
+	if s.cfg.Files == nil {
+		return "", errorf(http.StatusBadRequest, "local inputs are disabled")
+	}
+	u, err := url.Parse(ref)
+	if err != nil || (u.Scheme != "" && u.Scheme != "file") || u.Host != "" {
+		return "", errorf(http.StatusBadRequest, "invalid input URL %q; use file:path", ref)
+	}
+	p := u.Path
+	if u.Opaque != "" {
+		p = u.Opaque
+	}
+	p = strings.TrimPrefix(p, "/")
+	if !fs.ValidPath(p) || p == "." {
+		return "", errorf(http.StatusBadRequest, "invalid input path %q", ref)
+	}
+	return p, nil
+}
+
+// opener opens an input; it may be called more than once.
+type opener func() (io.ReadCloser, error)
+
+// imageLoader decodes the inputs of a request within its pixel budget.
+type imageLoader struct {
+	inputs map[string]opener
+	budget int
+	images map[string]image.Image
+}
+
+func (l *imageLoader) load(name string) (image.Image, error) {
+	if img, ok := l.images[name]; ok {
+		return img, nil
+	}
+	open, ok := l.inputs[name]
+	if !ok {
+		return nil, errorf(http.StatusBadRequest, "unknown input %q", name)
+	}
+
+	// The size is checked before decoding so that large images are not allocated.
+	cfg, err := decode(open, image.DecodeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("input %q: %w", name, err)
+	}
+	pixels := cfg.Width * cfg.Height
+	if pixels > l.budget {
+		return nil, errorf(http.StatusRequestEntityTooLarge, "input %q: the inputs exceed the limit of pixels", name)
+	}
+	l.budget -= pixels
+	img, err := decode(open, image.Decode)
+	if err != nil {
+		return nil, fmt.Errorf("input %q: %w", name, err)
+	}
+	l.images[name] = img
+	return img, nil
+}
+
+// decode opens an input and decodes it with fn.
+func decode[T any](open opener, fn func(io.Reader) (T, string, error)) (T, error) {
+	var zero T
+	rc, err := open()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return zero, errorf(http.StatusNotFound, "%v", err)
+		}
+		return zero, err
+	}
+	defer rc.Close()
+	v, _, err := fn(rc)
+	if err != nil {
+		return zero, errorf(http.StatusBadRequest, "decoding: %v", err)
+	}
+	return v, nil
+}
+
+// encoder encodes the result of a request.
+type encoder struct {
+	contentType string
+	encode      func(w io.Writer, img image.Image) error
+}
+
+func newEncoder(format string, quality int) (encoder, error) {
+	switch strings.ToLower(format) {
+	case "", "png":
+		return encoder{"image/png", png.Encode}, nil
+	case "jpeg", "jpg":
+		if quality == 0 {
+			quality = jpeg.DefaultQuality
+		}
+		return encoder{"image/jpeg", func(w io.Writer, img image.Image) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+		}}, nil
+	case "gif":
+		return encoder{"image/gif", func(w io.Writer, img image.Image) error {
+			return gif.Encode(w, img, nil)
+		}}, nil
+	default:
+		return encoder{}, errorf(http.StatusBadRequest, "unsupported format %q; use png, jpeg or gif", format)
+	}
+}
+
+// newOutput returns an output creating a new image of the named color model.
+func newOutput(model string) (core.Output, error) {
+	switch strings.ToLower(model) {
+	case "":
+		return core.ToNewImage(), nil
+	case "nrgba":
+		return core.ToNewNRGBAImage(), nil
+	case "rgba":
+		return core.ToNewRGBAImage(), nil
+	default:
+		return nil, errorf(http.StatusBadRequest, "unknown color model %q; use nrgba or rgba", model)
+	}
+}
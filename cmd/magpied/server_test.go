@@ -0,0 +1,356 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	magpie "github.com/blazeroni/magpie/pkg"
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+func testImage(w, h int, shift uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x*16) + shift, uint8(y * 16), shift, uint8(96 + x*8)})
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func testServer(t *testing.T, cfg serverConfig) *httptest.Server {
+	t.Helper()
+	if cfg.MaxPixels == 0 {
+		cfg.MaxPixels = 1 << 20
+	}
+	if cfg.MaxBodySize == 0 {
+		cfg.MaxBodySize = 1 << 20
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Workers == 0 {
+		cfg.Workers = 2
+	}
+	cfg.Logger = log.New(io.Discard, "", 0)
+	ts := httptest.NewServer(newServer(cfg))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// expected draws the layers the way the server should.
+func expected(t *testing.T, dst image.Image, layers ...func(dst image.Image) error) image.Image {
+	t.Helper()
+	out := image.NewNRGBA(dst.Bounds())
+	draw.Draw(out, out.Rect, dst, out.Rect.Min, draw.Src)
+	for _, l := range layers {
+		if err := l(out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return out
+}
+
+func assertImage(t *testing.T, resp *http.Response, want image.Image) {
+	t.Helper()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("status = %d: %s", resp.StatusCode, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", ct)
+	}
+	got, err := png.Decode(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Bounds() != want.Bounds() {
+		t.Fatalf("bounds = %v, want %v", got.Bounds(), want.Bounds())
+	}
+	b := want.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			g, w := color.NRGBAModel.Convert(got.At(x, y)), color.NRGBAModel.Convert(want.At(x, y))
+			if g != w {
+				t.Fatalf("pixel (%d,%d) = %v, want %v", x, y, g, w)
+			}
+		}
+	}
+}
+
+func TestDrawMultipart(t *testing.T) {
+	ts := testServer(t, serverConfig{})
+	dst, badge := testImage(16, 12, 0), testImage(4, 4, 60)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("request", `{
+		"dst": "product",
+		"layers": [
+			{"src": "badge", "op": {"blend": "multiply"}, "at": {"X": 10, "Y": 6}},
+			{"src": "badge", "op": {"composite": "source-over"}, "at": {"X": -2, "Y": -1}}
+		]
+	}`)
+	for name, img := range map[string]image.Image{"product": dst, "badge": badge} {
+		fw, err := mw.CreateFormFile(name, name+".png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(encodePNG(t, img))
+	}
+	mw.Close()
+
+	resp, err := http.Post(ts.URL+"/draw", mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := expected(t, dst,
+		func(out image.Image) error {
+			_, err := magpie.Draw(out, image.Rect(10, 6, 14, 10), badge, image.Point{},
+				op.BlendOp{Mode: op.Multiply, Compositing: op.CompositeAll}, magpie.ToDst())
+			return err
+		},
+		func(out image.Image) error {
+			_, err := magpie.Draw(out, image.Rect(0, 0, 2, 3), badge, image.Pt(2, 1),
+				op.CompositeOp{Mode: op.SourceOver}, magpie.ToDst())
+			return err
+		})
+	assertImage(t, resp, want)
+}
+
+func TestDrawLocalInputs(t *testing.T) {
+	dst, mark := testImage(8, 8, 0), testImage(8, 8, 90)
+	files := fstest.MapFS{
+		"products/1.png": {Data: encodePNG(t, dst)},
+		"watermark.png":  {Data: encodePNG(t, mark)},
+	}
+	ts := testServer(t, serverConfig{Files: files})
+
+	resp, err := http.Post(ts.URL+"/draw", "application/json", strings.NewReader(`{
+		"dst": "product",
+		"layers": [{"src": "mark", "op": {"blend": "screen", "compositing": "blend-only"}}],
+		"inputs": {"product": "file:products/1.png", "mark": "file:///watermark.png"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := expected(t, dst, func(out image.Image) error {
+		_, err := magpie.Draw(out, out.Bounds(), mark, image.Point{},
+			op.BlendOp{Mode: op.Screen, Compositing: op.CompositeBlendOnly}, magpie.ToDst())
+		return err
+	})
+	assertImage(t, resp, want)
+}
+
+func TestDrawErrors(t *testing.T) {
+	small := encodePNG(t, testImage(8, 8, 0))
+	files := fstest.MapFS{
+		"a.png":    {Data: small},
+		"big.png":  {Data: encodePNG(t, testImage(64, 64, 0))},
+		"text.png": {Data: []byte("not an image")},
+	}
+	ts := testServer(t, serverConfig{Files: files, MaxPixels: 150, MaxBodySize: 1024})
+
+	tests := []struct {
+		name, contentType, body string
+		code                    int
+	}{
+		{"Invalid JSON", "application/json", `{"dst": `, http.StatusBadRequest},
+		{"Unknown field", "application/json", `{"dst": "a", "size": 1}`, http.StatusBadRequest},
+		{"Content type", "text/plain", `{}`, http.StatusUnsupportedMediaType},
+		{"Missing dst", "application/json", `{"inputs": {"a": "a.png"}}`, http.StatusBadRequest},
+		{"Unknown input", "application/json", `{"dst": "b", "inputs": {"a": "a.png"}}`, http.StatusBadRequest},
+		{"Unknown mode", "application/json", `{"dst": "a", "layers": [{"src": "a", "op": {"blend": "bogus"}}], "inputs": {"a": "a.png"}}`, http.StatusBadRequest},
+		{"No op", "application/json", `{"dst": "a", "layers": [{"src": "a", "op": {}}], "inputs": {"a": "a.png"}}`, http.StatusBadRequest},
+		{"Format", "application/json", `{"dst": "a", "format": "bmp", "inputs": {"a": "a.png"}}`, http.StatusBadRequest},
+		{"Model", "application/json", `{"dst": "a", "model": "gray", "inputs": {"a": "a.png"}}`, http.StatusBadRequest},
+		{"Escaping root", "application/json", `{"dst": "a", "inputs": {"a": "file:../a.png"}}`, http.StatusBadRequest},
+		{"Remote URL", "application/json", `{"dst": "a", "inputs": {"a": "https://example.com/a.png"}}`, http.StatusBadRequest},
+		{"Missing file", "application/json", `{"dst": "a", "inputs": {"a": "missing.png"}}`, http.StatusNotFound},
+		{"Not an image", "application/json", `{"dst": "a", "inputs": {"a": "text.png"}}`, http.StatusBadRequest},
+		{"Pixel limit", "application/json", `{"dst": "a", "inputs": {"a": "big.png"}}`, http.StatusRequestEntityTooLarge},
+		{"Pixel limit of all inputs", "application/json", `{"dst": "a", "layers": [{"src": "a2", "op": {"blend": "normal"}}, {"src": "a3", "op": {"blend": "normal"}}], "inputs": {"a": "a.png", "a2": "a.png", "a3": "a.png"}}`, http.StatusRequestEntityTooLarge},
+		{"Within limits", "application/json", `{"dst": "a", "layers": [{"src": "a2", "op": {"blend": "normal"}}], "inputs": {"a": "a.png", "a2": "a.png"}}`, http.StatusOK},
+		{"Body size", "application/json", `{"dst": "a", "format": "` + strings.Repeat("x", 2048) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(ts.URL+"/draw", tt.contentType, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.code {
+				t.Errorf("status = %d, want %d: %s", resp.StatusCode, tt.code, body)
+			}
+		})
+	}
+
+	t.Run("Method", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/draw")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func TestDrawExpressionLimits(t *testing.T) {
+	files := fstest.MapFS{"a.png": {Data: encodePNG(t, testImage(8, 8, 0))}}
+	ts := testServer(t, serverConfig{Files: files, MaxBodySize: 16 << 20})
+	request := func(expr string) string {
+		return `{"dst": "a", "layers": [{"src": "a", "op": {"expression": "` + expr + `"}}], "inputs": {"a": "a.png"}}`
+	}
+	nested := func(depth int) string {
+		return strings.Repeat("(", depth) + "$S" + strings.Repeat(")", depth)
+	}
+
+	tests := []struct {
+		name, expr string
+		code       int
+	}{
+		{"Valid", "255 - $D", http.StatusOK},
+		{"Nested within limits", nested(op.MaxExpressionDepth), http.StatusOK},
+		{"Nested too deep", nested(op.MaxExpressionDepth + 1), http.StatusBadRequest},
+		{"Huge nesting", strings.Repeat("(", 8_000_000), http.StatusBadRequest},
+		{"Too long", strings.Repeat("$S+", 100_000) + "$S", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			resp, err := http.Post(ts.URL+"/draw", "application/json", strings.NewReader(request(tt.expr)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.code {
+				t.Errorf("status = %d, want %d: %.200s", resp.StatusCode, tt.code, body)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("the request took %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestDrawTimeout(t *testing.T) {
+	files := fstest.MapFS{"a.png": {Data: encodePNG(t, testImage(8, 8, 0))}}
+	ts := testServer(t, serverConfig{Files: files, Timeout: time.Nanosecond})
+
+	resp, err := http.Post(ts.URL+"/draw", "application/json", strings.NewReader(`{"dst": "a", "inputs": {"a": "a.png"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+}
+
+// rowCounter is a PixRowCalculator recording the rows it is asked for.
+type rowCounter struct {
+	rect image.Rectangle
+	mu   sync.Mutex
+	rows map[int]int
+}
+
+func (c *rowCounter) Rect() image.Rectangle { return c.rect }
+
+func (c *rowCounter) Calculate(row int) ([]uint8, []uint8, []uint8) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rows[row]++
+	return nil, nil, nil
+}
+
+func TestPoolIterator(t *testing.T) {
+	pool := newWorkerPool(3)
+
+	t.Run("All rows once", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c := &rowCounter{rect: image.Rect(0, 5, 1, 105), rows: map[int]int{}}
+				pool.iterator(context.Background()).Iterate(c, func(_, _, _ []uint8) {})
+				if len(c.rows) != 100 {
+					t.Errorf("%d rows were processed, want 100", len(c.rows))
+				}
+				for row, n := range c.rows {
+					if row < 0 || row >= 100 || n != 1 {
+						t.Errorf("row %d was processed %d times", row, n)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		if n := len(pool.slots); n != 0 {
+			t.Errorf("%d slots are still taken", n)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		c := &rowCounter{rect: image.Rect(0, 0, 1, 100), rows: map[int]int{}}
+		n := 0
+		pool.iterator(ctx).Iterate(c, func(_, _, _ []uint8) {
+			if n++; n == 10 {
+				cancel()
+			}
+		})
+		if len(c.rows) >= 100 {
+			t.Error("rows should not be processed after the context is canceled")
+		}
+	})
+
+	t.Run("Draw", func(t *testing.T) {
+		dst, src := testImage(8, 8, 0), testImage(8, 8, 40)
+		cfg := requestConfig{pixIter: pool.iterator(context.Background())}
+		got, err := magpie.NewPipeline().Draw(op.BlendOp{Mode: op.Overlay, Compositing: op.CompositeAll}, src, image.Point{}).
+			RunWith(cfg, dst, dst.Bounds(), core.ToNewImage())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := magpie.Draw(dst, dst.Bounds(), src, image.Point{}, op.BlendOp{Mode: op.Overlay, Compositing: op.CompositeAll}, core.ToNewImage())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.(*image.NRGBA).Pix, want.(*image.NRGBA).Pix) {
+			t.Error("the pool iterator should draw the same image as the default one")
+		}
+	})
+}
@@ -21,6 +21,7 @@ var funcsTemplateFS embed.FS
 type TemplateData struct {
 	Name                      string
 	KernelR, KernelG, KernelB string
+	Params                    string
 	KernelDocs                string
 }
 
@@ -54,7 +55,18 @@ func processTemplates(modes []shared.BlendTemplate) []TemplateData {
 	channels := []string{"R", "G", "B"}
 
 	for i, mode := range modes {
-		td := TemplateData{Name: mode.Name, KernelDocs: mode.KernelDocs}
+		td := TemplateData{Name: mode.Name, Params: mode.Params, KernelDocs: mode.KernelDocs}
+
+		if mode.PixelKernel != "" {
+			// Process Pixel Kernel Template (non-premultiplied, all channels at once)
+			k := mode.PixelKernel
+			k = strings.ReplaceAll(k, "$S", "s")
+			k = strings.ReplaceAll(k, "$D", "d")
+			k = strings.ReplaceAll(k, "$R", "o")
+			td.KernelR = k
+			data[i] = td
+			continue
+		}
 
 		for _, ch := range channels {
 			// Process Kernel Template (non-premultiplied)
@@ -32,6 +32,8 @@ type TemplateData struct {
 	Name                            string
 	KernelR, KernelG, KernelB       string
 	EquationR, EquationG, EquationB string
+	PixelEquation                   string
+	Params                          string
 	KernelDocs                      string
 }
 
@@ -68,7 +70,29 @@ func processTemplates(modes []shared.BlendTemplate) []TemplateData {
 	channels := []string{"R", "G", "B"}
 
 	for i, mode := range modes {
-		td := TemplateData{Name: mode.Name, KernelDocs: mode.KernelDocs}
+		td := TemplateData{Name: mode.Name, Params: mode.Params, KernelDocs: mode.KernelDocs}
+
+		if mode.PixelKernel != "" {
+			// Process Pixel Kernel Template (non-premultiplied, all channels at once)
+			k := mode.PixelKernel
+			k = strings.ReplaceAll(k, "$S", "s")
+			k = strings.ReplaceAll(k, "$D", "d")
+			k = strings.ReplaceAll(k, "$R", "k")
+			td.KernelR = k
+
+			// Process Pixel Equation Template (premultiplied, all channels at once)
+			eq := mode.PixelEquationRGBA
+			for _, ch := range channels {
+				eq = strings.ReplaceAll(eq, "$Rp"+ch, "o"+ch+"p")
+			}
+			eq = strings.ReplaceAll(eq, "$Sp", "s")
+			eq = strings.ReplaceAll(eq, "$Dp", "d")
+			eq = strings.ReplaceAll(eq, "$sA", "sA")
+			eq = strings.ReplaceAll(eq, "$dA", "dA")
+			td.PixelEquation = eq
+			data[i] = td
+			continue
+		}
 
 		for _, ch := range channels {
 			// Process Kernel Template (non-premultiplied)
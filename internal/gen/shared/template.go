@@ -4,10 +4,13 @@
 package shared
 
 type BlendTemplate struct {
-	Name         string
-	Kernel       string
-	EquationRGBA string
-	KernelDocs   string
+	Name              string
+	Kernel            string
+	EquationRGBA      string
+	PixelKernel       string
+	PixelEquationRGBA string
+	Params            string
+	KernelDocs        string
 }
 
 // BlendTemplates is the list of all the blend modes that can be generated.
@@ -19,6 +22,10 @@ type BlendTemplate struct {
 // Both the kernel and equation are code snippets for color-channel calculations with specific placeholders.
 // The placeholders are repeated in the output for each color channel (RGB).
 //
+// Modes that are not separable use a pixel kernel and an optional pixel equation instead. These are
+// code snippets for a whole pixel, expanded once; each placeholder is followed by the channel
+// it refers to, e.g. $SR or $DpB.
+//
 // Kernel placeholders:
 // $R: the result/output color channel
 // $S: the source color channel
@@ -30,12 +37,32 @@ type BlendTemplate struct {
 // $sA: the source alpha channel
 // $dA: the destination alpha channel
 //
+// Pixel equation placeholders are those of the equation, plus:
+// $Rp: the premultiplied result color channel of source over blending
+//
+// Params optionally declares extra parameters of the generated function that the kernel can use,
+// e.g. ", lut *[256][256]uint8".
+//
 // Available optimized custom helper functions:
 // md255: multiply two values and divide by 255; input must be [0, 255]
 // sqrt: square root of a color channel; input must be [0, 255]
+// isqrt: exact integer square root; input must be [0, 255*255]
 // div255: divide value by 255 (approximated)
 // unpremultiply: un-premultiply a color channel
+// softLightD: the W3C soft light D(Cb) function of a color channel
+// softLightIllusions: the illusions.hu soft light of a source and destination color channel
+// lum: the luminosity of a color scaled by 100; inputs must be [0, 255]
 var BlendTemplates = []BlendTemplate{
+	{
+		Name:       "AdditiveSubtractive",
+		Kernel:     "$R = sqrt($D) - sqrt($S); if $R > 255 { $R = sqrt($S) - sqrt($D) }",
+		KernelDocs: "Cr = abs(sqrt(Cd) - sqrt(Cs))",
+	},
+	{
+		Name:       "Average",
+		Kernel:     "$R = ($S + $D + 1) / 2",
+		KernelDocs: "Cr = (Cs + Cd) / 2",
+	},
 	{
 		Name:       "ColorBurn",
 		Kernel:     "if $S + $D <= 255 { $R = 0 } else { $R = 255 - ((255 - $D) * 255 + $S/2) / $S }",
@@ -51,6 +78,16 @@ var BlendTemplates = []BlendTemplate{
 		Kernel:     "$R = min($S, $D)",
 		KernelDocs: "Cr = min(Cs, Cd)",
 	},
+	{
+		Name: "DarkerColor",
+		PixelKernel: "if lum($SR, $SG, $SB) < lum($DR, $DG, $DB) { $RR, $RG, $RB = $SR, $SG, $SB } " +
+			"else { $RR, $RG, $RB = $DR, $DG, $DB }",
+		PixelEquationRGBA: "if lum($SpR, $SpG, $SpB)*$dA < lum($DpR, $DpG, $DpB)*$sA { " +
+			"$RpR, $RpG, $RpB = $SpR+md255($DpR, 255-$sA), $SpG+md255($DpG, 255-$sA), $SpB+md255($DpB, 255-$sA) " +
+			"} else { " +
+			"$RpR, $RpG, $RpB = md255($SpR, 255-$dA)+$DpR, md255($SpG, 255-$dA)+$DpG, md255($SpB, 255-$dA)+$DpB }",
+		KernelDocs: "if Lum(Cs) < Lum(Cd) { Cr = Cs } else { Cr = Cd }, where Lum(C) = 0.3*R + 0.59*G + 0.11*B",
+	},
 	{
 		Name:         "Difference",
 		Kernel:       "$R = min($S - $D, $D - $S)",
@@ -68,6 +105,31 @@ var BlendTemplates = []BlendTemplate{
 		KernelDocs:   "Cr = Cs + Cd - 2 * Cs * Cd",
 		EquationRGBA: "$Sp + $Dp - 2*md255($Sp, $Dp)",
 	},
+	{
+		Name:       "Freeze",
+		Kernel:     "if $S == 0 { $R = 0 } else { $R = 255 - min(((255-$D)*(255-$D) + $S/2) / $S, 255) }",
+		KernelDocs: "Cr = 1 - (1 - Cd)^2 / Cs",
+	},
+	{
+		Name:       "GeometricMean",
+		Kernel:     "$R = isqrt($S * $D)",
+		KernelDocs: "Cr = sqrt(Cs * Cd)",
+	},
+	{
+		Name:       "Glow",
+		Kernel:     "if $D == 255 { $R = 255 } else { $R = min(($S*$S + (255-$D)/2) / (255 - $D), 255) }",
+		KernelDocs: "Cr = Cs^2 / (1 - Cd)",
+	},
+	{
+		Name:       "GrainExtract",
+		Kernel:     "$R = uint32(max(min(int($D) - int($S) + 128, 255), 0))",
+		KernelDocs: "Cr = Cd - Cs + 0.5",
+	},
+	{
+		Name:       "GrainMerge",
+		Kernel:     "$R = uint32(max(min(int($D) + int($S) - 128, 255), 0))",
+		KernelDocs: "Cr = Cd + Cs - 0.5",
+	},
 	{
 		Name:       "HardLight",
 		Kernel:     "if $S < 128 { $R = 2 * md255($S, $D) } else { $R = 255 - 2*md255(255-$S, 255-$D) }",
@@ -78,11 +140,32 @@ var BlendTemplates = []BlendTemplate{
 		Kernel:     "if $S + $D < 255 { $R = 0 } else { $R = 255 }",
 		KernelDocs: "if Cs + Cd < 1 { Cr = 0 } else { Cr = 1 }",
 	},
+	{
+		Name:       "Heat",
+		Kernel:     "if $D == 0 { $R = 0 } else { $R = 255 - min(((255-$S)*(255-$S) + $D/2) / $D, 255) }",
+		KernelDocs: "Cr = 1 - (1 - Cs)^2 / Cd",
+	},
+	{
+		Name:       "LUT",
+		Kernel:     "$R = uint32(lut[$S][$D])",
+		Params:     ", lut *[256][256]uint8",
+		KernelDocs: "Cr = lut[Cs][Cd], a blend function precomputed for every pair of 8-bit values",
+	},
 	{
 		Name:       "Lighten",
 		Kernel:     "$R = max($S, $D)",
 		KernelDocs: "Cr = max(Cs, Cd)",
 	},
+	{
+		Name: "LighterColor",
+		PixelKernel: "if lum($SR, $SG, $SB) > lum($DR, $DG, $DB) { $RR, $RG, $RB = $SR, $SG, $SB } " +
+			"else { $RR, $RG, $RB = $DR, $DG, $DB }",
+		PixelEquationRGBA: "if lum($SpR, $SpG, $SpB)*$dA > lum($DpR, $DpG, $DpB)*$sA { " +
+			"$RpR, $RpG, $RpB = $SpR+md255($DpR, 255-$sA), $SpG+md255($DpG, 255-$sA), $SpB+md255($DpB, 255-$sA) " +
+			"} else { " +
+			"$RpR, $RpG, $RpB = md255($SpR, 255-$dA)+$DpR, md255($SpG, 255-$dA)+$DpG, md255($SpB, 255-$dA)+$DpB }",
+		KernelDocs: "if Lum(Cs) > Lum(Cd) { Cr = Cs } else { Cr = Cd }, where Lum(C) = 0.3*R + 0.59*G + 0.11*B",
+	},
 	{
 		Name:       "LinearBurn",
 		Kernel:     "$R = $S + $D - 255; if $R > 255 { $R = 0 }",
@@ -104,16 +187,37 @@ var BlendTemplates = []BlendTemplate{
 		KernelDocs:   "Cr = Cs * Cd",
 		EquationRGBA: "md255($Dp, 255-$sA) + md255($Sp, 255-$dA) + md255($Sp, $Dp)",
 	},
+	{
+		Name:       "Negation",
+		Kernel:     "if $S + $D > 255 { $R = 510 - $S - $D } else { $R = $S + $D }",
+		KernelDocs: "Cr = 1 - abs(1 - Cs - Cd)",
+	},
+	{
+		Name:         "Normal",
+		Kernel:       "$R = $S",
+		KernelDocs:   "Cr = Cs",
+		EquationRGBA: "$Sp + md255($Dp, 255-$sA)",
+	},
 	{
 		Name:       "Overlay",
 		Kernel:     "if $D < 128 { $R = 2 * md255($S, $D) } else { $R = 255 - 2*md255(255-$S, 255-$D) }",
 		KernelDocs: "if Cd < 0.5 { Cr = 2 * Cs * Cd } else { Cr = 1 - 2 * (1 - Cs) * (1 - Cd) }",
 	},
+	{
+		Name:       "Phoenix",
+		Kernel:     "$R = min($S, $D) + 255 - max($S, $D)",
+		KernelDocs: "Cr = min(Cs, Cd) - max(Cs, Cd) + 1",
+	},
 	{
 		Name:       "PinLight",
 		Kernel:     "if $S < 128 { $R = min($D, 2 * $S) } else { $R = max($D, 2*$S-255) }",
 		KernelDocs: "if Cs < 0.5 { Cr = min(Cd, 2 * Cs) } else { Cr = max(Cd, 2 * Cs - 1) }",
 	},
+	{
+		Name:       "Reflect",
+		Kernel:     "if $S == 255 { $R = 255 } else { $R = min(($D*$D + (255-$S)/2) / (255 - $S), 255) }",
+		KernelDocs: "Cr = Cd^2 / (1 - Cs)",
+	},
 	{
 		Name:         "Screen",
 		Kernel:       "$R = 255 - md255(255-$S, 255-$D)",
@@ -125,6 +229,21 @@ var BlendTemplates = []BlendTemplate{
 		Kernel:     "if $S < 128 { $R = $D - div255(div255((255 - 2*$S) * $D * (255 - $D))) } else { $R = $D + md255(2*$S - 255, sqrt($D) - $D) }",
 		KernelDocs: "if Cs < 0.5 { Cr = Cd - (1 - 2*Cs) * Cd * (1 - Cd) } else { Cr = Cd + (2*Cs - 1) * (sqrt(Cd) - Cd) }",
 	},
+	{
+		Name:       "SoftLightIllusions",
+		Kernel:     "$R = softLightIllusions($S, $D)",
+		KernelDocs: "Cr = Cd^(2^(2 * (0.5 - Cs)))",
+	},
+	{
+		Name:       "SoftLightPegtop",
+		Kernel:     "$R = ($D*$D*(255 - 2*$S) + 2*$S*$D*255 + 32512) / 65025",
+		KernelDocs: "Cr = (1 - 2*Cs) * Cd^2 + 2 * Cs * Cd",
+	},
+	{
+		Name:       "SoftLightW3C",
+		Kernel:     "if $S < 128 { $R = $D - div255(div255((255 - 2*$S) * $D * (255 - $D))) } else { $R = $D + md255(2*$S - 255, softLightD($D) - $D) }",
+		KernelDocs: "if Cs <= 0.5 { Cr = Cd - (1 - 2*Cs) * Cd * (1 - Cd) } else { Cr = Cd + (2*Cs - 1) * (D(Cd) - Cd) }, where D(x) = ((16*x - 12)*x + 4)*x if x <= 0.25 else sqrt(x)",
+	},
 	{
 		Name:       "Subtract",
 		Kernel:     "$R = uint32(max(int($D) - int($S), 0))",
@@ -138,4 +257,13 @@ var BlendTemplates = []BlendTemplate{
 			"default: $R = min((($D * 255) + (255 - $S)) / (510 - 2 * $S), 255) }",
 		KernelDocs: "if Cs < 0.5 { Cr = 1 - (1 - Cd) / (2 * Cs) } else { Cr = Cd / (2 * (1 - Cs)) }",
 	},
+	{
+		Name: "VividLightGIMP",
+		Kernel: "switch { " +
+			"case $S == 0: $R = 255; " +
+			"case $S == 255: $R = 0; " +
+			"case $S < 128: $R = 255 - min(((255 - $D) * 255 + $S) / (2 * $S), 255); " +
+			"default: $R = min((($D * 255) + (255 - $S)) / (510 - 2 * $S), 255) }",
+		KernelDocs: "if Cs <= 0.5 { Cr = 1 - (1 - Cd) / (2 * Cs) } else { Cr = Cd / (2 * (1 - Cs)) }, where x / 0 = 0",
+	},
 }
@@ -13,6 +13,25 @@ import (
 // It iterates over pixels in an image an passes each row to a PixRowCalculator.
 type PixelIterator = core.PixelIterator
 
+// Op is an alias for core.Op.
+// It is the operation applied by Draw; see core.Op for the contract of implementations.
+type Op = core.Op
+
+// SourceWrap is an alias for core.SourceWrap.
+// It describes how DrawWrapped extends a source beyond its bounds.
+type SourceWrap = core.SourceWrap
+
+// WrapMode is an alias for core.WrapMode.
+type WrapMode = core.WrapMode
+
+// Source wrap modes; see core.WrapMode.
+const (
+	WrapNone   = core.WrapNone
+	WrapRepeat = core.WrapRepeat
+	WrapMirror = core.WrapMirror
+	WrapClamp  = core.WrapClamp
+)
+
 // PixCalculator calculates the set of Pix slices for a given row.
 type PixCalculator[T image.Image] = core.PixCalculator[T]
 type PixRowCalculator = core.PixRowCalculator
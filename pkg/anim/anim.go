@@ -0,0 +1,193 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package anim draws on animated GIFs and PNGs frame by frame.
+//
+// A GIF stores most frames as patches over the previous ones, so an op can't be applied to the
+// frames as they are decoded. FromGIF reconstructs the full frames instead, honoring the disposal
+// methods and transparency of the file, Draw applies an op to every frame, and GIF quantizes the
+// frames back to palettes:
+//
+//	a, err := anim.FromGIF(g)
+//	if err != nil {
+//		return err
+//	}
+//	err = a.Draw(magpie.DefaultContext(), op.CompositeOp{Mode: op.SourceOver}, anim.Static(logo, image.Pt(8, 8)))
+//	if err != nil {
+//		return err
+//	}
+//	return gif.EncodeAll(w, a.GIF(nil))
+//
+// Animated PNGs are read with DecodeAPNG and written with EncodeAPNG. They keep 8-bit colors and
+// alpha, so no quantization is needed.
+package anim
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// Animation is a sequence of full frames.
+type Animation struct {
+	// Frames holds the frames, which all have the bounds of the canvas starting at the origin.
+	Frames []*image.NRGBA
+	// Delay holds the delay of every frame in hundredths of a second.
+	Delay []int
+	// LoopCount follows gif.GIF: 0 loops forever, -1 shows the frames once and n > 0 shows
+	// them n+1 times.
+	LoopCount int
+}
+
+// FromGIF reconstructs the full frames of g. Every frame is drawn over the canvas left by the
+// previous one and then disposed of as it specifies: DisposalBackground clears its rectangle to
+// transparent, as browsers do, and DisposalPrevious restores the canvas it was drawn on. The
+// canvas has the size of g.Config, or covers all frames if that is empty.
+func FromGIF(g *gif.GIF) (*Animation, error) {
+	if g == nil || len(g.Image) == 0 {
+		return nil, errors.New("gif has no frames")
+	}
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		bounds = image.Rectangle{}
+		for _, f := range g.Image {
+			if f == nil {
+				return nil, errors.New("gif frame is nil")
+			}
+			bounds.Max = image.Pt(max(bounds.Max.X, f.Rect.Max.X), max(bounds.Max.Y, f.Rect.Max.Y))
+		}
+	}
+
+	a := &Animation{
+		Frames:    make([]*image.NRGBA, len(g.Image)),
+		Delay:     make([]int, len(g.Image)),
+		LoopCount: g.LoopCount,
+	}
+	copy(a.Delay, g.Delay)
+	canvas := image.NewNRGBA(bounds)
+	var saved *image.NRGBA
+	for i, f := range g.Image {
+		if f == nil {
+			return nil, fmt.Errorf("gif frame %d is nil", i)
+		}
+		var disposal byte
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			saved = cloneNRGBA(saved, canvas)
+		}
+		drawPaletted(canvas, f)
+		a.Frames[i] = cloneNRGBA(nil, canvas)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, f.Rect, image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, saved.Pix)
+		}
+	}
+	return a, nil
+}
+
+// drawPaletted draws the opaque pixels of f over canvas; GIF pixels are either opaque or transparent.
+func drawPaletted(canvas *image.NRGBA, f *image.Paletted) {
+	palette := make([]color.NRGBA, len(f.Palette))
+	for i, c := range f.Palette {
+		palette[i] = color.NRGBAModel.Convert(c).(color.NRGBA)
+	}
+	r := f.Rect.Intersect(canvas.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		src := f.Pix[f.PixOffset(r.Min.X, y):]
+		dst := canvas.Pix[canvas.PixOffset(r.Min.X, y):]
+		for x := 0; x < r.Dx(); x++ {
+			idx := int(src[x])
+			if idx >= len(palette) || palette[idx].A == 0 {
+				continue
+			}
+			c := palette[idx]
+			dst[x*4], dst[x*4+1], dst[x*4+2], dst[x*4+3] = c.R, c.G, c.B, c.A
+		}
+	}
+}
+
+// cloneNRGBA copies src into dst, allocating dst if it is nil.
+func cloneNRGBA(dst, src *image.NRGBA) *image.NRGBA {
+	if dst == nil {
+		dst = image.NewNRGBA(src.Rect)
+	}
+	copy(dst.Pix, src.Pix)
+	return dst
+}
+
+// Layer returns the source drawn on a frame and the position of its top-left corner on the
+// canvas. A nil source leaves the frame unchanged.
+type Layer func(frame int) (src image.Image, at image.Point)
+
+// Static returns a layer drawing src at the same position on every frame.
+func Static(src image.Image, at image.Point) Layer {
+	return func(int) (image.Image, image.Point) { return src, at }
+}
+
+// Sequence returns a layer drawing srcs in turn at the same position, e.g. the frames of an
+// animated overlay. The sequence restarts when the animation has more frames than srcs.
+func Sequence(srcs []image.Image, at image.Point) Layer {
+	return func(frame int) (image.Image, image.Point) {
+		if len(srcs) == 0 {
+			return nil, at
+		}
+		return srcs[frame%len(srcs)], at
+	}
+}
+
+// Path returns a layer drawing src at path[i] on frame i. The path restarts when the animation
+// has more frames than points.
+func Path(src image.Image, path []image.Point) Layer {
+	return func(frame int) (image.Image, image.Point) {
+		if len(path) == 0 {
+			return nil, image.Point{}
+		}
+		return src, path[frame%len(path)]
+	}
+}
+
+// Draw applies op to every frame with the frame as dst and the source of layer as src, and writes
+// the result to the frame. Any core.Op can be used, such as op.BlendOp and op.CompositeOp. The
+// rows are distributed with the PixelIterator of cfg.
+func (a *Animation) Draw(cfg core.Config, op core.Op, layer Layer) error {
+	if op == nil || !op.IsValid() {
+		return errors.New("invalid operation")
+	}
+	if layer == nil {
+		return errors.New("layer is nil")
+	}
+	for i, frame := range a.Frames {
+		src, at := layer(i)
+		if src == nil {
+			continue
+		}
+		s := originNRGBA(src)
+		r := s.Rect.Add(at).Intersect(frame.Rect)
+		if r.Empty() {
+			continue
+		}
+		op.ApplyNRGBA(cfg.PixelIterator(), core.NewPixCalculatorNRGBA(frame, r, s, r.Min.Sub(at), frame, r.Min))
+	}
+	return nil
+}
+
+// originNRGBA returns img as an NRGBA image whose bounds start at the origin.
+func originNRGBA(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	if n, ok := img.(*image.NRGBA); ok && b.Min == (image.Point{}) {
+		return n
+	}
+	out := image.NewNRGBA(b.Sub(b.Min))
+	draw.Draw(out, out.Rect, img, b.Min, draw.Src)
+	return out
+}
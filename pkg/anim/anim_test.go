@@ -0,0 +1,340 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package anim
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+var (
+	red         = color.NRGBA{R: 255, A: 255}
+	green       = color.NRGBA{G: 255, A: 255}
+	blue        = color.NRGBA{B: 255, A: 255}
+	transparent = color.NRGBA{}
+)
+
+func testConfig(concurrency int) core.Config {
+	return internal.NewConfig(core.NewPixelIterator(concurrency), core.DefaultOutputToNewImage, color.NRGBAModel)
+}
+
+// paletted returns a frame of the given bounds filled with palette index fill.
+func paletted(r image.Rectangle, palette color.Palette, fill uint8) *image.Paletted {
+	p := image.NewPaletted(r, palette)
+	for i := range p.Pix {
+		p.Pix[i] = fill
+	}
+	return p
+}
+
+// testGIF returns a 4x4 animation exercising the disposal methods:
+//
+//   - frame 0 fills the canvas with red and is kept;
+//   - frame 1 draws green over (0,0)-(2,2) and is disposed of to the background;
+//   - frame 2 draws blue over (2,2)-(4,4) with a transparent pixel and restores the previous canvas;
+//   - frame 3 draws nothing but a transparent pixel.
+func testGIF() *gif.GIF {
+	palette := color.Palette{red, green, blue, transparent}
+	f2 := paletted(image.Rect(2, 2, 4, 4), palette, 2)
+	f2.SetColorIndex(3, 3, 3)
+	return &gif.GIF{
+		Image: []*image.Paletted{
+			paletted(image.Rect(0, 0, 4, 4), palette, 0),
+			paletted(image.Rect(0, 0, 2, 2), palette, 1),
+			f2,
+			paletted(image.Rect(0, 0, 1, 1), palette, 3),
+		},
+		Delay:     []int{10, 20, 30, 40},
+		Disposal:  []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalPrevious, gif.DisposalNone},
+		LoopCount: 2,
+		Config:    image.Config{Width: 4, Height: 4},
+	}
+}
+
+func assertPixel(t *testing.T, img *image.NRGBA, x, y int, want color.NRGBA) {
+	t.Helper()
+	if got := img.NRGBAAt(x, y); got != want {
+		t.Errorf("pixel (%d,%d) = %v, want %v", x, y, got, want)
+	}
+}
+
+func TestFromGIF(t *testing.T) {
+	a, err := FromGIF(testGIF())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Frames) != 4 || a.LoopCount != 2 || a.Delay[3] != 40 {
+		t.Fatalf("got %d frames, loop count %d and delays %v", len(a.Frames), a.LoopCount, a.Delay)
+	}
+	for _, f := range a.Frames {
+		if f.Rect != image.Rect(0, 0, 4, 4) {
+			t.Fatalf("frame bounds = %v, want the canvas", f.Rect)
+		}
+	}
+
+	assertPixel(t, a.Frames[0], 1, 1, red)
+	assertPixel(t, a.Frames[1], 1, 1, green)
+	assertPixel(t, a.Frames[1], 3, 3, red)
+	// Frame 1 was disposed of to the background.
+	assertPixel(t, a.Frames[2], 1, 1, transparent)
+	assertPixel(t, a.Frames[2], 2, 2, blue)
+	// Transparent pixels keep the canvas.
+	assertPixel(t, a.Frames[2], 3, 3, red)
+	// Frame 2 was disposed of to the previous canvas.
+	assertPixel(t, a.Frames[3], 2, 2, red)
+	assertPixel(t, a.Frames[3], 1, 1, transparent)
+	assertPixel(t, a.Frames[3], 0, 0, transparent)
+
+	t.Run("Canvas from frames", func(t *testing.T) {
+		g := testGIF()
+		g.Config = image.Config{}
+		a, err := FromGIF(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Frames[0].Rect != image.Rect(0, 0, 4, 4) {
+			t.Errorf("canvas = %v, want (0,0)-(4,4)", a.Frames[0].Rect)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		if _, err := FromGIF(&gif.GIF{}); err == nil {
+			t.Error("expected an error for a GIF without frames")
+		}
+		if _, err := FromGIF(&gif.GIF{Image: []*image.Paletted{nil}, Config: image.Config{Width: 1, Height: 1}}); err == nil {
+			t.Error("expected an error for a nil frame")
+		}
+	})
+}
+
+// solid returns an NRGBA image of the given bounds filled with c.
+func solid(r image.Rectangle, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(r)
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func TestDraw(t *testing.T) {
+	for _, concurrency := range []int{1, 4} {
+		cfg := testConfig(concurrency)
+
+		t.Run("Static", func(t *testing.T) {
+			a, err := FromGIF(testGIF())
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The source bounds don't start at the origin and the layer is partly off the canvas.
+			badge := solid(image.Rect(5, 5, 8, 8), blue)
+			if err := a.Draw(cfg, op.CompositeOp{Mode: op.SourceOver}, Static(badge, image.Pt(2, -1))); err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range a.Frames {
+				for y := 0; y < 2; y++ {
+					for x := 2; x < 4; x++ {
+						assertPixel(t, f, x, y, blue)
+					}
+				}
+			}
+			assertPixel(t, a.Frames[0], 1, 1, red)
+			assertPixel(t, a.Frames[0], 2, 2, red)
+		})
+
+		t.Run("Blend", func(t *testing.T) {
+			a, err := FromGIF(testGIF())
+			if err != nil {
+				t.Fatal(err)
+			}
+			gray := solid(image.Rect(0, 0, 4, 4), color.NRGBA{R: 128, G: 128, B: 128, A: 255})
+			if err := a.Draw(cfg, op.BlendOp{Mode: op.Multiply, Compositing: op.CompositeAll}, Static(gray, image.Point{})); err != nil {
+				t.Fatal(err)
+			}
+			assertPixel(t, a.Frames[0], 0, 0, color.NRGBA{R: 128, A: 255})
+		})
+
+		t.Run("Path", func(t *testing.T) {
+			a, err := FromGIF(testGIF())
+			if err != nil {
+				t.Fatal(err)
+			}
+			dot := solid(image.Rect(0, 0, 1, 1), green)
+			path := []image.Point{{0, 3}, {1, 3}, {2, 3}}
+			if err := a.Draw(cfg, op.CompositeOp{Mode: op.Source}, Path(dot, path)); err != nil {
+				t.Fatal(err)
+			}
+			for i, f := range a.Frames {
+				p := path[i%len(path)]
+				assertPixel(t, f, p.X, p.Y, green)
+			}
+			assertPixel(t, a.Frames[1], 0, 3, red)
+		})
+
+		t.Run("Sequence", func(t *testing.T) {
+			a, err := FromGIF(testGIF())
+			if err != nil {
+				t.Fatal(err)
+			}
+			srcs := []image.Image{solid(image.Rect(0, 0, 1, 1), green), nil}
+			if err := a.Draw(cfg, op.CompositeOp{Mode: op.Source}, Sequence(srcs, image.Pt(3, 0))); err != nil {
+				t.Fatal(err)
+			}
+			assertPixel(t, a.Frames[0], 3, 0, green)
+			assertPixel(t, a.Frames[1], 3, 0, red)
+			assertPixel(t, a.Frames[2], 3, 0, green)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		a, err := FromGIF(testGIF())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := a.Draw(testConfig(1), nil, Static(solid(image.Rect(0, 0, 1, 1), red), image.Point{})); err == nil {
+			t.Error("expected an error for a nil op")
+		}
+		if err := a.Draw(testConfig(1), op.BlendOp{Mode: op.BlendMode(-1)}, Static(solid(image.Rect(0, 0, 1, 1), red), image.Point{})); err == nil {
+			t.Error("expected an error for an invalid op")
+		}
+		if err := a.Draw(testConfig(1), op.CompositeOp{Mode: op.SourceOver}, nil); err == nil {
+			t.Error("expected an error for a nil layer")
+		}
+	})
+}
+
+// roundTrip encodes and decodes g.
+func roundTrip(t *testing.T, g *gif.GIF) *gif.GIF {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatalf("EncodeAll failed: %v", err)
+	}
+	out, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("DecodeAll failed: %v", err)
+	}
+	return out
+}
+
+func TestGIF(t *testing.T) {
+	t.Run("Shared palette", func(t *testing.T) {
+		a, err := FromGIF(testGIF())
+		if err != nil {
+			t.Fatal(err)
+		}
+		g := a.GIF(nil)
+		palette, ok := g.Config.ColorModel.(color.Palette)
+		if !ok || len(palette) != 4 {
+			t.Fatalf("global palette = %v, want red, green, blue and transparent", g.Config.ColorModel)
+		}
+		for i, f := range g.Image {
+			if len(f.Palette) != len(palette) {
+				t.Errorf("frame %d should use the shared palette", i)
+			}
+			if g.Disposal[i] != gif.DisposalBackground {
+				t.Errorf("frame %d disposal = %d, want background since frames are transparent", i, g.Disposal[i])
+			}
+		}
+
+		// The frames come back unchanged.
+		back, err := FromGIF(roundTrip(t, g))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back.LoopCount != a.LoopCount || back.Delay[2] != a.Delay[2] {
+			t.Errorf("loop count %d and delays %v, want %d and %v", back.LoopCount, back.Delay, a.LoopCount, a.Delay)
+		}
+		for i := range a.Frames {
+			if !bytes.Equal(back.Frames[i].Pix, a.Frames[i].Pix) {
+				t.Errorf("frame %d changed in the round trip", i)
+			}
+		}
+	})
+
+	t.Run("Quantized", func(t *testing.T) {
+		// Every frame has 32x32 colors, and the frames don't share them.
+		frames := make([]*image.NRGBA, 3)
+		for i := range frames {
+			f := image.NewNRGBA(image.Rect(0, 0, 32, 32))
+			for y := 0; y < 32; y++ {
+				for x := 0; x < 32; x++ {
+					f.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 8), G: uint8(y * 8), B: uint8(i * 80), A: 255})
+				}
+			}
+			frames[i] = f
+		}
+		a := &Animation{Frames: frames, Delay: []int{5, 5, 5}}
+		for _, dither := range []bool{false, true} {
+			g := a.GIF(&Options{Dither: dither})
+			if g.Config.ColorModel != nil {
+				t.Error("frames with too many colors should not share a palette")
+			}
+			back, err := FromGIF(roundTrip(t, g))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, f := range frames {
+				if len(g.Image[i].Palette) > 256 {
+					t.Fatalf("frame %d has %d colors", i, len(g.Image[i].Palette))
+				}
+				if g.Disposal[i] != gif.DisposalNone {
+					t.Errorf("opaque frame %d disposal = %d, want none", i, g.Disposal[i])
+				}
+				// The mean error of a median cut palette with 256 colors is small.
+				var sum, n int
+				for j := 0; j < len(f.Pix); j += 4 {
+					for c := 0; c < 3; c++ {
+						d := int(f.Pix[j+c]) - int(back.Frames[i].Pix[j+c])
+						sum += d * d
+						n++
+					}
+					if back.Frames[i].Pix[j+3] != 255 {
+						t.Fatalf("frame %d pixel %d is not opaque", i, j/4)
+					}
+				}
+				if mse := float64(sum) / float64(n); mse > 20 {
+					t.Errorf("dither %v: frame %d mean squared error = %.1f", dither, i, mse)
+				}
+			}
+		}
+	})
+
+	t.Run("Semi-transparent", func(t *testing.T) {
+		f := solid(image.Rect(0, 0, 2, 1), color.NRGBA{R: 200, A: 200})
+		f.SetNRGBA(1, 0, color.NRGBA{G: 200, A: 60})
+		g := (&Animation{Frames: []*image.NRGBA{f}, Delay: []int{1}}).GIF(nil)
+		back, err := FromGIF(roundTrip(t, g))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPixel(t, back.Frames[0], 0, 0, color.NRGBA{R: 200, A: 255})
+		assertPixel(t, back.Frames[0], 1, 0, transparent)
+	})
+}
+
+func TestMedianCut(t *testing.T) {
+	colors := map[uint32]int{}
+	for r := 0; r < 256; r += 5 {
+		for b := 0; b < 256; b += 5 {
+			colors[packColor(uint8(r), 0, uint8(b))] = 1 + r%3
+		}
+	}
+	palette := medianCut(colors, 16)
+	if len(palette) != 16 {
+		t.Fatalf("got %d colors, want 16", len(palette))
+	}
+	if again := medianCut(colors, 16); len(again) != len(palette) || again[3] != palette[3] {
+		t.Error("median cut should be deterministic")
+	}
+	if few := medianCut(map[uint32]int{1: 3, 2: 1}, 16); len(few) != 2 {
+		t.Errorf("got %d colors for 2 input colors, want 2", len(few))
+	}
+}
@@ -0,0 +1,557 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package anim
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/image/nrgba"
+)
+
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// APNG dispose_op and blend_op values.
+const (
+	apngDisposeNone       = 0
+	apngDisposeBackground = 1
+	apngDisposePrevious   = 2
+
+	apngBlendSource = 0
+	apngBlendOver   = 1
+)
+
+// apngFrame is the frame control of an APNG frame and its image data.
+type apngFrame struct {
+	rect            image.Rectangle
+	delayNum        uint16
+	delayDen        uint16
+	dispose, blend  byte
+	data            []byte
+	hasData, isIDAT bool
+}
+
+// DecodeAPNG reads an animated PNG and reconstructs its full frames. Every frame is drawn on the
+// canvas left by the previous one, replacing its region or composited over it as its blend_op
+// specifies, and then disposed of as its dispose_op specifies. A default image that isn't part
+// of the animation is skipped, and a PNG without animation decodes to a single frame.
+//
+// Delays are rounded to hundredths of a second, and the number of plays is converted to the
+// LoopCount of Animation.
+func DecodeAPNG(r io.Reader) (*Animation, error) {
+	chunks, err := readChunks(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 || chunks[0].typ != "IHDR" || len(chunks[0].data) != 13 {
+		return nil, errors.New("apng: missing IHDR chunk")
+	}
+	ihdr := chunks[0].data
+	w, h := binary.BigEndian.Uint32(ihdr[0:4]), binary.BigEndian.Uint32(ihdr[4:8])
+	if w == 0 || h == 0 || w > 0x7fffffff || h > 0x7fffffff {
+		return nil, fmt.Errorf("apng: invalid image size %dx%d", w, h)
+	}
+	// As in image/png, the pixels must be addressable.
+	if uint64(w)*uint64(h) > math.MaxInt/4 {
+		return nil, fmt.Errorf("apng: image size %dx%d is too large", w, h)
+	}
+	canvas := image.Rect(0, 0, int(w), int(h))
+
+	// The chunks copied into the PNG of every frame, and the frames.
+	var shared []pngChunk
+	var frames []*apngFrame
+	var idat []byte
+	plays, animated := 0, false
+	for _, c := range chunks[1:] {
+		switch c.typ {
+		case "PLTE", "tRNS":
+			shared = append(shared, c)
+		case "acTL":
+			if len(c.data) != 8 {
+				return nil, errors.New("apng: invalid acTL chunk")
+			}
+			plays, animated = int(binary.BigEndian.Uint32(c.data[4:8])), true
+		case "fcTL":
+			f, err := parseFrameControl(c.data, canvas)
+			if err != nil {
+				return nil, err
+			}
+			frames = append(frames, f)
+		case "IDAT":
+			idat = append(idat, c.data...)
+			// The default image is the first frame if an fcTL precedes it.
+			if len(frames) == 1 && (!frames[0].hasData || frames[0].isIDAT) {
+				frames[0].data, frames[0].hasData, frames[0].isIDAT = append(frames[0].data, c.data...), true, true
+			}
+		case "fdAT":
+			if len(c.data) < 4 {
+				return nil, errors.New("apng: invalid fdAT chunk")
+			}
+			if len(frames) == 0 || frames[len(frames)-1].isIDAT {
+				return nil, errors.New("apng: fdAT chunk without fcTL")
+			}
+			f := frames[len(frames)-1]
+			f.data, f.hasData = append(f.data, c.data[4:]...), true
+		}
+	}
+	if !animated || len(frames) == 0 {
+		frames = []*apngFrame{{rect: canvas, delayDen: 100, data: idat, hasData: true}}
+	}
+	if frames[0].rect != canvas {
+		return nil, errors.New("apng: the first frame doesn't cover the canvas")
+	}
+
+	a := &Animation{
+		Frames:    make([]*image.NRGBA, len(frames)),
+		Delay:     make([]int, len(frames)),
+		LoopCount: loopCount(plays),
+	}
+	pixIter := core.NewSerialPixelIterator()
+	var out, saved *image.NRGBA
+	for i, f := range frames {
+		if !f.hasData {
+			return nil, fmt.Errorf("apng: frame %d has no image data", i)
+		}
+		img, err := decodeFrame(ihdr, shared, f)
+		if err != nil {
+			return nil, fmt.Errorf("apng: frame %d: %w", i, err)
+		}
+		if out == nil {
+			// The first frame covers the canvas, so decoding it has shown that the file holds
+			// the pixels of the canvas.
+			out = image.NewNRGBA(canvas)
+		}
+		dispose := f.dispose
+		if dispose == apngDisposePrevious {
+			// The first frame has no previous canvas and is disposed of to the background instead.
+			if i == 0 {
+				dispose = apngDisposeBackground
+			} else {
+				saved = cloneNRGBA(saved, out)
+			}
+		}
+
+		calc := core.NewPixCalculatorNRGBA(out, f.rect, img, image.Point{}, out, f.rect.Min)
+		if f.blend == apngBlendOver {
+			nrgba.CompositeSourceOver(pixIter, calc)
+		} else {
+			nrgba.CompositeSource(pixIter, calc)
+		}
+		a.Frames[i] = cloneNRGBA(nil, out)
+		a.Delay[i] = delay(f.delayNum, f.delayDen)
+
+		switch dispose {
+		case apngDisposeBackground:
+			draw.Draw(out, f.rect, image.Transparent, image.Point{}, draw.Src)
+		case apngDisposePrevious:
+			copy(out.Pix, saved.Pix)
+		}
+	}
+	return a, nil
+}
+
+// parseFrameControl parses the data of an fcTL chunk.
+func parseFrameControl(data []byte, canvas image.Rectangle) (*apngFrame, error) {
+	if len(data) != 26 {
+		return nil, errors.New("apng: invalid fcTL chunk")
+	}
+	u := func(i int) uint32 { return binary.BigEndian.Uint32(data[i : i+4]) }
+	w, h, x, y := u(4), u(8), u(12), u(16)
+	if w == 0 || h == 0 || max(w, h, x, y) > 0x7fffffff {
+		return nil, errors.New("apng: invalid frame size or offset")
+	}
+	r := image.Rect(int(x), int(y), int(x)+int(w), int(y)+int(h))
+	if !r.In(canvas) {
+		return nil, fmt.Errorf("apng: frame %v is outside of the canvas %v", r, canvas)
+	}
+	f := &apngFrame{
+		rect:     r,
+		delayNum: binary.BigEndian.Uint16(data[20:22]),
+		delayDen: binary.BigEndian.Uint16(data[22:24]),
+		dispose:  data[24],
+		blend:    data[25],
+	}
+	if f.dispose > apngDisposePrevious || f.blend > apngBlendOver {
+		return nil, errors.New("apng: invalid dispose_op or blend_op")
+	}
+	return f, nil
+}
+
+// decodeFrame decodes the image data of a frame by wrapping it in a PNG of the size of the frame.
+// image/png allocates the image before reading its data, so the data is first checked to inflate
+// to the size of the frame: allocations then grow with the size of the file, not with the size
+// it claims.
+func decodeFrame(ihdr []byte, shared []pngChunk, f *apngFrame) (*image.NRGBA, error) {
+	size, err := rawSize(ihdr, f.rect.Dx(), f.rect.Dy())
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(f.data))
+	if err != nil {
+		return nil, err
+	}
+	n, err := io.Copy(io.Discard, io.LimitReader(zr, size))
+	if err != nil {
+		return nil, err
+	}
+	if n < size {
+		return nil, errors.New("not enough image data")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(pngSignature)
+	header := bytes.Clone(ihdr)
+	binary.BigEndian.PutUint32(header[0:4], uint32(f.rect.Dx()))
+	binary.BigEndian.PutUint32(header[4:8], uint32(f.rect.Dy()))
+	writeChunk(&buf, "IHDR", header)
+	for _, c := range shared {
+		writeChunk(&buf, c.typ, c.data)
+	}
+	writeChunk(&buf, "IDAT", f.data)
+	writeChunk(&buf, "IEND", nil)
+	img, err := png.Decode(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return originNRGBA(img), nil
+}
+
+// rawSize returns the size of the filtered rows of a w x h image with the format of ihdr.
+func rawSize(ihdr []byte, w, h int) (int64, error) {
+	channels := map[byte]int64{0: 1, 2: 3, 3: 1, 4: 2, 6: 4}[ihdr[9]]
+	depth := int64(ihdr[8])
+	if channels == 0 || depth == 0 {
+		return 0, fmt.Errorf("unsupported color type %d with bit depth %d", ihdr[9], ihdr[8])
+	}
+	// Every row starts with its filter type.
+	rowSize := func(w int) int64 { return 1 + (int64(w)*channels*depth+7)/8 }
+	if ihdr[12] == 0 {
+		return int64(h) * rowSize(w), nil
+	}
+	// The Adam7 passes: offset and spacing of their pixels.
+	var n int64
+	for _, p := range [7][4]int{{0, 0, 8, 8}, {4, 0, 8, 8}, {0, 4, 4, 8}, {2, 0, 4, 4}, {0, 2, 2, 4}, {1, 0, 2, 2}, {0, 1, 1, 2}} {
+		pw, ph := (w-p[0]+p[2]-1)/p[2], (h-p[1]+p[3]-1)/p[3]
+		if pw > 0 && ph > 0 {
+			n += int64(ph) * rowSize(pw)
+		}
+	}
+	return n, nil
+}
+
+// delay converts an APNG delay fraction to hundredths of a second. A zero denominator means 100.
+func delay(num, den uint16) int {
+	if den == 0 {
+		den = 100
+	}
+	return (int(num)*100 + int(den)/2) / int(den)
+}
+
+// loopCount converts the number of plays of an APNG, 0 for infinite, to a LoopCount.
+func loopCount(plays int) int {
+	switch plays {
+	case 0:
+		return 0
+	case 1:
+		return -1
+	default:
+		return plays - 1
+	}
+}
+
+// numPlays converts a LoopCount to the number of plays of an APNG.
+func numPlays(loopCount int) uint32 {
+	switch {
+	case loopCount == 0:
+		return 0
+	case loopCount < 0:
+		return 1
+	default:
+		return uint32(loopCount) + 1
+	}
+}
+
+// EncodeAPNG writes the animation as an animated PNG. The first frame is also the default image,
+// shown by decoders without APNG support. The following frames only store the region that changed
+// since the previous frame. Delays are written in hundredths of a second.
+//
+// The pixels are stored as 8-bit RGBA, or as 8-bit RGB if all frames are opaque.
+func EncodeAPNG(w io.Writer, a *Animation) error {
+	if len(a.Frames) == 0 {
+		return errors.New("apng: animation has no frames")
+	}
+	canvas := a.Frames[0].Rect
+	opaque := true
+	for i, f := range a.Frames {
+		if f == nil || f.Rect != canvas {
+			return fmt.Errorf("apng: frame %d doesn't have the bounds of the canvas %v", i, canvas)
+		}
+		opaque = opaque && f.Opaque()
+	}
+	if canvas.Empty() {
+		return errors.New("apng: canvas is empty")
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(pngSignature)
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(canvas.Dx()))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(canvas.Dy()))
+	ihdr[8] = 8 // bit depth
+	ihdr[9] = 6 // RGBA
+	if opaque {
+		ihdr[9] = 2 // RGB
+	}
+	writeChunk(bw, "IHDR", ihdr)
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:4], uint32(len(a.Frames)))
+	binary.BigEndian.PutUint32(actl[4:8], numPlays(a.LoopCount))
+	writeChunk(bw, "acTL", actl)
+
+	var seq uint32
+	for i, f := range a.Frames {
+		// Frames replace their region, so only the changed pixels need to be stored.
+		r := canvas
+		if i > 0 {
+			r = changedRect(a.Frames[i-1], f)
+		}
+		var d int
+		if i < len(a.Delay) {
+			d = a.Delay[i]
+		}
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:4], seq)
+		binary.BigEndian.PutUint32(fctl[4:8], uint32(r.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:12], uint32(r.Dy()))
+		binary.BigEndian.PutUint32(fctl[12:16], uint32(r.Min.X))
+		binary.BigEndian.PutUint32(fctl[16:20], uint32(r.Min.Y))
+		binary.BigEndian.PutUint16(fctl[20:22], uint16(core.Clamp(d, 0, 0xffff)))
+		binary.BigEndian.PutUint16(fctl[22:24], 100)
+		fctl[24], fctl[25] = apngDisposeNone, apngBlendSource
+		writeChunk(bw, "fcTL", fctl)
+		seq++
+
+		data, err := compressRows(f, r, opaque)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			writeChunk(bw, "IDAT", data)
+			continue
+		}
+		fdat := make([]byte, 4+len(data))
+		binary.BigEndian.PutUint32(fdat, seq)
+		copy(fdat[4:], data)
+		writeChunk(bw, "fdAT", fdat)
+		seq++
+	}
+	writeChunk(bw, "IEND", nil)
+	return bw.Flush()
+}
+
+// changedRect returns the bounding box of the pixels that differ between prev and cur. Frames
+// can't be empty, so an unchanged frame stores its top-left pixel.
+func changedRect(prev, cur *image.NRGBA) image.Rectangle {
+	r := image.Rectangle{}
+	b := cur.Rect
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		p := prev.Pix[prev.PixOffset(b.Min.X, y):][:b.Dx()*4]
+		c := cur.Pix[cur.PixOffset(b.Min.X, y):][:b.Dx()*4]
+		if bytes.Equal(p, c) {
+			continue
+		}
+		x0, x1 := 0, b.Dx()
+		for x0 < x1 && bytes.Equal(p[x0*4:x0*4+4], c[x0*4:x0*4+4]) {
+			x0++
+		}
+		for x1 > x0 && bytes.Equal(p[x1*4-4:x1*4], c[x1*4-4:x1*4]) {
+			x1--
+		}
+		r = r.Union(image.Rect(b.Min.X+x0, y, b.Min.X+x1, y+1))
+	}
+	if r.Empty() {
+		return image.Rectangle{Min: b.Min, Max: b.Min.Add(image.Pt(1, 1))}
+	}
+	return r
+}
+
+// compressRows filters and compresses the region r of img as PNG image data. Every row uses the
+// filter with the smallest sum of absolute differences, as image/png does.
+func compressRows(img *image.NRGBA, r image.Rectangle, opaque bool) ([]byte, error) {
+	bpp := 4
+	if opaque {
+		bpp = 3
+	}
+	n := r.Dx() * bpp
+	prev, cur := make([]byte, n), make([]byte, n)
+	filtered := make([]byte, n)
+	best := make([]byte, n+1)
+
+	var buf bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&buf, zlib.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(r.Min.X, y):][:r.Dx()*4]
+		if opaque {
+			for x := 0; x < r.Dx(); x++ {
+				copy(cur[x*3:x*3+3], row[x*4:x*4+3])
+			}
+		} else {
+			copy(cur, row)
+		}
+
+		bestSum := -1
+		for ft := byte(0); ft < 5; ft++ {
+			sum := filterRow(filtered, cur, prev, bpp, ft)
+			if bestSum < 0 || sum < bestSum {
+				bestSum = sum
+				best[0] = ft
+				copy(best[1:], filtered)
+			}
+		}
+		if _, err := zw.Write(best); err != nil {
+			return nil, err
+		}
+		prev, cur = cur, prev
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// filterRow applies the PNG filter ft to cur, whose previous row is prev, and returns the sum of
+// the absolute values of the filtered bytes.
+func filterRow(dst, cur, prev []byte, bpp int, ft byte) int {
+	sum := 0
+	for i := range cur {
+		var a, c byte
+		if i >= bpp {
+			a, c = cur[i-bpp], prev[i-bpp]
+		}
+		b := prev[i]
+		var p byte
+		switch ft {
+		case 1:
+			p = a
+		case 2:
+			p = b
+		case 3:
+			p = byte((int(a) + int(b)) / 2)
+		case 4:
+			p = paeth(a, b, c)
+		}
+		v := cur[i] - p
+		dst[i] = v
+		if v < 128 {
+			sum += int(v)
+		} else {
+			sum += 256 - int(v)
+		}
+	}
+	return sum
+}
+
+// paeth is the Paeth predictor of PNG.
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	default:
+		return c
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// pngChunk is a chunk of a PNG stream.
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+// readChunks reads the chunks of a PNG stream up to IEND, checking their CRCs.
+func readChunks(r io.Reader) ([]pngChunk, error) {
+	br := bufio.NewReader(r)
+	sig := make([]byte, len(pngSignature))
+	if _, err := io.ReadFull(br, sig); err != nil || string(sig) != pngSignature {
+		return nil, errors.New("apng: not a PNG file")
+	}
+	var chunks []pngChunk
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(br, header); err != nil {
+			return nil, fmt.Errorf("apng: reading chunk: %w", noEOF(err))
+		}
+		n := binary.BigEndian.Uint32(header[0:4])
+		if n > 0x7fffffff {
+			return nil, errors.New("apng: invalid chunk length")
+		}
+		typ := string(header[4:8])
+		// The data is read as it arrives rather than allocated from the length, which a short
+		// file may overstate.
+		var data bytes.Buffer
+		crc := crc32.NewIEEE()
+		crc.Write(header[4:8])
+		if _, err := io.CopyN(io.MultiWriter(&data, crc), br, int64(n)); err != nil {
+			return nil, fmt.Errorf("apng: reading %s chunk: %w", typ, noEOF(err))
+		}
+		var sum [4]byte
+		if _, err := io.ReadFull(br, sum[:]); err != nil {
+			return nil, fmt.Errorf("apng: reading %s chunk: %w", typ, noEOF(err))
+		}
+		if crc.Sum32() != binary.BigEndian.Uint32(sum[:]) {
+			return nil, fmt.Errorf("apng: invalid checksum of %s chunk", typ)
+		}
+		if typ == "IEND" {
+			return chunks, nil
+		}
+		chunks = append(chunks, pngChunk{typ: typ, data: data.Bytes()})
+	}
+}
+
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// writeChunk writes a chunk with its length and CRC. Write errors are reported by the caller,
+// which flushes or reads the buffer.
+func writeChunk(w io.Writer, typ string, data []byte) {
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[0:4], uint32(len(data)))
+	copy(header[4:8], typ)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:8])
+	crc.Write(data)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+	w.Write(header[:])
+	w.Write(data)
+	w.Write(sum[:])
+}
@@ -0,0 +1,261 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package anim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+// gradient returns an NRGBA image with varying colors and, unless opaque, varying alpha.
+func gradient(r image.Rectangle, seed uint8, opaque bool) *image.NRGBA {
+	img := image.NewNRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			a := uint8(255)
+			if !opaque {
+				a = uint8(x*40+y*15) + seed
+			}
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x*30) + seed, G: uint8(y * 25), B: seed * 3, A: a})
+		}
+	}
+	return img
+}
+
+func TestAPNGRoundTrip(t *testing.T) {
+	canvas := image.Rect(0, 0, 7, 5)
+	for _, opaque := range []bool{false, true} {
+		f0 := gradient(canvas, 10, opaque)
+		// Frame 1 changes a small region, frame 2 nothing and frame 3 everything.
+		f1 := cloneNRGBA(nil, f0)
+		f1.SetNRGBA(2, 1, color.NRGBA{R: 1, G: 2, B: 3, A: 255})
+		f1.SetNRGBA(4, 3, color.NRGBA{R: 4, G: 5, B: 6, A: 255})
+		f2 := cloneNRGBA(nil, f1)
+		f3 := gradient(canvas, 70, opaque)
+		for _, loop := range []int{0, -1, 3} {
+			a := &Animation{Frames: []*image.NRGBA{f0, f1, f2, f3}, Delay: []int{4, 8, 0, 250}, LoopCount: loop}
+			var buf bytes.Buffer
+			if err := EncodeAPNG(&buf, a); err != nil {
+				t.Fatalf("EncodeAPNG failed: %v", err)
+			}
+			data := buf.Bytes()
+
+			// Decoders without APNG support see the first frame.
+			first, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("png.Decode failed: %v", err)
+			}
+			if _, isNRGBA := first.(*image.NRGBA); isNRGBA == opaque {
+				t.Errorf("opaque %v: default image is %T", opaque, first)
+			}
+
+			back, err := DecodeAPNG(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("DecodeAPNG failed: %v", err)
+			}
+			if back.LoopCount != loop {
+				t.Errorf("loop count = %d, want %d", back.LoopCount, loop)
+			}
+			if len(back.Frames) != len(a.Frames) {
+				t.Fatalf("got %d frames, want %d", len(back.Frames), len(a.Frames))
+			}
+			for i := range a.Frames {
+				if back.Delay[i] != a.Delay[i] {
+					t.Errorf("frame %d delay = %d, want %d", i, back.Delay[i], a.Delay[i])
+				}
+				if !bytes.Equal(back.Frames[i].Pix, a.Frames[i].Pix) {
+					t.Errorf("opaque %v: frame %d changed in the round trip", opaque, i)
+				}
+			}
+		}
+	}
+}
+
+func TestChangedRect(t *testing.T) {
+	a := gradient(image.Rect(0, 0, 8, 8), 0, false)
+	b := cloneNRGBA(nil, a)
+	if got := changedRect(a, b); got != image.Rect(0, 0, 1, 1) {
+		t.Errorf("changedRect of equal frames = %v, want (0,0)-(1,1)", got)
+	}
+	b.SetNRGBA(2, 5, color.NRGBA{})
+	b.SetNRGBA(6, 3, color.NRGBA{})
+	if got := changedRect(a, b); got != image.Rect(2, 3, 7, 6) {
+		t.Errorf("changedRect = %v, want (2,3)-(7,6)", got)
+	}
+}
+
+// testFrame describes a frame of a hand-written APNG.
+type testFrame struct {
+	img            *image.NRGBA // bounds give the frame region
+	dispose, blend byte
+}
+
+// buildAPNG writes an RGBA APNG chunk by chunk. hidden, if not nil, is a default image that is not
+// part of the animation.
+func buildAPNG(canvas image.Rectangle, hidden *image.NRGBA, frames []testFrame) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(pngSignature)
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(canvas.Dx()))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(canvas.Dy()))
+	ihdr[8], ihdr[9] = 8, 6
+	writeChunk(&buf, "IHDR", ihdr)
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:4], uint32(len(frames)))
+	writeChunk(&buf, "acTL", actl)
+	if hidden != nil {
+		data, _ := compressRows(hidden, hidden.Rect, false)
+		writeChunk(&buf, "IDAT", data)
+	}
+	var seq uint32
+	for i, f := range frames {
+		r := f.img.Rect
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:4], seq)
+		binary.BigEndian.PutUint32(fctl[4:8], uint32(r.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:12], uint32(r.Dy()))
+		binary.BigEndian.PutUint32(fctl[12:16], uint32(r.Min.X))
+		binary.BigEndian.PutUint32(fctl[16:20], uint32(r.Min.Y))
+		binary.BigEndian.PutUint16(fctl[20:22], 1)
+		binary.BigEndian.PutUint16(fctl[22:24], 10)
+		fctl[24], fctl[25] = f.dispose, f.blend
+		writeChunk(&buf, "fcTL", fctl)
+		seq++
+		data, _ := compressRows(f.img, r, false)
+		if i == 0 && hidden == nil {
+			writeChunk(&buf, "IDAT", data)
+			continue
+		}
+		writeChunk(&buf, "fdAT", append(binary.BigEndian.AppendUint32(nil, seq), data...))
+		seq++
+	}
+	writeChunk(&buf, "IEND", nil)
+	return buf.Bytes()
+}
+
+func TestDecodeAPNG(t *testing.T) {
+	canvas := image.Rect(0, 0, 4, 4)
+	half := color.NRGBA{R: 200, G: 100, B: 50, A: 128}
+	frames := []testFrame{
+		{img: solid(canvas, red), dispose: apngDisposeNone, blend: apngBlendSource},
+		{img: solid(image.Rect(0, 0, 2, 2), half), dispose: apngDisposeBackground, blend: apngBlendOver},
+		{img: solid(image.Rect(2, 2, 4, 4), half), dispose: apngDisposePrevious, blend: apngBlendSource},
+		{img: solid(image.Rect(3, 0, 4, 1), blue), dispose: apngDisposeNone, blend: apngBlendOver},
+	}
+	for _, hidden := range []*image.NRGBA{nil, solid(canvas, green)} {
+		a, err := DecodeAPNG(bytes.NewReader(buildAPNG(canvas, hidden, frames)))
+		if err != nil {
+			t.Fatalf("DecodeAPNG failed: %v", err)
+		}
+		if len(a.Frames) != 4 || a.Delay[0] != 10 || a.LoopCount != 0 {
+			t.Fatalf("got %d frames, delays %v and loop count %d", len(a.Frames), a.Delay, a.LoopCount)
+		}
+		// Frame 0 replaces the empty canvas.
+		assertPixel(t, a.Frames[0], 0, 0, red)
+		// Frame 1 is composited over red, then cleared.
+		assertPixel(t, a.Frames[1], 0, 0, color.NRGBA{R: 227, G: 50, B: 25, A: 255})
+		assertPixel(t, a.Frames[1], 3, 3, red)
+		assertPixel(t, a.Frames[2], 0, 0, transparent)
+		// Frame 2 replaces its region, then restores the previous canvas.
+		assertPixel(t, a.Frames[2], 3, 3, half)
+		assertPixel(t, a.Frames[3], 3, 3, red)
+		assertPixel(t, a.Frames[3], 0, 0, transparent)
+		assertPixel(t, a.Frames[3], 3, 0, blue)
+	}
+
+	t.Run("Composite", func(t *testing.T) {
+		a, err := DecodeAPNG(bytes.NewReader(buildAPNG(canvas, nil, frames)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := a.Draw(testConfig(1), op.CompositeOp{Mode: op.DestinationOver}, Static(solid(canvas, green), image.Point{})); err != nil {
+			t.Fatal(err)
+		}
+		assertPixel(t, a.Frames[2], 0, 0, green)
+		assertPixel(t, a.Frames[2], 2, 2, color.NRGBA{R: 100, G: 177, B: 25, A: 255})
+	})
+
+	t.Run("Plain PNG", func(t *testing.T) {
+		img := gradient(canvas, 5, false)
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		a, err := DecodeAPNG(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(a.Frames) != 1 || !bytes.Equal(a.Frames[0].Pix, img.Pix) {
+			t.Error("a PNG without animation should decode to its image")
+		}
+	})
+}
+
+func TestAPNGInvalid(t *testing.T) {
+	canvas := image.Rect(0, 0, 4, 4)
+	valid := buildAPNG(canvas, nil, []testFrame{{img: solid(canvas, red)}})
+
+	corrupt := bytes.Clone(valid)
+	corrupt[len(pngSignature)+10] ^= 0xff
+	outside := buildAPNG(canvas, nil, []testFrame{{img: solid(image.Rect(2, 2, 6, 6), red)}})
+	partial := buildAPNG(canvas, nil, []testFrame{{img: solid(image.Rect(0, 0, 2, 2), red)}})
+
+	// sized returns a PNG that claims a w x h size but holds the pixels of a single row.
+	sized := func(w, h uint32) []byte {
+		var buf bytes.Buffer
+		buf.WriteString(pngSignature)
+		ihdr := make([]byte, 13)
+		binary.BigEndian.PutUint32(ihdr[0:4], w)
+		binary.BigEndian.PutUint32(ihdr[4:8], h)
+		ihdr[8], ihdr[9] = 8, 6
+		writeChunk(&buf, "IHDR", ihdr)
+		data, _ := compressRows(solid(image.Rect(0, 0, 4, 1), red), image.Rect(0, 0, 4, 1), false)
+		writeChunk(&buf, "IDAT", data)
+		writeChunk(&buf, "IEND", nil)
+		return buf.Bytes()
+	}
+	// A chunk claiming 2 GB of data followed by a few bytes.
+	long := append([]byte(pngSignature), 0x7f, 0xff, 0xff, 0xff, 'I', 'D', 'A', 'T', 1, 2, 3)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"Empty", nil},
+		{"Not a PNG", []byte("GIF89a")},
+		{"Checksum", corrupt},
+		{"Truncated", valid[:len(valid)-20]},
+		{"Frame outside of canvas", outside},
+		{"First frame smaller than the canvas", partial},
+		{"Zero size", sized(0, 4)},
+		{"Size above 2^31-1", sized(1<<31, 1)},
+		{"Largest size", sized(0x7fffffff, 0x7fffffff)},
+		{"Missing pixels", sized(200000, 200000)},
+		{"Chunk longer than the data", long},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeAPNG(bytes.NewReader(tt.data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	t.Run("Encode", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := EncodeAPNG(&buf, &Animation{}); err == nil {
+			t.Error("expected an error for an animation without frames")
+		}
+		a := &Animation{Frames: []*image.NRGBA{solid(canvas, red), solid(image.Rect(0, 0, 2, 2), red)}}
+		if err := EncodeAPNG(&buf, a); err == nil {
+			t.Error("expected an error for frames of different sizes")
+		}
+	})
+}
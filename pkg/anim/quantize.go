@@ -0,0 +1,290 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package anim
+
+import (
+	"cmp"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"slices"
+)
+
+// Options control how GIF quantizes the frames.
+type Options struct {
+	// Dither diffuses the quantization error with Floyd-Steinberg dithering in frames that have
+	// more colors than fit in a palette. Frames whose colors fit are never dithered.
+	Dither bool
+}
+
+// alphaThreshold is the alpha from which a pixel is opaque; GIF pixels are either opaque or
+// transparent.
+const alphaThreshold = 128
+
+// GIF quantizes the frames to palettes and returns them as a GIF with the delays and loop count
+// of the animation. If the colors of all frames fit in 256 entries, the frames share one exact
+// palette, which is stored once as the global color table. Otherwise every frame gets its own
+// palette: the exact colors if they fit, and a median cut palette if not. One entry is kept for
+// transparency when any pixel is transparent. opts may be nil.
+//
+// Every frame covers the whole canvas. Frames are disposed of to the background when the
+// animation has transparent pixels, so that they don't show through the following frames.
+func (a *Animation) GIF(opts *Options) *gif.GIF {
+	if opts == nil {
+		opts = &Options{}
+	}
+	g := &gif.GIF{
+		Image:     make([]*image.Paletted, len(a.Frames)),
+		Delay:     make([]int, len(a.Frames)),
+		Disposal:  make([]byte, len(a.Frames)),
+		LoopCount: a.LoopCount,
+	}
+	copy(g.Delay, a.Delay)
+	if len(a.Frames) == 0 {
+		return g
+	}
+	bounds := a.Frames[0].Rect
+	g.Config.Width, g.Config.Height = bounds.Dx(), bounds.Dy()
+
+	// The colors of all frames, if they fit in a shared palette.
+	transparent := false
+	shared := map[uint32]int{}
+	for _, f := range a.Frames {
+		t := countColors(f, shared, 256)
+		transparent = transparent || t
+		if len(shared) > paletteSize(transparent) {
+			shared = nil
+			break
+		}
+	}
+	if shared == nil {
+		transparent = false
+		for _, f := range a.Frames {
+			transparent = transparent || countColors(f, nil, 0)
+		}
+	}
+
+	disposal := byte(gif.DisposalNone)
+	if transparent {
+		disposal = gif.DisposalBackground
+	}
+	var sharedPalette color.Palette
+	if shared != nil {
+		sharedPalette = exactPalette(shared, transparent)
+		g.Config.ColorModel = sharedPalette
+		if transparent {
+			g.BackgroundIndex = uint8(len(sharedPalette) - 1)
+		}
+	}
+
+	for i, f := range a.Frames {
+		g.Disposal[i] = disposal
+		if sharedPalette != nil {
+			g.Image[i] = mapExact(f, sharedPalette)
+			continue
+		}
+		colors := map[uint32]int{}
+		t := countColors(f, colors, 256)
+		if len(colors) <= paletteSize(t) {
+			g.Image[i] = mapExact(f, exactPalette(colors, t))
+			continue
+		}
+		colors = map[uint32]int{}
+		countColors(f, colors, -1)
+		palette := medianCut(colors, paletteSize(t))
+		if t {
+			palette = append(palette, color.NRGBA{})
+		}
+		g.Image[i] = mapNearest(f, palette, opts.Dither)
+	}
+	return g
+}
+
+// paletteSize returns the number of entries available for opaque colors.
+func paletteSize(transparent bool) int {
+	if transparent {
+		return 255
+	}
+	return 256
+}
+
+// packColor packs an opaque color into a map key.
+func packColor(r, g, b uint8) uint32 {
+	return uint32(r)<<16 | uint32(g)<<8 | uint32(b)
+}
+
+// countColors counts the opaque colors of f into colors, if not nil, and reports whether f has
+// transparent pixels. It stops counting once colors has more than limit entries, unless limit is
+// negative.
+func countColors(f *image.NRGBA, colors map[uint32]int, limit int) (transparent bool) {
+	for i := 0; i < len(f.Pix); i += 4 {
+		if f.Pix[i+3] < alphaThreshold {
+			transparent = true
+			continue
+		}
+		if colors != nil && (limit < 0 || len(colors) <= limit) {
+			colors[packColor(f.Pix[i], f.Pix[i+1], f.Pix[i+2])]++
+		}
+	}
+	return transparent
+}
+
+// exactPalette returns the colors in a stable order, followed by a transparent entry if needed.
+func exactPalette(colors map[uint32]int, transparent bool) color.Palette {
+	keys := make([]uint32, 0, len(colors))
+	for c := range colors {
+		keys = append(keys, c)
+	}
+	slices.Sort(keys)
+	palette := make(color.Palette, 0, len(keys)+1)
+	for _, c := range keys {
+		palette = append(palette, color.NRGBA{R: uint8(c >> 16), G: uint8(c >> 8), B: uint8(c), A: 255})
+	}
+	if transparent {
+		palette = append(palette, color.NRGBA{})
+	}
+	return palette
+}
+
+// mapExact maps f to a palette holding all of its colors.
+func mapExact(f *image.NRGBA, palette color.Palette) *image.Paletted {
+	index := make(map[uint32]uint8, len(palette))
+	transparent := uint8(0)
+	for i, c := range palette {
+		n := c.(color.NRGBA)
+		if n.A == 0 {
+			transparent = uint8(i)
+			continue
+		}
+		index[packColor(n.R, n.G, n.B)] = uint8(i)
+	}
+	p := image.NewPaletted(f.Rect, palette)
+	for i, j := 0, 0; i < len(f.Pix); i, j = i+4, j+1 {
+		if f.Pix[i+3] < alphaThreshold {
+			p.Pix[j] = transparent
+			continue
+		}
+		p.Pix[j] = index[packColor(f.Pix[i], f.Pix[i+1], f.Pix[i+2])]
+	}
+	return p
+}
+
+// mapNearest maps f to the nearest colors of palette, optionally with dithering.
+func mapNearest(f *image.NRGBA, palette color.Palette, dither bool) *image.Paletted {
+	// Pixels are made fully opaque or transparent first, so that the alpha doesn't weigh on the
+	// choice of the colors.
+	flat := image.NewNRGBA(f.Rect)
+	for i := 0; i < len(f.Pix); i += 4 {
+		if f.Pix[i+3] >= alphaThreshold {
+			flat.Pix[i], flat.Pix[i+1], flat.Pix[i+2], flat.Pix[i+3] = f.Pix[i], f.Pix[i+1], f.Pix[i+2], 255
+		}
+	}
+	p := image.NewPaletted(f.Rect, palette)
+	if dither {
+		draw.FloydSteinberg.Draw(p, p.Rect, flat, p.Rect.Min)
+		return p
+	}
+	cache := map[uint32]uint8{}
+	for i, j := 0, 0; i < len(flat.Pix); i, j = i+4, j+1 {
+		key := uint32(flat.Pix[i])<<24 | uint32(flat.Pix[i+1])<<16 | uint32(flat.Pix[i+2])<<8 | uint32(flat.Pix[i+3])
+		idx, ok := cache[key]
+		if !ok {
+			idx = uint8(palette.Index(color.NRGBA{R: flat.Pix[i], G: flat.Pix[i+1], B: flat.Pix[i+2], A: flat.Pix[i+3]}))
+			cache[key] = idx
+		}
+		p.Pix[j] = idx
+	}
+	return p
+}
+
+// colorCount is a color of a histogram with its number of pixels.
+type colorCount struct {
+	c     [3]uint8
+	count int
+}
+
+// medianCut reduces the colors of a histogram to at most n colors. The box with the widest
+// channel range is split at the median pixel of that channel until there are n boxes, and every
+// box is replaced by the average of its colors weighted by their counts.
+func medianCut(colors map[uint32]int, n int) color.Palette {
+	all := make([]colorCount, 0, len(colors))
+	for c, count := range colors {
+		all = append(all, colorCount{c: [3]uint8{uint8(c >> 16), uint8(c >> 8), uint8(c)}, count: count})
+	}
+	// Sorting first makes the result independent of the order of the map.
+	slices.SortFunc(all, func(a, b colorCount) int {
+		return cmp.Compare(packColor(a.c[0], a.c[1], a.c[2]), packColor(b.c[0], b.c[1], b.c[2]))
+	})
+
+	boxes := [][]colorCount{all}
+	for len(boxes) < n {
+		// The box with the widest range over all channels.
+		best, bestCh, bestRange := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			ch, rng := widestChannel(box)
+			if rng > bestRange {
+				best, bestCh, bestRange = i, ch, rng
+			}
+		}
+		if best < 0 {
+			break
+		}
+		box := boxes[best]
+		slices.SortStableFunc(box, func(a, b colorCount) int { return int(a.c[bestCh]) - int(b.c[bestCh]) })
+		total := 0
+		for _, c := range box {
+			total += c.count
+		}
+		split, acc := 1, 0
+		for i := 0; i < len(box)-1; i++ {
+			acc += box[i].count
+			split = i + 1
+			if acc*2 >= total {
+				break
+			}
+		}
+		boxes[best] = box[:split:split]
+		boxes = append(boxes, box[split:])
+	}
+
+	palette := make(color.Palette, len(boxes))
+	for i, box := range boxes {
+		var sum [3]int
+		total := 0
+		for _, c := range box {
+			for ch := range sum {
+				sum[ch] += int(c.c[ch]) * c.count
+			}
+			total += c.count
+		}
+		palette[i] = color.NRGBA{
+			R: uint8((sum[0] + total/2) / total),
+			G: uint8((sum[1] + total/2) / total),
+			B: uint8((sum[2] + total/2) / total),
+			A: 255,
+		}
+	}
+	return palette
+}
+
+// widestChannel returns the channel with the widest range of values in box, and the range.
+func widestChannel(box []colorCount) (ch, rng int) {
+	lo, hi := [3]uint8{255, 255, 255}, [3]uint8{}
+	for _, c := range box {
+		for i := range lo {
+			lo[i] = min(lo[i], c.c[i])
+			hi[i] = max(hi[i], c.c[i])
+		}
+	}
+	for i := range lo {
+		if r := int(hi[i]) - int(lo[i]); r > rng {
+			ch, rng = i, r
+		}
+	}
+	return ch, rng
+}
@@ -9,6 +9,14 @@ import (
 
 type Compositing = op.BlendCompositing
 
+func AdditiveSubtractive() op.BlendOp {
+	return op.BlendOp{Mode: op.AdditiveSubtractive, Compositing: op.CompositeAll}
+}
+
+func Average() op.BlendOp {
+	return op.BlendOp{Mode: op.Average, Compositing: op.CompositeAll}
+}
+
 func ColorBurn() op.BlendOp {
 	return op.BlendOp{Mode: op.ColorBurn, Compositing: op.CompositeAll}
 }
@@ -21,10 +29,19 @@ func Darken() op.BlendOp {
 	return op.BlendOp{Mode: op.Darken, Compositing: op.CompositeAll}
 }
 
+func DarkerColor() op.BlendOp {
+	return op.BlendOp{Mode: op.DarkerColor, Compositing: op.CompositeAll}
+}
+
 func Difference() op.BlendOp {
 	return op.BlendOp{Mode: op.Difference, Compositing: op.CompositeAll}
 }
 
+// Dissolve returns a Dissolve blend whose noise pattern is selected by seed.
+func Dissolve(seed uint64) op.BlendOp {
+	return op.BlendOp{Mode: op.Dissolve, Compositing: op.CompositeAll, Seed: seed}
+}
+
 func Divide() op.BlendOp {
 	return op.BlendOp{Mode: op.Divide, Compositing: op.CompositeAll}
 }
@@ -33,6 +50,26 @@ func Exclusion() op.BlendOp {
 	return op.BlendOp{Mode: op.Exclusion, Compositing: op.CompositeAll}
 }
 
+func Freeze() op.BlendOp {
+	return op.BlendOp{Mode: op.Freeze, Compositing: op.CompositeAll}
+}
+
+func GeometricMean() op.BlendOp {
+	return op.BlendOp{Mode: op.GeometricMean, Compositing: op.CompositeAll}
+}
+
+func Glow() op.BlendOp {
+	return op.BlendOp{Mode: op.Glow, Compositing: op.CompositeAll}
+}
+
+func GrainExtract() op.BlendOp {
+	return op.BlendOp{Mode: op.GrainExtract, Compositing: op.CompositeAll}
+}
+
+func GrainMerge() op.BlendOp {
+	return op.BlendOp{Mode: op.GrainMerge, Compositing: op.CompositeAll}
+}
+
 func HardLight() op.BlendOp {
 	return op.BlendOp{Mode: op.HardLight, Compositing: op.CompositeAll}
 }
@@ -41,10 +78,18 @@ func HardMix() op.BlendOp {
 	return op.BlendOp{Mode: op.HardMix, Compositing: op.CompositeAll}
 }
 
+func Heat() op.BlendOp {
+	return op.BlendOp{Mode: op.Heat, Compositing: op.CompositeAll}
+}
+
 func Lighten() op.BlendOp {
 	return op.BlendOp{Mode: op.Lighten, Compositing: op.CompositeAll}
 }
 
+func LighterColor() op.BlendOp {
+	return op.BlendOp{Mode: op.LighterColor, Compositing: op.CompositeAll}
+}
+
 func LinearBurn() op.BlendOp {
 	return op.BlendOp{Mode: op.LinearBurn, Compositing: op.CompositeAll}
 }
@@ -57,18 +102,38 @@ func LinearLight() op.BlendOp {
 	return op.BlendOp{Mode: op.LinearLight, Compositing: op.CompositeAll}
 }
 
+func LinearLightGIMP() op.BlendOp {
+	return op.BlendOp{Mode: op.LinearLightGIMP, Compositing: op.CompositeAll}
+}
+
 func Multiply() op.BlendOp {
 	return op.BlendOp{Mode: op.Multiply, Compositing: op.CompositeAll}
 }
 
+func Negation() op.BlendOp {
+	return op.BlendOp{Mode: op.Negation, Compositing: op.CompositeAll}
+}
+
+func Normal() op.BlendOp {
+	return op.BlendOp{Mode: op.Normal, Compositing: op.CompositeAll}
+}
+
 func Overlay() op.BlendOp {
 	return op.BlendOp{Mode: op.Overlay, Compositing: op.CompositeAll}
 }
 
+func Phoenix() op.BlendOp {
+	return op.BlendOp{Mode: op.Phoenix, Compositing: op.CompositeAll}
+}
+
 func PinLight() op.BlendOp {
 	return op.BlendOp{Mode: op.PinLight, Compositing: op.CompositeAll}
 }
 
+func Reflect() op.BlendOp {
+	return op.BlendOp{Mode: op.Reflect, Compositing: op.CompositeAll}
+}
+
 func Screen() op.BlendOp {
 	return op.BlendOp{Mode: op.Screen, Compositing: op.CompositeAll}
 }
@@ -77,6 +142,18 @@ func SoftLight() op.BlendOp {
 	return op.BlendOp{Mode: op.SoftLight, Compositing: op.CompositeAll}
 }
 
+func SoftLightIllusions() op.BlendOp {
+	return op.BlendOp{Mode: op.SoftLightIllusions, Compositing: op.CompositeAll}
+}
+
+func SoftLightPegtop() op.BlendOp {
+	return op.BlendOp{Mode: op.SoftLightPegtop, Compositing: op.CompositeAll}
+}
+
+func SoftLightW3C() op.BlendOp {
+	return op.BlendOp{Mode: op.SoftLightW3C, Compositing: op.CompositeAll}
+}
+
 func Subtract() op.BlendOp {
 	return op.BlendOp{Mode: op.Subtract, Compositing: op.CompositeAll}
 }
@@ -84,3 +161,22 @@ func Subtract() op.BlendOp {
 func VividLight() op.BlendOp {
 	return op.BlendOp{Mode: op.VividLight, Compositing: op.CompositeAll}
 }
+
+func VividLightGIMP() op.BlendOp {
+	return op.BlendOp{Mode: op.VividLightGIMP, Compositing: op.CompositeAll}
+}
+
+// Custom returns a blend that applies fn to every color channel. See op.NewCustomBlend.
+func Custom(fn func(s, d uint8) uint8) op.CustomBlendOp {
+	return op.CustomBlendOp{Blend: op.NewCustomBlend(fn), Compositing: op.CompositeAll}
+}
+
+// Expression returns a blend that evaluates expr for every color channel, e.g. "$S*$D/255".
+// See op.CompileCustomBlend for the syntax.
+func Expression(expr string) (op.CustomBlendOp, error) {
+	b, err := op.CompileCustomBlend(expr)
+	if err != nil {
+		return op.CustomBlendOp{}, err
+	}
+	return op.CustomBlendOp{Blend: b, Compositing: op.CompositeAll}, nil
+}
@@ -111,6 +111,101 @@ func TestBlendOps(t *testing.T) {
 			opFunc:       blend.HardMix,
 			expectedMode: op.HardMix,
 		},
+		{
+			name:         "Normal",
+			opFunc:       blend.Normal,
+			expectedMode: op.Normal,
+		},
+		{
+			name:         "AdditiveSubtractive",
+			opFunc:       blend.AdditiveSubtractive,
+			expectedMode: op.AdditiveSubtractive,
+		},
+		{
+			name:         "Average",
+			opFunc:       blend.Average,
+			expectedMode: op.Average,
+		},
+		{
+			name:         "Freeze",
+			opFunc:       blend.Freeze,
+			expectedMode: op.Freeze,
+		},
+		{
+			name:         "GeometricMean",
+			opFunc:       blend.GeometricMean,
+			expectedMode: op.GeometricMean,
+		},
+		{
+			name:         "Glow",
+			opFunc:       blend.Glow,
+			expectedMode: op.Glow,
+		},
+		{
+			name:         "GrainExtract",
+			opFunc:       blend.GrainExtract,
+			expectedMode: op.GrainExtract,
+		},
+		{
+			name:         "GrainMerge",
+			opFunc:       blend.GrainMerge,
+			expectedMode: op.GrainMerge,
+		},
+		{
+			name:         "Heat",
+			opFunc:       blend.Heat,
+			expectedMode: op.Heat,
+		},
+		{
+			name:         "LinearLightGIMP",
+			opFunc:       blend.LinearLightGIMP,
+			expectedMode: op.LinearLightGIMP,
+		},
+		{
+			name:         "Negation",
+			opFunc:       blend.Negation,
+			expectedMode: op.Negation,
+		},
+		{
+			name:         "Phoenix",
+			opFunc:       blend.Phoenix,
+			expectedMode: op.Phoenix,
+		},
+		{
+			name:         "Reflect",
+			opFunc:       blend.Reflect,
+			expectedMode: op.Reflect,
+		},
+		{
+			name:         "SoftLightIllusions",
+			opFunc:       blend.SoftLightIllusions,
+			expectedMode: op.SoftLightIllusions,
+		},
+		{
+			name:         "SoftLightPegtop",
+			opFunc:       blend.SoftLightPegtop,
+			expectedMode: op.SoftLightPegtop,
+		},
+		{
+			name:         "SoftLightW3C",
+			opFunc:       blend.SoftLightW3C,
+			expectedMode: op.SoftLightW3C,
+		},
+		{
+			name:         "VividLightGIMP",
+			opFunc:       blend.VividLightGIMP,
+			expectedMode: op.VividLightGIMP,
+		},
+		{
+			name:         "DarkerColor",
+			opFunc:       blend.DarkerColor,
+			expectedMode: op.DarkerColor,
+		},
+		{
+			name:         "LighterColor",
+			opFunc:       blend.LighterColor,
+			expectedMode: op.LighterColor,
+		},
 	}
 
 	for _, tt := range tests {
@@ -125,3 +220,28 @@ func TestBlendOps(t *testing.T) {
 		})
 	}
 }
+
+func TestDissolve(t *testing.T) {
+	o := blend.Dissolve(42)
+	if o.Mode != op.Dissolve || o.Compositing != op.CompositeAll || o.Seed != 42 {
+		t.Errorf("Dissolve(42) = %+v", o)
+	}
+}
+
+func TestCustom(t *testing.T) {
+	o := blend.Custom(func(s, d uint8) uint8 { return s / 2 })
+	if !o.IsValid() || o.Compositing != op.CompositeAll || o.Blend.At(200, 0) != 100 {
+		t.Errorf("Custom() = %+v", o)
+	}
+
+	o, err := blend.Expression("$S / 2")
+	if err != nil {
+		t.Fatalf("Expression failed: %v", err)
+	}
+	if !o.IsValid() || o.Compositing != op.CompositeAll || o.Blend.At(200, 0) != 100 {
+		t.Errorf("Expression() = %+v", o)
+	}
+	if _, err = blend.Expression("$S /"); err == nil {
+		t.Error("expected an error for an incomplete expression")
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package clone
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+func fill(r image.Rectangle, fn func(x, y int) color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetNRGBA(x, y, fn(x, y))
+		}
+	}
+	return img
+}
+
+func TestSeamlessCloneMatchesBoundary(t *testing.T) {
+	// Dark destination, bright source with a vertical stripe of detail.
+	dst := fill(image.Rect(0, 0, 40, 40), func(_, _ int) color.NRGBA {
+		return color.NRGBA{R: 40, G: 50, B: 60, A: 255}
+	})
+	src := fill(image.Rect(0, 0, 40, 40), func(x, _ int) color.NRGBA {
+		if x == 20 {
+			return color.NRGBA{R: 230, G: 230, B: 230, A: 255}
+		}
+		return color.NRGBA{R: 200, G: 200, B: 200, A: 255}
+	})
+
+	out, err := SeamlessClone(internal.DefaultConfig, dst, image.Rect(5, 5, 35, 35), src, image.Pt(5, 5), nil, image.Point{}, NormalClone(), core.ToNewImage())
+	if err != nil {
+		t.Fatalf("SeamlessClone failed: %v", err)
+	}
+	img := out.(*image.NRGBA)
+
+	// The flat source area takes on the destination color...
+	if c := img.NRGBAAt(10, 20); absDiff(c.R, 40) > 3 || absDiff(c.B, 60) > 3 {
+		t.Errorf("cloned flat area = %v, want close to dst color", c)
+	}
+	// ...while the source detail is preserved as a relative change.
+	stripe, flat := img.NRGBAAt(20, 20), img.NRGBAAt(18, 20)
+	if d := int(stripe.R) - int(flat.R); d < 15 {
+		t.Errorf("source detail lost: stripe %v, neighbor %v", stripe, flat)
+	}
+	if dst.NRGBAAt(20, 20).R != 40 {
+		t.Error("dst should not be modified with ToNewImage")
+	}
+}
+
+func TestSeamlessCloneMask(t *testing.T) {
+	dst := fill(image.Rect(0, 0, 20, 20), func(x, _ int) color.NRGBA {
+		return color.NRGBA{R: uint8(x * 10), A: 255}
+	})
+	src := fill(image.Rect(0, 0, 20, 20), func(_, _ int) color.NRGBA {
+		return color.NRGBA{G: 255, A: 255}
+	})
+	mask := image.NewAlpha(image.Rect(0, 0, 20, 20))
+	for y := 5; y < 15; y++ {
+		for x := 5; x < 15; x++ {
+			mask.SetAlpha(x, y, color.Alpha{A: 255})
+		}
+	}
+
+	out, err := SeamlessClone(internal.DefaultConfig, dst, dst.Bounds(), src, image.Point{}, mask, image.Point{}, MixedClone(), core.ToNewImage())
+	if err != nil {
+		t.Fatalf("SeamlessClone failed: %v", err)
+	}
+	img := out.(*image.NRGBA)
+	for y := range 20 {
+		for x := range 20 {
+			if mask.AlphaAt(x, y).A == 0 && img.NRGBAAt(x, y) != dst.NRGBAAt(x, y) {
+				t.Fatalf("pixel (%d, %d) outside the mask changed", x, y)
+			}
+		}
+	}
+	// Mixed gradients keep the destination ramp through the flat source.
+	if a, b := img.NRGBAAt(8, 10).R, img.NRGBAAt(12, 10).R; absDiff(b, a) < 30 {
+		t.Errorf("destination gradient not preserved: %d, %d", a, b)
+	}
+}
+
+func TestSeamlessCloneRGBAOutsideMask(t *testing.T) {
+	// Translucent premultiplied colors that don't survive a round trip through NRGBA.
+	dst := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	for y := range 20 {
+		for x := range 20 {
+			dst.SetRGBA(x, y, color.RGBA{R: 7, G: 131, B: uint8(x + y), A: 138})
+		}
+	}
+	src := fill(image.Rect(0, 0, 20, 20), func(_, _ int) color.NRGBA {
+		return color.NRGBA{G: 255, A: 255}
+	})
+	mask := image.NewAlpha(image.Rect(0, 0, 20, 20))
+	for y := 5; y < 15; y++ {
+		for x := 5; x < 15; x++ {
+			mask.SetAlpha(x, y, color.Alpha{A: 255})
+		}
+	}
+
+	for name, output := range map[string]core.Output{"new image": core.ToNewImage(), "dst": core.ToImage(dst, image.Point{})} {
+		orig := image.NewRGBA(dst.Rect)
+		copy(orig.Pix, dst.Pix)
+		out, err := SeamlessClone(internal.DefaultConfig, dst, dst.Bounds(), src, image.Point{}, mask, image.Point{}, NormalClone(), output)
+		if err != nil {
+			t.Fatalf("SeamlessClone failed: %v", err)
+		}
+		img := out.(*image.RGBA)
+		for y := range 20 {
+			for x := range 20 {
+				if mask.AlphaAt(x, y).A == 0 && img.RGBAAt(x, y) != orig.RGBAAt(x, y) {
+					t.Fatalf("%s: pixel (%d, %d) outside the mask changed from %v to %v", name, x, y, orig.RGBAAt(x, y), img.RGBAAt(x, y))
+				}
+			}
+		}
+	}
+}
+
+func TestSeamlessCloneDeterministic(t *testing.T) {
+	dst := fill(image.Rect(0, 0, 30, 30), func(x, y int) color.NRGBA {
+		return color.NRGBA{R: uint8(x * 8), G: uint8(y * 8), B: 90, A: 255}
+	})
+	src := fill(image.Rect(0, 0, 30, 30), func(x, y int) color.NRGBA {
+		return color.NRGBA{R: uint8((x * y) % 255), G: 20, B: uint8(x * 3), A: 255}
+	})
+	o := Op{Mode: Normal, Iterations: 50}
+
+	serial, err := SeamlessClone(config{core.NewSerialPixelIterator()}, dst, dst.Bounds(), src, image.Point{}, nil, image.Point{}, o, core.ToNewRGBAImage())
+	if err != nil {
+		t.Fatalf("SeamlessClone failed: %v", err)
+	}
+	parallel, err := SeamlessClone(config{core.NewParallelPixelIterator(4)}, dst, dst.Bounds(), src, image.Point{}, nil, image.Point{}, o, core.ToNewRGBAImage())
+	if err != nil {
+		t.Fatalf("SeamlessClone failed: %v", err)
+	}
+	s, p := serial.(*image.RGBA), parallel.(*image.RGBA)
+	for i := range s.Pix {
+		if s.Pix[i] != p.Pix[i] {
+			t.Fatalf("serial and parallel results differ at %d", i)
+		}
+	}
+}
+
+func TestSeamlessCloneInvalid(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for _, o := range []Op{{Mode: -1}, {Mode: _maxMode}, {Omega: 2}, {Iterations: -1}} {
+		if _, err := SeamlessClone(internal.DefaultConfig, img, img.Bounds(), img, image.Point{}, nil, image.Point{}, o, nil); err == nil {
+			t.Errorf("SeamlessClone(%+v) should fail", o)
+		}
+	}
+}
+
+// config is a minimal core.Config with a fixed PixelIterator.
+type config struct {
+	pixIter core.PixelIterator
+}
+
+func (c config) PixelIterator() core.PixelIterator         { return c.pixIter }
+func (c config) DefaultOutputMode() core.DefaultOutputMode { return core.DefaultOutputToNewImage }
+func (c config) DefaultColorModel() color.Model            { return color.NRGBAModel }
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
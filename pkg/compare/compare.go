@@ -0,0 +1,159 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package compare measures the difference between two images: MSE, PSNR, SSIM, MS-SSIM,
+// CIEDE2000 color differences, diff heatmaps and tolerance based equality.
+//
+// Images are compared pixel by pixel with their bounds aligned at the top-left corner, so both
+// must have the same size. Colors are compared premultiplied by alpha: differences in the color
+// of transparent pixels are ignored and partially transparent pixels are weighted by their
+// coverage. Work is split across rows using the configuration's PixelIterator.
+package compare
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// premultiplied returns img as an *image.RGBA with bounds at the origin.
+func premultiplied(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Rect, img, b.Min, draw.Src)
+	return out
+}
+
+// load converts both images to premultiplied RGBA and verifies that their sizes match.
+func load(a, b image.Image) (*image.RGBA, *image.RGBA, error) {
+	if a.Bounds().Size() != b.Bounds().Size() {
+		return nil, nil, errors.New("images have different sizes")
+	}
+	return premultiplied(a), premultiplied(b), nil
+}
+
+// rowSums calls fn for each row of an image with h rows in parallel and returns the total of
+// the values it returns. Rows are summed in order so the result is deterministic.
+func rowSums(pixIter core.PixelIterator, h int, fn func(y int) float64) float64 {
+	sums := make([]float64, h)
+	core.IterateRows(pixIter, h, func(y int) {
+		sums[y] = fn(y)
+	})
+	var total float64
+	for _, s := range sums {
+		total += s
+	}
+	return total
+}
+
+// MSE returns the mean squared error over the premultiplied R, G, B and A channels of a and b,
+// in 8-bit units.
+func MSE(cfg core.Config, a, b image.Image) (float64, error) {
+	pa, pb, err := load(a, b)
+	if err != nil {
+		return 0, err
+	}
+	n := len(pa.Pix)
+	if n == 0 {
+		return 0, nil
+	}
+	w := pa.Rect.Dx() * 4
+	total := rowSums(cfg.PixelIterator(), pa.Rect.Dy(), func(y int) float64 {
+		ra, rb := pa.Pix[y*pa.Stride:y*pa.Stride+w], pb.Pix[y*pb.Stride:y*pb.Stride+w]
+		var sum float64
+		for i, v := range ra {
+			d := float64(v) - float64(rb[i])
+			sum += d * d
+		}
+		return sum
+	})
+	return total / float64(n), nil
+}
+
+// PSNR returns the peak signal-to-noise ratio of a and b in decibels.
+// Identical images return +Inf.
+func PSNR(cfg core.Config, a, b image.Image) (float64, error) {
+	mse, err := MSE(cfg, a, b)
+	if err != nil {
+		return 0, err
+	}
+	if mse == 0 {
+		return math.Inf(1), nil
+	}
+	return 10 * math.Log10(255*255/mse), nil
+}
+
+// Result describes the outcome of Equal.
+type Result struct {
+	// Equal is set when no channel of any pixel differs by more than the tolerance.
+	Equal bool
+	// Mismatches is the number of pixels exceeding the tolerance.
+	Mismatches int
+	// First is the first offending pixel in row-major order, relative to the bounds of a.
+	First image.Point
+	// Worst is the pixel with the largest channel difference, relative to the bounds of a.
+	Worst image.Point
+	// MaxDiff is the largest premultiplied channel difference found in any pixel.
+	MaxDiff uint8
+}
+
+// Equal reports whether the premultiplied channels of every pixel of a and b differ by at most
+// tolerance. The result locates the first and the worst offending pixels in a's coordinates.
+func Equal(cfg core.Config, a, b image.Image, tolerance uint8) (Result, error) {
+	pa, pb, err := load(a, b)
+	if err != nil {
+		return Result{}, err
+	}
+	type rowResult struct {
+		mismatches   int
+		first, worst int
+		diff         uint8
+	}
+	h, w := pa.Rect.Dy(), pa.Rect.Dx()
+	rows := make([]rowResult, h)
+	core.IterateRows(cfg.PixelIterator(), h, func(y int) {
+		ra, rb := pa.Pix[y*pa.Stride:], pb.Pix[y*pb.Stride:]
+		res := rowResult{first: -1}
+		for x := range w {
+			var d uint8
+			for c := range 4 {
+				d = max(d, absDiff(ra[x*4+c], rb[x*4+c]))
+			}
+			if d > res.diff {
+				res.diff, res.worst = d, x
+			}
+			if d > tolerance {
+				res.mismatches++
+				if res.first < 0 {
+					res.first = x
+				}
+			}
+		}
+		rows[y] = res
+	})
+
+	origin := a.Bounds().Min
+	out := Result{Equal: true}
+	for y, res := range rows {
+		if res.mismatches > 0 && out.Equal {
+			out.Equal = false
+			out.First = origin.Add(image.Pt(res.first, y))
+		}
+		out.Mismatches += res.mismatches
+		if res.diff > out.MaxDiff {
+			out.MaxDiff = res.diff
+			out.Worst = origin.Add(image.Pt(res.worst, y))
+		}
+	}
+	return out, nil
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
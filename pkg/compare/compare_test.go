@@ -0,0 +1,241 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+// gradient returns an opaque test image with smooth structure.
+func gradient(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(10, 20, 10+w, 20+h))
+	for y := range h {
+		for x := range w {
+			v := 128 + 100*math.Sin(float64(x)/5)*math.Cos(float64(y)/7)
+			img.SetNRGBA(10+x, 20+y, color.NRGBA{R: uint8(v), G: uint8(255 - v), B: uint8(x * 2), A: 255})
+		}
+	}
+	return img
+}
+
+// noisy returns a copy of img with uniform noise of the given amplitude added.
+func noisy(img *image.NRGBA, amplitude int, seed uint64) *image.NRGBA {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	out := image.NewNRGBA(img.Rect)
+	copy(out.Pix, img.Pix)
+	for i := range out.Pix {
+		if i%4 == 3 {
+			continue
+		}
+		v := int(out.Pix[i]) + rng.IntN(2*amplitude+1) - amplitude
+		out.Pix[i] = uint8(max(0, min(255, v)))
+	}
+	return out
+}
+
+func TestMSEAndPSNR(t *testing.T) {
+	cfg := internal.DefaultConfig
+	a := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	b := image.NewNRGBA(image.Rect(5, 5, 7, 7))
+	for i := range 4 {
+		a.Pix[i*4+3], b.Pix[i*4+3] = 255, 255
+	}
+	b.SetNRGBA(6, 6, color.NRGBA{R: 10, A: 255})
+
+	mse, err := MSE(cfg, a, b)
+	if err != nil {
+		t.Fatalf("MSE failed: %v", err)
+	}
+	if mse != 100.0/16 {
+		t.Errorf("MSE = %v, want %v", mse, 100.0/16)
+	}
+	psnr, _ := PSNR(cfg, a, b)
+	if want := 10 * math.Log10(255*255/(100.0/16)); math.Abs(psnr-want) > 1e-9 {
+		t.Errorf("PSNR = %v, want %v", psnr, want)
+	}
+	if psnr, _ = PSNR(cfg, a, a); !math.IsInf(psnr, 1) {
+		t.Errorf("PSNR of identical images = %v, want +Inf", psnr)
+	}
+
+	// The color of transparent pixels is ignored.
+	c := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	d := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	d.SetNRGBA(0, 0, color.NRGBA{R: 255, G: 255})
+	if mse, _ = MSE(cfg, c, d); mse != 0 {
+		t.Errorf("MSE of transparent pixels = %v, want 0", mse)
+	}
+
+	if _, err = MSE(cfg, a, image.NewNRGBA(image.Rect(0, 0, 3, 2))); err == nil {
+		t.Error("expected error for different sizes")
+	}
+}
+
+func TestSSIM(t *testing.T) {
+	cfg := internal.DefaultConfig
+	img := gradient(64, 48)
+	if s, _ := SSIM(cfg, img, img); math.Abs(s-1) > 1e-6 {
+		t.Errorf("SSIM of identical images = %v, want 1", s)
+	}
+	low, _ := SSIM(cfg, img, noisy(img, 5, 1))
+	high, _ := SSIM(cfg, img, noisy(img, 40, 1))
+	if !(low < 1 && high < low) {
+		t.Errorf("SSIM should decrease with noise: %v, %v", low, high)
+	}
+
+	if s, _ := MSSSIM(cfg, img, img); math.Abs(s-1) > 1e-6 {
+		t.Errorf("MS-SSIM of identical images = %v, want 1", s)
+	}
+	low, _ = MSSSIM(cfg, img, noisy(img, 5, 2))
+	high, _ = MSSSIM(cfg, img, noisy(img, 40, 2))
+	if !(low < 1 && high < low) {
+		t.Errorf("MS-SSIM should decrease with noise: %v, %v", low, high)
+	}
+
+	// Alpha changes are detected even when the premultiplied color over black is the same.
+	clear := image.NewNRGBA(img.Rect)
+	black := image.NewNRGBA(img.Rect)
+	for i := 3; i < len(black.Pix); i += 4 {
+		black.Pix[i] = 255
+	}
+	if s, _ := SSIM(cfg, clear, black); s > 0.9 {
+		t.Errorf("SSIM of transparent and opaque black = %v, want low", s)
+	}
+}
+
+func TestCIEDE2000(t *testing.T) {
+	// Reference pairs from Sharma, Wu and Dalal (2005).
+	tests := []struct {
+		c1, c2 Lab
+		want   float64
+	}{
+		{Lab{50, 2.6772, -79.7751}, Lab{50, 0, -82.7485}, 2.0425},
+		{Lab{50, 0, 0}, Lab{50, -1, 2}, 2.3669},
+		{Lab{50, 2.5, 0}, Lab{73, 25, -18}, 27.1492},
+		{Lab{60.2574, -34.0099, 36.2677}, Lab{60.4626, -34.1751, 39.4387}, 1.2644},
+		{Lab{22.7233, 20.0904, -46.6940}, Lab{23.0331, 14.9730, -42.5619}, 2.0373},
+		{Lab{2.0776, 0.0795, -1.1350}, Lab{0.9033, -0.0636, -0.5514}, 0.9082},
+	}
+	for _, tt := range tests {
+		if got := CIEDE2000(tt.c1, tt.c2); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("CIEDE2000(%v, %v) = %.4f, want %.4f", tt.c1, tt.c2, got, tt.want)
+		}
+		if got := CIEDE2000(tt.c2, tt.c1); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("CIEDE2000 is not symmetric for %v, %v", tt.c1, tt.c2)
+		}
+	}
+
+	if lab := ToLab(color.White); math.Abs(lab.L-100) > 0.01 || math.Abs(lab.A) > 0.01 || math.Abs(lab.B) > 0.01 {
+		t.Errorf("ToLab(white) = %v, want {100 0 0}", lab)
+	}
+}
+
+func TestDeltaE(t *testing.T) {
+	cfg := internal.DefaultConfig
+	a := image.NewNRGBA(image.Rect(0, 0, 3, 1))
+	b := image.NewNRGBA(image.Rect(0, 0, 3, 1))
+	a.SetNRGBA(0, 0, color.NRGBA{R: 255})                       // transparent colors are ignored
+	a.SetNRGBA(1, 0, color.NRGBA{A: 255})                       // opaque black
+	b.SetNRGBA(2, 0, color.NRGBA{R: 200, G: 10, B: 10, A: 255}) // red on transparent
+	m, err := DeltaE(cfg, a, b)
+	if err != nil {
+		t.Fatalf("DeltaE failed: %v", err)
+	}
+	if d := m.At(0, 0); d != 0 {
+		t.Errorf("transparent difference = %v, want 0", d)
+	}
+	if d := m.At(1, 0); d < 10 {
+		t.Errorf("opaque vs transparent black difference = %v, want large", d)
+	}
+	mx, at := m.Max()
+	if at != image.Pt(1, 0) && at != image.Pt(2, 0) || mx < m.At(1, 0) || mx < m.At(2, 0) {
+		t.Errorf("Max = %v at %v", mx, at)
+	}
+	if mean := m.Mean(); math.Abs(mean-(m.At(1, 0)+m.At(2, 0))/3) > 1e-4 {
+		t.Errorf("Mean = %v", mean)
+	}
+	if p := m.Percentile(0); p != 0 {
+		t.Errorf("Percentile(0) = %v, want 0", p)
+	}
+	if p := m.Percentile(1); p != mx {
+		t.Errorf("Percentile(1) = %v, want %v", p, mx)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	cfg := internal.DefaultConfig
+	a := gradient(8, 6)
+	b := image.NewNRGBA(a.Rect)
+	copy(b.Pix, a.Pix)
+
+	res, err := Equal(cfg, a, b, 0)
+	if err != nil || !res.Equal || res.Mismatches != 0 {
+		t.Fatalf("Equal of identical images = %+v, %v", res, err)
+	}
+
+	shift := func(x, y, c, d int) {
+		i := b.PixOffset(x, y) + c
+		if b.Pix[i] >= 128 {
+			d = -d
+		}
+		b.Pix[i] = uint8(int(b.Pix[i]) + d)
+	}
+	shift(13, 22, 0, 3)  // small difference
+	shift(11, 24, 1, 64) // large difference
+	shift(17, 25, 2, 1)  // within tolerance
+	res, _ = Equal(cfg, a, b, 2)
+	if res.Equal {
+		t.Fatal("images should differ")
+	}
+	if res.Mismatches != 2 {
+		t.Errorf("Mismatches = %d, want 2", res.Mismatches)
+	}
+	if res.First != image.Pt(13, 22) {
+		t.Errorf("First = %v, want (13, 22)", res.First)
+	}
+	if res.Worst != image.Pt(11, 24) {
+		t.Errorf("Worst = %v, want (11, 24)", res.Worst)
+	}
+	if res.MaxDiff < 3 {
+		t.Errorf("MaxDiff = %d", res.MaxDiff)
+	}
+
+	// RGBA and NRGBA inputs with the same colors are equal.
+	rgba := image.NewRGBA(a.Rect)
+	for y := a.Rect.Min.Y; y < a.Rect.Max.Y; y++ {
+		for x := a.Rect.Min.X; x < a.Rect.Max.X; x++ {
+			rgba.Set(x, y, a.At(x, y))
+		}
+	}
+	if res, _ = Equal(cfg, a, rgba, 0); !res.Equal {
+		t.Errorf("NRGBA and RGBA copies differ: %+v", res)
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	cfg := internal.DefaultConfig
+	a := gradient(8, 8)
+	b := image.NewNRGBA(a.Rect)
+	copy(b.Pix, a.Pix)
+	b.SetNRGBA(12, 23, color.NRGBA{R: 0, G: 0, B: 255, A: 255})
+
+	h, err := Heatmap(cfg, a, b, 0)
+	if err != nil {
+		t.Fatalf("Heatmap failed: %v", err)
+	}
+	if h.Rect != a.Rect {
+		t.Errorf("bounds = %v, want %v", h.Rect, a.Rect)
+	}
+	if c := h.NRGBAAt(12, 23); c != (color.NRGBA{R: 255, A: 255}) {
+		t.Errorf("changed pixel = %v, want red", c)
+	}
+	if c := h.NRGBAAt(10, 20); c.R != c.G || c.G != c.B || c.R < 191 {
+		t.Errorf("unchanged pixel = %v, want light gray", c)
+	}
+}
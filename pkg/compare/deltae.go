@@ -0,0 +1,201 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// Lab is a color in the CIE L*a*b* color space with a D65 white point.
+type Lab struct {
+	L, A, B float64
+}
+
+// ToLab converts an sRGB color to CIE L*a*b*. Alpha is ignored.
+func ToLab(c color.Color) Lab {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA) //nolint:errcheck
+	return labFromSRGB(float64(nc.R)/255, float64(nc.G)/255, float64(nc.B)/255)
+}
+
+// labFromSRGB converts sRGB components in [0, 1] to L*a*b*.
+func labFromSRGB(r, g, b float64) Lab {
+	lin := func(v float64) float64 {
+		if v <= 0.04045 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	r, g, b = lin(r), lin(g), lin(b)
+	x := (0.4124564*r + 0.3575761*g + 0.1804375*b) / 0.95047
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z := (0.0193339*r + 0.1191920*g + 0.9503041*b) / 1.08883
+	f := func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return Lab{L: 116*fy - 16, A: 500 * (fx - fy), B: 200 * (fy - fz)}
+}
+
+// CIEDE2000 returns the CIEDE2000 color difference between two colors with unit weighting
+// factors. A difference of about 1 is just noticeable.
+func CIEDE2000(c1, c2 Lab) float64 {
+	deg := math.Pi / 180
+	cab1 := math.Hypot(c1.A, c1.B)
+	cab2 := math.Hypot(c2.A, c2.B)
+	cab := (cab1 + cab2) / 2
+	cab7 := math.Pow(cab, 7)
+	g := 0.5 * (1 - math.Sqrt(cab7/(cab7+math.Pow(25, 7))))
+	a1, a2 := (1+g)*c1.A, (1+g)*c2.A
+	cp1, cp2 := math.Hypot(a1, c1.B), math.Hypot(a2, c2.B)
+
+	hue := func(b, a float64) float64 {
+		if a == 0 && b == 0 {
+			return 0
+		}
+		h := math.Atan2(b, a)
+		if h < 0 {
+			h += 2 * math.Pi
+		}
+		return h
+	}
+	hp1, hp2 := hue(c1.B, a1), hue(c2.B, a2)
+
+	dL := c2.L - c1.L
+	dC := cp2 - cp1
+	var dh float64
+	if cp1*cp2 != 0 {
+		dh = hp2 - hp1
+		switch {
+		case dh > math.Pi:
+			dh -= 2 * math.Pi
+		case dh < -math.Pi:
+			dh += 2 * math.Pi
+		}
+	}
+	dH := 2 * math.Sqrt(cp1*cp2) * math.Sin(dh/2)
+
+	lp := (c1.L + c2.L) / 2
+	cp := (cp1 + cp2) / 2
+	hp := hp1 + hp2
+	if cp1*cp2 != 0 {
+		switch {
+		case math.Abs(hp1-hp2) <= math.Pi:
+			hp /= 2
+		case hp < 2*math.Pi:
+			hp = (hp + 2*math.Pi) / 2
+		default:
+			hp = (hp - 2*math.Pi) / 2
+		}
+	}
+
+	t := 1 - 0.17*math.Cos(hp-30*deg) + 0.24*math.Cos(2*hp) +
+		0.32*math.Cos(3*hp+6*deg) - 0.20*math.Cos(4*hp-63*deg)
+	dTheta := 30 * deg * math.Exp(-math.Pow((hp/deg-275)/25, 2))
+	cp7 := math.Pow(cp, 7)
+	rc := 2 * math.Sqrt(cp7/(cp7+math.Pow(25, 7)))
+	l50 := (lp - 50) * (lp - 50)
+	sl := 1 + 0.015*l50/math.Sqrt(20+l50)
+	sc := 1 + 0.045*cp
+	sh := 1 + 0.015*cp*t
+	rt := -math.Sin(2*dTheta) * rc
+
+	l, c, h := dL/sl, dC/sc, dH/sh
+	return math.Sqrt(l*l + c*c + h*h + rt*c*h)
+}
+
+// DeltaEMap holds the CIEDE2000 difference of each pixel of two images.
+type DeltaEMap struct {
+	// Rect is the bounds of the first image.
+	Rect image.Rectangle
+	// Pix holds the differences in row-major order, Rect.Dx() per row.
+	Pix []float32
+}
+
+// At returns the difference at (x, y), or 0 outside of Rect.
+func (m *DeltaEMap) At(x, y int) float64 {
+	if !image.Pt(x, y).In(m.Rect) {
+		return 0
+	}
+	return float64(m.Pix[(y-m.Rect.Min.Y)*m.Rect.Dx()+x-m.Rect.Min.X])
+}
+
+// Mean returns the average difference over all pixels.
+func (m *DeltaEMap) Mean() float64 {
+	if len(m.Pix) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range m.Pix {
+		sum += float64(v)
+	}
+	return sum / float64(len(m.Pix))
+}
+
+// Max returns the largest difference and the first pixel where it occurs.
+func (m *DeltaEMap) Max() (float64, image.Point) {
+	var mx float32
+	var at int
+	for i, v := range m.Pix {
+		if v > mx {
+			mx, at = v, i
+		}
+	}
+	if len(m.Pix) == 0 {
+		return 0, m.Rect.Min
+	}
+	w := m.Rect.Dx()
+	return float64(mx), m.Rect.Min.Add(image.Pt(at%w, at/w))
+}
+
+// Percentile returns the difference below which the fraction p in [0, 1] of pixels fall.
+func (m *DeltaEMap) Percentile(p float64) float64 {
+	if len(m.Pix) == 0 {
+		return 0
+	}
+	sorted := append([]float32(nil), m.Pix...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	i := int(math.Round(core.Clamp(p, 0, 1) * float64(len(sorted)-1)))
+	return float64(sorted[i])
+}
+
+// DeltaE returns the CIEDE2000 difference of each pixel of a and b. To account for alpha,
+// each pixel is composited over black and over white and the larger difference is used, so
+// pixels only match when both their colors and their alpha match.
+func DeltaE(cfg core.Config, a, b image.Image) (*DeltaEMap, error) {
+	pa, pb, err := load(a, b)
+	if err != nil {
+		return nil, err
+	}
+	w, h := pa.Rect.Dx(), pa.Rect.Dy()
+	m := &DeltaEMap{Rect: a.Bounds(), Pix: make([]float32, w*h)}
+	core.IterateRows(cfg.PixelIterator(), h, func(y int) {
+		ra, rb := pa.Pix[y*pa.Stride:], pb.Pix[y*pb.Stride:]
+		out := m.Pix[y*w : (y+1)*w]
+		for x := range out {
+			ca, cb := ra[x*4:x*4+4], rb[x*4:x*4+4]
+			if ca[0] == cb[0] && ca[1] == cb[1] && ca[2] == cb[2] && ca[3] == cb[3] {
+				continue
+			}
+			out[x] = float32(max(
+				CIEDE2000(over(ca, 0), over(cb, 0)),
+				CIEDE2000(over(ca, 255), over(cb, 255)),
+			))
+		}
+	})
+	return m, nil
+}
+
+// over returns the Lab color of a premultiplied pixel composited over a gray background.
+func over(p []uint8, bg float64) Lab {
+	k := (255 - float64(p[3])) * bg / 255
+	return labFromSRGB((float64(p[0])+k)/255, (float64(p[1])+k)/255, (float64(p[2])+k)/255)
+}
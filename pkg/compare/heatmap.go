@@ -0,0 +1,62 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// DefaultHeatmapScale is the CIEDE2000 difference shown at full intensity when Heatmap is
+// called with a scale of zero.
+const DefaultHeatmapScale = 10
+
+// heatmapStops is the color ramp used for changed pixels, from barely changed to scale.
+var heatmapStops = []color.NRGBA{
+	{B: 255, A: 255},
+	{R: 255, G: 255, A: 255},
+	{R: 255, A: 255},
+}
+
+// Heatmap returns an opaque image with the bounds of a highlighting where a and b differ.
+// Unchanged pixels show a faded grayscale copy of a. Changed pixels are colored from blue
+// through yellow to red as their CIEDE2000 difference approaches scale.
+func Heatmap(cfg core.Config, a, b image.Image, scale float64) (*image.NRGBA, error) {
+	m, err := DeltaE(cfg, a, b)
+	if err != nil {
+		return nil, err
+	}
+	if scale <= 0 {
+		scale = DefaultHeatmapScale
+	}
+	pixIter := cfg.PixelIterator()
+	background := luma(pixIter, premultiplied(a), true)
+
+	out := image.NewNRGBA(m.Rect)
+	w := m.Rect.Dx()
+	core.IterateRows(pixIter, m.Rect.Dy(), func(y int) {
+		pix := out.Pix[y*out.Stride:]
+		for x, d := range m.Pix[y*w : (y+1)*w] {
+			c := ramp(float64(d) / scale)
+			if d == 0 {
+				v := uint8(background.Row(y)[x]/4 + 191.5)
+				c = color.NRGBA{R: v, G: v, B: v, A: 255}
+			}
+			pix[x*4], pix[x*4+1], pix[x*4+2], pix[x*4+3] = c.R, c.G, c.B, c.A
+		}
+	})
+	return out, nil
+}
+
+// ramp interpolates the heatmap stops for t in [0, 1].
+func ramp(t float64) color.NRGBA {
+	t = core.Clamp(t, 0, 1) * float64(len(heatmapStops)-1)
+	i := min(int(t), len(heatmapStops)-2)
+	f := t - float64(i)
+	a, b := heatmapStops[i], heatmapStops[i+1]
+	mix := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*f + 0.5) }
+	return color.NRGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 255}
+}
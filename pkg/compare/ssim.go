@@ -0,0 +1,154 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"image"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal/plane"
+)
+
+// SSIM window and stabilization constants from Wang et al. A sigma of 1.5 gives the
+// standard 11x11 Gaussian window.
+const (
+	ssimSigma = 1.5
+	ssimC1    = (0.01 * 255) * (0.01 * 255)
+	ssimC2    = (0.03 * 255) * (0.03 * 255)
+)
+
+// msssimWeights are the per-scale exponents of MS-SSIM, from the finest to the coarsest scale.
+var msssimWeights = []float64{0.0448, 0.2856, 0.3001, 0.2363, 0.1333}
+
+// SSIM returns the mean structural similarity of a and b in [-1, 1], where 1 means identical.
+//
+// SSIM is computed on luma with an 11x11 Gaussian window. To account for alpha, the images are
+// composited over black and over white and the result is the average of both comparisons.
+func SSIM(cfg core.Config, a, b image.Image) (float64, error) {
+	pa, pb, err := load(a, b)
+	if err != nil {
+		return 0, err
+	}
+	if pa.Rect.Empty() {
+		return 1, nil
+	}
+	pixIter := cfg.PixelIterator()
+	var total float64
+	for _, white := range []bool{false, true} {
+		x, y := luma(pixIter, pa, white), luma(pixIter, pb, white)
+		s, _ := ssim(pixIter, x, y)
+		total += s
+	}
+	return total / 2, nil
+}
+
+// MSSSIM returns the multi-scale structural similarity of a and b using the five scales and
+// weights of Wang et al. Images too small for five scales use as many as fit, with the weights
+// renormalized. Alpha is handled as in SSIM.
+func MSSSIM(cfg core.Config, a, b image.Image) (float64, error) {
+	pa, pb, err := load(a, b)
+	if err != nil {
+		return 0, err
+	}
+	if pa.Rect.Empty() {
+		return 1, nil
+	}
+	pixIter := cfg.PixelIterator()
+
+	scales := 1
+	for w, h := pa.Rect.Dx()/2, pa.Rect.Dy()/2; scales < len(msssimWeights) && min(w, h) >= 11; w, h = w/2, h/2 {
+		scales++
+	}
+	weights := msssimWeights[:scales]
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+
+	var total float64
+	for _, white := range []bool{false, true} {
+		x, y := luma(pixIter, pa, white), luma(pixIter, pb, white)
+		result := 1.0
+		for i, w := range weights {
+			s, cs := ssim(pixIter, x, y)
+			if i == len(weights)-1 {
+				cs = s
+			}
+			result *= math.Pow(max(cs, 0), w/sum)
+			x, y = downsample(pixIter, x), downsample(pixIter, y)
+		}
+		total += result
+	}
+	return total / 2, nil
+}
+
+// luma returns the Rec. 601 luma of a premultiplied image composited over black or white.
+func luma(pixIter core.PixelIterator, img *image.RGBA, white bool) *plane.Plane {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	p := plane.New(w, h)
+	core.IterateRows(pixIter, h, func(y int) {
+		pix := img.Pix[y*img.Stride:]
+		row := p.Row(y)
+		for x := range row {
+			i := x * 4
+			v := 0.299*float32(pix[i]) + 0.587*float32(pix[i+1]) + 0.114*float32(pix[i+2])
+			if white {
+				// The luma weights sum to one, so compositing over white adds 255-alpha.
+				v += 255 - float32(pix[i+3])
+			}
+			row[x] = v
+		}
+	})
+	return p
+}
+
+// ssim returns the mean SSIM and the mean contrast-structure term of two planes.
+func ssim(pixIter core.PixelIterator, x, y *plane.Plane) (float64, float64) {
+	blur := func(p *plane.Plane) *plane.Plane { return plane.GaussianBlur(pixIter, p, ssimSigma) }
+	product := func(a, b *plane.Plane) *plane.Plane {
+		out := plane.New(a.W, a.H)
+		for i, v := range a.Pix {
+			out.Pix[i] = v * b.Pix[i]
+		}
+		return out
+	}
+	muX, muY := blur(x), blur(y)
+	xx, yy, xy := blur(product(x, x)), blur(product(y, y)), blur(product(x, y))
+
+	type sums struct{ s, cs float64 }
+	rows := make([]sums, x.H)
+	core.IterateRows(pixIter, x.H, func(row int) {
+		var res sums
+		for i := row * x.W; i < (row+1)*x.W; i++ {
+			mx, my := float64(muX.Pix[i]), float64(muY.Pix[i])
+			vx := float64(xx.Pix[i]) - mx*mx
+			vy := float64(yy.Pix[i]) - my*my
+			cov := float64(xy.Pix[i]) - mx*my
+			cs := (2*cov + ssimC2) / (vx + vy + ssimC2)
+			res.cs += cs
+			res.s += (2*mx*my + ssimC1) / (mx*mx + my*my + ssimC1) * cs
+		}
+		rows[row] = res
+	})
+	var total sums
+	for _, r := range rows {
+		total.s += r.s
+		total.cs += r.cs
+	}
+	n := float64(x.W * x.H)
+	return total.s / n, total.cs / n
+}
+
+// downsample halves a plane by averaging 2x2 blocks. Odd trailing rows and columns are dropped.
+func downsample(pixIter core.PixelIterator, p *plane.Plane) *plane.Plane {
+	out := plane.New(max(p.W/2, 1), max(p.H/2, 1))
+	core.IterateRows(pixIter, out.H, func(y int) {
+		row := out.Row(y)
+		for x := range row {
+			row[x] = (p.At(2*x, 2*y) + p.At(2*x+1, 2*y) + p.At(2*x, 2*y+1) + p.At(2*x+1, 2*y+1)) / 4
+		}
+	})
+	return out
+}
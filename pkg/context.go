@@ -32,6 +32,16 @@ type Context interface {
 	// this function writes to output which may be the destination image, a provided image, or a new image.
 	// Returns the modified output image.
 	Composite(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op op.CompositeOp, out core.Output) (image.Image, error)
+
+	// Draw applies any operation, including ones implemented outside of Magpie.
+	// See core.Op for the contract an operation must follow.
+	// It follows the same semantics as Blend and Composite.
+	Draw(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op core.Op, out core.Output) (image.Image, error)
+
+	// DrawWrapped applies any operation like Draw, with src extended beyond its bounds as wrap
+	// describes. sp may lie outside of the source bounds, and r is not clipped to the source, so a
+	// small source can tile the whole of r in a single pass. See core.SourceWrap.
+	DrawWrapped(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, wrap core.SourceWrap, op core.Op, out core.Output) (image.Image, error)
 }
 
 // context implements the Context interface.
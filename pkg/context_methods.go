@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"image/draw"
 
 	"github.com/blazeroni/magpie/pkg/core"
 	"github.com/blazeroni/magpie/pkg/internal"
@@ -31,53 +30,54 @@ func (ctx *context) DefaultColorModel() color.Model {
 }
 
 func (ctx *context) Blend(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op op.BlendOp, output core.Output) (image.Image, error) {
-	return ctx.draw2(dst, r, src, sp, op, output)
+	return ctx.draw2(dst, r, src, sp, core.SourceWrap{}, op, output)
 }
 
 func (ctx *context) Composite(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op op.CompositeOp, output core.Output) (image.Image, error) {
-	return ctx.draw2(dst, r, src, sp, op, output)
+	return ctx.draw2(dst, r, src, sp, core.SourceWrap{}, op, output)
 }
 
-// draw2 is the internal drawing function that handles both blend and composite operations.
-func (ctx *context) draw2(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op internal.Op, output core.Output) (image.Image, error) {
+func (ctx *context) Draw(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op core.Op, output core.Output) (image.Image, error) {
+	if op == nil {
+		return nil, fmt.Errorf("invalid operation")
+	}
+	return ctx.draw2(dst, r, src, sp, core.SourceWrap{}, op, output)
+}
+
+func (ctx *context) DrawWrapped(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, wrap core.SourceWrap, op core.Op, output core.Output) (image.Image, error) {
+	if op == nil {
+		return nil, fmt.Errorf("invalid operation")
+	}
+	return ctx.draw2(dst, r, src, sp, wrap, op, output)
+}
+
+// draw2 is the internal drawing function that handles every operation.
+func (ctx *context) draw2(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, wrap core.SourceWrap, op core.Op, output core.Output) (image.Image, error) {
 	if !op.IsValid() {
 		return nil, fmt.Errorf("invalid operation")
 	}
+	if !wrap.IsValid() {
+		return nil, fmt.Errorf("invalid source wrap")
+	}
 
 	// Decide on a color model
 	clrModel, err := colorModel(dst, src, output)
 	if err != nil {
 		return nil, err
 	}
-	var outputMode core.OutputMode
-	if output != nil {
-		outputMode = output.OutputMode()
-	} else {
-		outputMode = ctx.DefaultOutputMode().ToOutputMode()
-	}
-
-	var out image.Image
-	var outPt image.Point
-	switch outputMode {
-	case core.OutputToDst:
-		out = dst
-		outPt = r.Min
-	case core.OutputToNewImage:
-		out, outPt = newImage(clrModel, r)
-	case core.OutputToProvidedImage:
-		out, outPt = output.ProvidedImage()
-	default:
-		return nil, fmt.Errorf("unsupported output mode %v", outputMode)
+	out, outPt, err := core.ResolveOutput(output, ctx.DefaultOutputMode(), dst, r, clrModel)
+	if err != nil {
+		return nil, err
 	}
 
 	switch clrModel {
 	case color.RGBAModel:
 		dstRGBA, srcRGBA, outRGBA := AsRGBA(dst), AsRGBA(src), AsRGBA(out)
-		calc := core.NewPixCalculatorRGBA(dstRGBA, r, srcRGBA, sp, outRGBA, outPt)
+		calc := core.NewWrappedPixCalculatorRGBA(dstRGBA, r, srcRGBA, sp, wrap, outRGBA, outPt)
 		return op.ApplyRGBA(ctx.PixelIterator(), calc), nil
 	case color.NRGBAModel:
 		dstNRGBA, srcNRGBA, outNRGBA := AsNRGBA(dst), AsNRGBA(src), AsNRGBA(out)
-		calc := core.NewPixCalculatorNRGBA(dstNRGBA, r, srcNRGBA, sp, outNRGBA, outPt)
+		calc := core.NewWrappedPixCalculatorNRGBA(dstNRGBA, r, srcNRGBA, sp, wrap, outNRGBA, outPt)
 		return op.ApplyNRGBA(ctx.PixelIterator(), calc), nil
 	default:
 		return nil, fmt.Errorf("unsupported color model %v", clrModel)
@@ -86,14 +86,7 @@ func (ctx *context) draw2(dst image.Image, r image.Rectangle, src image.Image, s
 
 // newImage creates a new image with the given color model and bounds.
 func newImage(colorModel color.Model, bounds image.Rectangle) (image.Image, image.Point) {
-	switch colorModel {
-	case color.NRGBAModel:
-		return image.NewNRGBA(bounds), bounds.Min
-	case color.RGBAModel:
-		return image.NewRGBA(bounds), bounds.Min
-	default:
-		return nil, image.Point{}
-	}
+	return core.NewImage(colorModel, bounds)
 }
 
 // colorModel determines the best color model to use for an operation
@@ -123,24 +116,12 @@ func colorModel(dst image.Image, src image.Image, out core.Output) (color.Model,
 // this format, it is returned directly. Otherwise, a new NRGBA image is created
 // and the content is drawn onto it.
 func AsNRGBA(img image.Image) *image.NRGBA {
-	if rgba, ok := img.(*image.NRGBA); ok {
-		return rgba
-	}
-	bounds := img.Bounds()
-	out := image.NewNRGBA(bounds)
-	draw.Draw(out, bounds, img, bounds.Min, draw.Src)
-	return out
+	return internal.AsNRGBA(img)
 }
 
 // AsRGBA returns the image as an *image.RGBA. If the image is already in
 // this format, it is returned directly. Otherwise, a new RGBA image is created
 // and the content is drawn onto it.
 func AsRGBA(img image.Image) *image.RGBA {
-	if rgba, ok := img.(*image.RGBA); ok {
-		return rgba
-	}
-	bounds := img.Bounds()
-	out := image.NewRGBA(bounds)
-	draw.Draw(out, bounds, img, bounds.Min, draw.Over)
-	return out
+	return internal.AsRGBA(img)
 }
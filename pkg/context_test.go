@@ -4,8 +4,10 @@
 package magpie
 
 import (
+	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
 	"testing"
 
 	"github.com/blazeroni/magpie/pkg/core"
@@ -198,7 +200,7 @@ func TestDraw2(t *testing.T) {
 
 	t.Run("NRGBA model", func(t *testing.T) {
 		mock := &mockOp{}
-		_, err := ctx.draw2(dst, rect, src, image.Point{}, mock, nil)
+		_, err := ctx.draw2(dst, rect, src, image.Point{}, core.SourceWrap{}, mock, nil)
 		if err != nil {
 			t.Fatalf("draw2 failed: %v", err)
 		}
@@ -213,7 +215,7 @@ func TestDraw2(t *testing.T) {
 	t.Run("RGBA model", func(t *testing.T) {
 		mock := &mockOp{}
 		dstRGBA := image.NewRGBA(rect)
-		_, err := ctx.draw2(dstRGBA, rect, src, image.Point{}, mock, nil)
+		_, err := ctx.draw2(dstRGBA, rect, src, image.Point{}, core.SourceWrap{}, mock, nil)
 		if err != nil {
 			t.Fatalf("draw2 failed: %v", err)
 		}
@@ -227,7 +229,7 @@ func TestDraw2(t *testing.T) {
 
 	t.Run("OutputToDst", func(t *testing.T) {
 		mock := &mockOp{}
-		result, err := ctx.draw2(dst, rect, src, image.Point{}, mock, ToDst())
+		result, err := ctx.draw2(dst, rect, src, image.Point{}, core.SourceWrap{}, mock, ToDst())
 		if err != nil {
 			t.Fatalf("draw2 failed: %v", err)
 		}
@@ -238,7 +240,7 @@ func TestDraw2(t *testing.T) {
 
 	t.Run("OutputToNewImage", func(t *testing.T) {
 		mock := &mockOp{}
-		result, err := ctx.draw2(dst, rect, src, image.Point{}, mock, ToNewImage())
+		result, err := ctx.draw2(dst, rect, src, image.Point{}, core.SourceWrap{}, mock, ToNewImage())
 		if err != nil {
 			t.Fatalf("draw2 failed: %v", err)
 		}
@@ -253,7 +255,7 @@ func TestDraw2(t *testing.T) {
 	t.Run("OutputToProvidedImage", func(t *testing.T) {
 		mock := &mockOp{}
 		providedImg := image.NewNRGBA(rect)
-		result, err := ctx.draw2(dst, rect, src, image.Point{}, mock, ToImage(providedImg, image.Point{}))
+		result, err := ctx.draw2(dst, rect, src, image.Point{}, core.SourceWrap{}, mock, ToImage(providedImg, image.Point{}))
 		if err != nil {
 			t.Fatalf("draw2 failed: %v", err)
 		}
@@ -269,10 +271,216 @@ func TestDraw2(t *testing.T) {
 		SetDefaultContext(NewContext(func(c *context) {
 			_ = c.config.SetDefaultColorModel(color.GrayModel)
 		}))
-		_, err := ctx.draw2(unsupportedImg, rect, unsupportedImg, image.Point{}, mock, nil)
+		_, err := ctx.draw2(unsupportedImg, rect, unsupportedImg, image.Point{}, core.SourceWrap{}, mock, nil)
 		if err == nil {
 			t.Error("draw2 should return an error for unsupported color models")
 		}
 		SetDefaultContext(NewContext()) // reset
 	})
 }
+
+func TestDrawCustomBlend(t *testing.T) {
+	o := op.CustomBlendOp{
+		Blend:       op.NewCustomBlend(func(s, d uint8) uint8 { return 255 - d }),
+		Compositing: op.CompositeAll,
+	}
+	rect := image.Rect(0, 0, 2, 1)
+	dst := image.NewNRGBA(rect)
+	dst.SetNRGBA(0, 0, color.NRGBA{R: 10, G: 20, B: 30, A: 255})
+	src := image.NewNRGBA(rect)
+	src.SetNRGBA(0, 0, color.NRGBA{A: 255})
+
+	out, err := Draw(dst, rect, src, image.Point{}, o, ToNewImage())
+	if err != nil {
+		t.Fatalf("Draw failed: %v", err)
+	}
+	if got, want := out.(*image.NRGBA).NRGBAAt(0, 0), (color.NRGBA{R: 245, G: 235, B: 225, A: 255}); got != want {
+		t.Errorf("blended pixel = %v, want %v", got, want)
+	}
+
+	if _, err = Draw(dst, rect, src, image.Point{}, op.CustomBlendOp{Compositing: op.CompositeAll}, ToNewImage()); err == nil {
+		t.Error("expected an error for a custom blend without a function")
+	}
+}
+
+// maskOp is an operation implemented outside of the op package: it keeps the destination where
+// the source is opaque and clears it elsewhere.
+type maskOp struct{}
+
+func (maskOp) IsValid() bool { return true }
+
+func (maskOp) ApplyNRGBA(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA]) *image.NRGBA {
+	return core.Iterate(pixIter, calc, maskRow)
+}
+
+func (maskOp) ApplyRGBA(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA]) *image.RGBA {
+	return core.Iterate(pixIter, calc, maskRow)
+}
+
+func maskRow(dst, src, out []uint8) {
+	for i := 0; i < len(out); i += 4 {
+		if src[i+3] == 255 {
+			copy(out[i:i+4], dst[i:i+4])
+		} else {
+			copy(out[i:i+4], []uint8{0, 0, 0, 0})
+		}
+	}
+}
+
+func TestDrawCustomOp(t *testing.T) {
+	rect := image.Rect(0, 0, 4, 4)
+	src := image.NewNRGBA(rect)
+	for x := range 4 {
+		src.SetNRGBA(x, 1, color.NRGBA{A: 255})
+	}
+	fill := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+
+	for name, dst := range map[string]draw.Image{"NRGBA": image.NewNRGBA(rect), "RGBA": image.NewRGBA(rect)} {
+		t.Run(name, func(t *testing.T) {
+			for y := range 4 {
+				for x := range 4 {
+					dst.Set(x, y, fill)
+				}
+			}
+			// Drawing to the destination aliases out with dst.
+			out, err := Draw(dst, image.Rect(0, 0, 4, 3), src, image.Point{}, maskOp{}, ToDst())
+			if err != nil {
+				t.Fatalf("Draw failed: %v", err)
+			}
+			if out != image.Image(dst) {
+				t.Fatal("expected the destination to be modified in place")
+			}
+			for y := range 4 {
+				want := color.RGBA{}
+				if y == 1 || y == 3 {
+					want = color.RGBA{R: 200, G: 100, B: 50, A: 255}
+				}
+				if got := color.RGBAModel.Convert(dst.At(2, y)); got != want {
+					t.Errorf("row %d = %v, want %v", y, got, want)
+				}
+			}
+		})
+	}
+
+	if _, err := Draw(image.NewNRGBA(rect), rect, src, image.Point{}, nil, ToNewImage()); err == nil {
+		t.Error("expected an error for a nil operation")
+	}
+}
+
+func TestDrawWrapped(t *testing.T) {
+	ctx := &context{config: internal.NewConfig(core.NewSerialPixelIterator(), core.DefaultOutputToNewImage, color.NRGBAModel)}
+	iterators := map[string]core.PixelIterator{
+		"Serial":   core.NewSerialPixelIterator(),
+		"Parallel": core.NewParallelPixelIterator(4),
+	}
+	over := op.CompositeOp{Mode: op.SourceOver}
+	wrap := SourceWrap{Mode: WrapRepeat, Spacing: image.Pt(1, 1), RowOffset: 2}
+	sp := image.Pt(1, 0)
+	bounds := image.Rect(0, 0, 17, 21)
+
+	for name, model := range map[string]color.Model{"NRGBA": color.NRGBAModel, "RGBA": color.RGBAModel} {
+		dst := noiseImage(model, bounds, 1)
+		src := noiseImage(model, image.Rect(0, 0, 3, 2), 2)
+		for _, r := range []image.Rectangle{bounds, image.Rect(2, 10, 17, 21)} {
+			// The same tiles drawn one at a time, clipped to r. The tiles start at r.Min.
+			want, _ := newImage(model, bounds)
+			draw.Draw(want.(draw.Image), bounds, dst, image.Point{}, draw.Src)
+			for j := -1; j <= r.Dy()/3+1; j++ {
+				for i := -3; i <= r.Dx()/4+1; i++ {
+					tile := src.Bounds().Add(r.Min).Add(image.Pt(i*4+j*wrap.RowOffset, j*3).Sub(sp))
+					tr := tile.Intersect(r)
+					if tr.Empty() {
+						continue
+					}
+					if _, err := ctx.Draw(want, tr, src, tr.Min.Sub(tile.Min), over, ToImage(want, tr.Min)); err != nil {
+						t.Fatalf("Draw failed: %v", err)
+					}
+				}
+			}
+
+			for iterName, iter := range iterators {
+				t.Run(fmt.Sprintf("%s %s %v", name, iterName, r), func(t *testing.T) {
+					c := &context{config: internal.NewConfig(iter, core.DefaultOutputToNewImage, color.NRGBAModel)}
+					got, err := c.DrawWrapped(dst, r, src, sp, wrap, over, ToNewImage())
+					if err != nil {
+						t.Fatalf("DrawWrapped failed: %v", err)
+					}
+					if got.Bounds() != r {
+						t.Fatalf("bounds = %v, want %v", got.Bounds(), r)
+					}
+					for y := r.Min.Y; y < r.Max.Y; y++ {
+						for x := r.Min.X; x < r.Max.X; x++ {
+							if g, w := got.At(x, y), want.At(x, y); g != w {
+								t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, g, w)
+							}
+						}
+					}
+				})
+			}
+		}
+	}
+
+	rect := image.Rect(0, 0, 4, 4)
+	if _, err := ctx.DrawWrapped(image.NewNRGBA(rect), rect, image.NewNRGBA(rect), image.Point{}, SourceWrap{Mode: 7}, over, nil); err == nil {
+		t.Error("expected an error for an invalid wrap mode")
+	}
+	if _, err := DrawWrapped(image.NewNRGBA(rect), rect, image.NewNRGBA(rect), image.Point{}, wrap, nil, nil); err == nil {
+		t.Error("expected an error for a nil operation")
+	}
+}
+
+func TestDrawParallelOffset(t *testing.T) {
+	serial := &context{config: internal.NewConfig(core.NewSerialPixelIterator(), core.DefaultOutputToDst, color.NRGBAModel)}
+	parallel := &context{config: internal.NewConfig(core.NewParallelPixelIterator(4), core.DefaultOutputToDst, color.NRGBAModel)}
+	multiply := op.BlendOp{Mode: op.Multiply, Compositing: op.CompositeAll}
+
+	for name, model := range map[string]color.Model{"NRGBA": color.NRGBAModel, "RGBA": color.RGBAModel} {
+		t.Run(name, func(t *testing.T) {
+			bounds := image.Rect(0, 0, 40, 30)
+			src := noiseImage(model, bounds, 2)
+			// The region starts well below the top, so rows counted from the image origin would
+			// run past the end of the images.
+			r := image.Rect(3, 10, 31, 30)
+			want, got := noiseImage(model, bounds, 1), noiseImage(model, bounds, 1)
+			if _, err := serial.Draw(want, r, src, image.Pt(2, 1), multiply, ToDst()); err != nil {
+				t.Fatalf("serial Draw failed: %v", err)
+			}
+			if _, err := parallel.Draw(got, r, src, image.Pt(2, 1), multiply, ToDst()); err != nil {
+				t.Fatalf("parallel Draw failed: %v", err)
+			}
+			assertSameImage(t, got, want)
+		})
+	}
+}
+
+func TestDrawToNewImageOffset(t *testing.T) {
+	ctx := &context{config: internal.NewConfig(core.NewSerialPixelIterator(), core.DefaultOutputToDst, color.NRGBAModel)}
+	screen := op.BlendOp{Mode: op.Screen, Compositing: op.CompositeAll}
+
+	for name, model := range map[string]color.Model{"NRGBA": color.NRGBAModel, "RGBA": color.RGBAModel} {
+		t.Run(name, func(t *testing.T) {
+			dst := noiseImage(model, image.Rect(0, 0, 20, 20), 1)
+			src := noiseImage(model, image.Rect(4, 4, 24, 24), 2)
+			// r extends past dst and src; only the part of r inside both is drawn.
+			r := image.Rect(6, 8, 26, 26)
+			got, err := ctx.Draw(dst, r, src, image.Pt(5, 4), screen, ToNewImage())
+			if err != nil {
+				t.Fatalf("Draw failed: %v", err)
+			}
+
+			want, _ := newImage(model, dst.Bounds())
+			draw.Draw(want.(draw.Image), dst.Bounds(), dst, image.Point{}, draw.Src)
+			if _, err := ctx.Draw(want, r, src, image.Pt(5, 4), screen, ToDst()); err != nil {
+				t.Fatalf("Draw failed: %v", err)
+			}
+			clipped := r.Intersect(dst.Bounds()).Intersect(src.Bounds().Add(r.Min.Sub(image.Pt(5, 4))))
+			for y := clipped.Min.Y; y < clipped.Max.Y; y++ {
+				for x := clipped.Min.X; x < clipped.Max.X; x++ {
+					if g, w := got.At(x, y), want.At(x, y); g != w {
+						t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, g, w)
+					}
+				}
+			}
+		})
+	}
+}
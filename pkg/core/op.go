@@ -0,0 +1,31 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package core
+
+import "image"
+
+// Op is a per-pixel operation that combines a source image with a destination image. The
+// blend and composite operations of the op package implement it, and so can operations defined
+// outside of Magpie; Draw runs all of them the same way.
+//
+// Draw first calls IsValid and fails without touching any image if it returns false. It then
+// picks a color model and calls exactly one of ApplyNRGBA or ApplyRGBA:
+//
+//   - ApplyNRGBA receives non-premultiplied 8-bit pixels; ApplyRGBA receives premultiplied ones
+//     and must produce premultiplied results, with no color channel greater than alpha.
+//   - calc describes the region to process. Calculate(row) returns the dst, src and out pixels of
+//     row, counted from the top of calc.Rect(), as slices of 4 bytes per pixel in R, G, B, A
+//     order; the i-th pixel of each slice is at the same position.
+//   - The op should process the rows with pixIter, usually through Iterate, so that it honors
+//     the concurrency of the configuration. The callback may run concurrently for different rows
+//     and must only write to the out slice it is given.
+//   - out may alias dst or src, e.g. when drawing to the destination. A pixel's dst and src
+//     values must be read before its out value is written, and no other pixel may be read after
+//     it has been written.
+//   - The method returns calc.Result().
+type Op interface {
+	IsValid() bool
+	ApplyNRGBA(pixIter PixelIterator, calc PixCalculator[*image.NRGBA]) *image.NRGBA
+	ApplyRGBA(pixIter PixelIterator, calc PixCalculator[*image.RGBA]) *image.RGBA
+}
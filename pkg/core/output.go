@@ -4,6 +4,8 @@
 package core
 
 import (
+	"errors"
+	"fmt"
 	"image"
 	"image/color"
 )
@@ -92,6 +94,58 @@ func ToNewNRGBAImage() Output {
 	}
 }
 
+// ResolveOutput determines the image and point that an operation covering r writes to.
+// A nil output uses defaultMode. The dst image is used for OutputToDst; operations without
+// a writable destination may pass a nil dst, in which case a new image is created instead.
+// New images use the color model of the output when it is supported and model otherwise.
+func ResolveOutput(output Output, defaultMode DefaultOutputMode, dst image.Image, r image.Rectangle, model color.Model) (image.Image, image.Point, error) {
+	var outputMode OutputMode
+	if output != nil {
+		outputMode = output.OutputMode()
+		if IsColorModelSupported(output.ColorModel()) {
+			model = output.ColorModel()
+		}
+	} else {
+		outputMode = defaultMode.ToOutputMode()
+	}
+
+	if outputMode == OutputToDst && dst == nil {
+		outputMode = OutputToNewImage
+	}
+
+	switch outputMode {
+	case OutputToDst:
+		return dst, r.Min, nil
+	case OutputToNewImage:
+		img, pt := NewImage(model, r)
+		if img == nil {
+			return nil, image.Point{}, fmt.Errorf("unsupported color model %v", model)
+		}
+		return img, pt, nil
+	case OutputToProvidedImage:
+		img, pt := output.ProvidedImage()
+		if img == nil {
+			return nil, image.Point{}, errors.New("provided output image is nil")
+		}
+		return img, pt, nil
+	default:
+		return nil, image.Point{}, fmt.Errorf("unsupported output mode %v", outputMode)
+	}
+}
+
+// NewImage creates a new image with the given color model and bounds.
+// It returns a nil image for unsupported color models.
+func NewImage(model color.Model, bounds image.Rectangle) (image.Image, image.Point) {
+	switch model {
+	case color.NRGBAModel:
+		return image.NewNRGBA(bounds), bounds.Min
+	case color.RGBAModel:
+		return image.NewRGBA(bounds), bounds.Min
+	default:
+		return nil, image.Point{}
+	}
+}
+
 func zeroValue[T any]() T {
 	var zero T
 	return zero
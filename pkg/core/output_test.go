@@ -115,3 +115,53 @@ func TestOutputImageMethods(t *testing.T) {
 		t.Errorf("ColorModel() = %v, want %v", out.ColorModel(), m)
 	}
 }
+
+func TestResolveOutput(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	provided := image.NewNRGBA(image.Rect(0, 0, 5, 5))
+	r := image.Rect(2, 3, 6, 7)
+
+	tests := []struct {
+		name      string
+		output    Output
+		dst       image.Image
+		wantImage image.Image
+		wantPt    image.Point
+		wantModel color.Model
+	}{
+		{"Nil output uses default", nil, dst, dst, r.Min, nil},
+		{"ToDst", ToDst(), dst, dst, r.Min, nil},
+		{"ToDst without dst", ToDst(), nil, nil, r.Min, color.NRGBAModel},
+		{"ToNewImage", ToNewImage(), dst, nil, r.Min, color.NRGBAModel},
+		{"ToNewRGBAImage", ToNewRGBAImage(), dst, nil, r.Min, color.RGBAModel},
+		{"ToImage", ToImage(provided, image.Pt(1, 1)), dst, provided, image.Pt(1, 1), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, pt, err := ResolveOutput(tt.output, DefaultOutputToDst, tt.dst, r, color.NRGBAModel)
+			if err != nil {
+				t.Fatalf("ResolveOutput failed: %v", err)
+			}
+			if pt != tt.wantPt {
+				t.Errorf("point = %v, want %v", pt, tt.wantPt)
+			}
+			if tt.wantImage != nil && img != tt.wantImage {
+				t.Errorf("image = %T %v, want the expected image", img, img.Bounds())
+			}
+			if tt.wantModel != nil {
+				if img.ColorModel() != tt.wantModel {
+					t.Errorf("new image model = %v, want %v", img.ColorModel(), tt.wantModel)
+				}
+				if !img.Bounds().Eq(r) {
+					t.Errorf("new image bounds = %v, want %v", img.Bounds(), r)
+				}
+			}
+		})
+	}
+
+	t.Run("Unsupported model", func(t *testing.T) {
+		if _, _, err := ResolveOutput(ToNewImage(), DefaultOutputToDst, nil, r, color.GrayModel); err == nil {
+			t.Error("ResolveOutput should fail for unsupported color models")
+		}
+	})
+}
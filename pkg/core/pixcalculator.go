@@ -32,17 +32,21 @@ func (p *pixCalculator[T]) Result() T {
 	return p.out
 }
 
+// NewPixCalculatorNRGBA creates a PixCalculator for the region r of dst, clipped to dst, src and
+// out. The point srcPt of src and the point outPt of out are aligned with r.Min. The images may
+// have any bounds, as with draw.Draw.
 func NewPixCalculatorNRGBA(dst *image.NRGBA, r image.Rectangle, src *image.NRGBA, srcPt image.Point, out *image.NRGBA, outPt image.Point) PixCalculator[*image.NRGBA] {
 	bounds := IntersectNRGBA(dst, r, src, srcPt, out, outPt)
+	shift := bounds.Min.Sub(r.Min)
 	return &pixCalculator[*image.NRGBA]{
 		out:           out,
 		dstPix:        dst.Pix,
 		srcPix:        src.Pix,
 		outPix:        out.Pix,
 		rect:          bounds,
-		dstStart:      r.Min.Y*dst.Stride + (r.Min.X)*4,
-		srcStart:      srcPt.Y*src.Stride + srcPt.X*4,
-		outStart:      outPt.Y*out.Stride + outPt.X*4,
+		dstStart:      pixStart(dst.Rect, dst.Stride, bounds.Min),
+		srcStart:      pixStart(src.Rect, src.Stride, srcPt.Add(shift)),
+		outStart:      pixStart(out.Rect, out.Stride, outPt.Add(shift)),
 		dstStride:     dst.Stride,
 		srcStride:     src.Stride,
 		outStride:     out.Stride,
@@ -50,17 +54,19 @@ func NewPixCalculatorNRGBA(dst *image.NRGBA, r image.Rectangle, src *image.NRGBA
 	}
 }
 
+// NewPixCalculatorRGBA creates a PixCalculator like NewPixCalculatorNRGBA for RGBA images.
 func NewPixCalculatorRGBA(dst *image.RGBA, r image.Rectangle, src *image.RGBA, srcPt image.Point, out *image.RGBA, outPt image.Point) PixCalculator[*image.RGBA] {
 	bounds := IntersectRGBA(dst, r, src, srcPt, out, outPt)
+	shift := bounds.Min.Sub(r.Min)
 	return &pixCalculator[*image.RGBA]{
 		out:           out,
 		dstPix:        dst.Pix,
 		srcPix:        src.Pix,
 		outPix:        out.Pix,
 		rect:          bounds,
-		dstStart:      r.Min.Y*dst.Stride + (r.Min.X)*4,
-		srcStart:      srcPt.Y*src.Stride + srcPt.X*4,
-		outStart:      outPt.Y*out.Stride + outPt.X*4,
+		dstStart:      pixStart(dst.Rect, dst.Stride, bounds.Min),
+		srcStart:      pixStart(src.Rect, src.Stride, srcPt.Add(shift)),
+		outStart:      pixStart(out.Rect, out.Stride, outPt.Add(shift)),
 		dstStride:     dst.Stride,
 		srcStride:     src.Stride,
 		outStride:     out.Stride,
@@ -82,3 +88,10 @@ func (p *pixCalculator[T]) Calculate(row int) ([]uint8, []uint8, []uint8) {
 func (p *pixCalculator[T]) Rect() image.Rectangle {
 	return p.rect
 }
+
+// pixStart returns the offset of the point p in the Pix slice of an image with the given bounds
+// and stride, for 4 bytes per pixel. A point outside of the bounds has no offset; the calculator
+// of an empty region never reads it.
+func pixStart(bounds image.Rectangle, stride int, p image.Point) int {
+	return (p.Y-bounds.Min.Y)*stride + (p.X-bounds.Min.X)*4
+}
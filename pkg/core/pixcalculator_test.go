@@ -131,3 +131,41 @@ func TestPixCalculatorRGBA_PixelRange(t *testing.T) {
 		}
 	}
 }
+
+func TestPixCalculatorNRGBA_Bounds(t *testing.T) {
+	// Every pixel holds its own coordinates, and a tag per image in the blue channel.
+	coords := func(r image.Rectangle, tag uint8) *image.NRGBA {
+		img := image.NewNRGBA(r)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: tag, A: 255})
+			}
+		}
+		return img
+	}
+	// None of the images start at the origin, and each of them clips r.
+	dst := coords(image.Rect(10, 20, 30, 40), 1)
+	src := coords(image.Rect(-5, 3, 12, 14), 2)
+	out := coords(image.Rect(50, 60, 62, 75), 3)
+	r := image.Rect(5, 18, 25, 38)
+	srcPt, outPt := image.Pt(-8, 0), image.Pt(48, 58)
+
+	calc := NewPixCalculatorNRGBA(dst, r, src, srcPt, out, outPt)
+	want := image.Rect(10, 21, 19, 32)
+	if calc.Rect() != want {
+		t.Fatalf("Rect() = %v, want %v", calc.Rect(), want)
+	}
+	for y := range want.Dy() {
+		rows := [3][]uint8{}
+		rows[0], rows[1], rows[2] = calc.Calculate(y)
+		for x := range want.Dx() {
+			p := want.Min.Add(image.Pt(x, y))
+			for i, pt := range []image.Point{p, p.Sub(r.Min).Add(srcPt), p.Sub(r.Min).Add(outPt)} {
+				px := rows[i][x*4 : x*4+4]
+				if px[0] != uint8(pt.X) || px[1] != uint8(pt.Y) || px[2] != uint8(i+1) {
+					t.Fatalf("image %d at %v = %v, want the pixel at %v", i+1, p, px, pt)
+				}
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package core
 
 import (
+	"encoding/binary"
 	"image"
 	"runtime"
 	"sync"
@@ -13,6 +14,8 @@ import (
 var _ PixelIterator = (*SerialPixelIterator)(nil)
 var _ PixelIterator = (*ParallelPixelIterator)(nil)
 
+// PixelIterator calls fn with the pixels of every row of pixCalc.Rect(). The rows passed to
+// Calculate are counted from the top of the rectangle, whatever its position.
 type PixelIterator interface {
 	Iterate(pixCalc PixRowCalculator, fn func(dst, src, out []uint8))
 }
@@ -84,7 +87,7 @@ func (ppi ParallelPixelIterator) Iterate(pixCalc PixRowCalculator, fn func(dst,
 				if int(row) >= rect.Dy() {
 					break
 				}
-				fn(pixCalc.Calculate(rect.Min.Y + int(row)))
+				fn(pixCalc.Calculate(int(row)))
 			}
 		}()
 	}
@@ -93,3 +96,44 @@ func (ppi ParallelPixelIterator) Iterate(pixCalc PixRowCalculator, fn func(dst,
 }
 
 // endregion ParallelPixelIterator
+
+// region IterateRows
+
+// rowIndexCalculator is a PixRowCalculator that encodes the row index into the dst slice
+// so that any PixelIterator implementation can be used to distribute row-based work
+// that does not operate directly on Pix slices.
+type rowIndexCalculator struct {
+	rect  image.Rectangle
+	index []uint8
+}
+
+func (c rowIndexCalculator) Rect() image.Rectangle {
+	return c.rect
+}
+
+func (c rowIndexCalculator) Calculate(row int) ([]uint8, []uint8, []uint8) {
+	i := row * 4
+	return c.index[i : i+4], nil, nil
+}
+
+// IterateRows calls fn once for every row in [0, rows) using the provided PixelIterator.
+// It allows operations that work on data other than Pix slices (e.g. float buffers, masks)
+// to share the concurrency settings of a PixelIterator. As with Iterate, fn may be called
+// concurrently and must only write to data owned by its row.
+func IterateRows(pixIter PixelIterator, rows int, fn func(row int)) {
+	if rows <= 0 {
+		return
+	}
+	calc := rowIndexCalculator{
+		rect:  image.Rect(0, 0, 1, rows),
+		index: make([]uint8, rows*4),
+	}
+	for row := range rows {
+		binary.LittleEndian.PutUint32(calc.index[row*4:], uint32(row))
+	}
+	pixIter.Iterate(calc, func(idx, _, _ []uint8) {
+		fn(int(binary.LittleEndian.Uint32(idx)))
+	})
+}
+
+// endregion IterateRows
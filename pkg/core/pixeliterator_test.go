@@ -33,10 +33,9 @@ func (m *mockPixRowCalculator) Rect() image.Rectangle {
 func (m *mockPixRowCalculator) Calculate(y int) ([]uint8, []uint8, []uint8) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	// y is absolute from image origin, but our processed slice is 0-indexed from rect.Min.Y
-	index := y - m.rect.Min.Y
-	if index >= 0 && index < len(m.processed) {
-		m.processed[index] = true
+	// y is counted from the top of rect, like the processed slice.
+	if y >= 0 && y < len(m.processed) {
+		m.processed[y] = true
 	}
 	return nil, nil, nil
 }
@@ -159,3 +158,30 @@ func TestIterate(t *testing.T) {
 		t.Error("Iterate helper function did not return the correct result image")
 	}
 }
+
+func TestIterateRows(t *testing.T) {
+	iterators := map[string]PixelIterator{
+		"Serial":   NewSerialPixelIterator(),
+		"Parallel": NewParallelPixelIterator(4),
+	}
+	for name, iterator := range iterators {
+		t.Run(name, func(t *testing.T) {
+			const rows = 300
+			seen := make([]int, rows)
+			IterateRows(iterator, rows, func(row int) {
+				seen[row]++
+			})
+			for row, n := range seen {
+				if n != 1 {
+					t.Fatalf("row %d processed %d times, want 1", row, n)
+				}
+			}
+		})
+	}
+
+	t.Run("Zero rows", func(_ *testing.T) {
+		IterateRows(NewSerialPixelIterator(), 0, func(int) {
+			panic("fn should not be called")
+		})
+	})
+}
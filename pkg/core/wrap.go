@@ -0,0 +1,224 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package core
+
+import (
+	"fmt"
+	"image"
+)
+
+// WrapMode selects how a source image is extended beyond its bounds.
+type WrapMode int
+
+const (
+	// WrapNone doesn't extend the source: the region is clipped to the source bounds.
+	WrapNone WrapMode = iota
+	// WrapRepeat tiles the source.
+	WrapRepeat
+	// WrapMirror tiles the source, flipping every other tile so that neighboring tiles meet at
+	// matching edges.
+	WrapMirror
+	// WrapClamp extends the edge pixels of the source.
+	WrapClamp
+)
+
+func (m WrapMode) String() string {
+	switch m {
+	case WrapNone:
+		return "none"
+	case WrapRepeat:
+		return "repeat"
+	case WrapMirror:
+		return "mirror"
+	case WrapClamp:
+		return "clamp"
+	default:
+		return fmt.Sprintf("WrapMode(%d)", int(m))
+	}
+}
+
+// SourceWrap describes how a source is extended beyond its bounds, so that a small source covers
+// a whole region in a single pass, e.g. to tile a texture or a watermark.
+//
+// Spacing and RowOffset only apply to WrapRepeat and WrapMirror. Spacing adds a transparent gap
+// after every tile, horizontally and vertically. RowOffset shifts every row of tiles to the right
+// of the row above it; half of the tile width plus spacing gives a brick pattern, and smaller
+// offsets give diagonal grids.
+type SourceWrap struct {
+	Mode      WrapMode
+	Spacing   image.Point
+	RowOffset int
+}
+
+// IsValid reports whether the wrap mode is known and the spacing is not negative.
+func (w SourceWrap) IsValid() bool {
+	return w.Mode >= WrapNone && w.Mode <= WrapClamp && w.Spacing.X >= 0 && w.Spacing.Y >= 0
+}
+
+// wrapCalculator is a PixCalculator whose source rows are synthesized from a wrapped source.
+//
+// Repeated and mirrored sources are periodic, so every source row is expanded once into a row
+// covering the region plus one period. The source of any row of the region is then a slice of
+// the expanded row starting at the phase of that row, which accounts for the row offset.
+type wrapCalculator[T image.Image] struct {
+	out                  T
+	dstPix, outPix       []uint8
+	dstStride, outStride int
+	dstStart, outStart   int
+	rect                 image.Rectangle
+	// rows holds the expanded source rows, and srcRow and phase give the expanded row and the
+	// starting pixel of every row of rect.
+	rows          [][]uint8
+	srcRow, phase []int
+}
+
+func (c *wrapCalculator[T]) Result() T {
+	return c.out
+}
+
+func (c *wrapCalculator[T]) Rect() image.Rectangle {
+	return c.rect
+}
+
+func (c *wrapCalculator[T]) Calculate(row int) ([]uint8, []uint8, []uint8) {
+	// row is relative to rect, as for pixCalculator.
+	n := c.rect.Dx() * 4
+	di := c.dstStart + row*c.dstStride
+	oi := c.outStart + row*c.outStride
+	si := c.phase[row] * 4
+	return c.dstPix[di : di+n], c.rows[c.srcRow[row]][si : si+n], c.outPix[oi : oi+n]
+}
+
+// NewWrappedPixCalculatorNRGBA creates a PixCalculator like NewPixCalculatorNRGBA whose source
+// is extended as wrap describes. The point sp of src, which may lie outside of its bounds, is
+// aligned with r.Min, and r is only clipped to dst and out. WrapNone is the same as
+// NewPixCalculatorNRGBA.
+func NewWrappedPixCalculatorNRGBA(dst *image.NRGBA, r image.Rectangle, src *image.NRGBA, sp image.Point, wrap SourceWrap, out *image.NRGBA, outPt image.Point) PixCalculator[*image.NRGBA] {
+	if wrap.Mode == WrapNone {
+		return NewPixCalculatorNRGBA(dst, r, src, sp, out, outPt)
+	}
+	return newWrapCalculator(out, dst.Pix, dst.Stride, dst.Rect, r, src.Pix, src.Stride, src.Rect, sp, wrap, out.Pix, out.Stride, out.Rect, outPt)
+}
+
+// NewWrappedPixCalculatorRGBA creates a PixCalculator like NewPixCalculatorRGBA whose source
+// is extended as wrap describes. See NewWrappedPixCalculatorNRGBA.
+func NewWrappedPixCalculatorRGBA(dst *image.RGBA, r image.Rectangle, src *image.RGBA, sp image.Point, wrap SourceWrap, out *image.RGBA, outPt image.Point) PixCalculator[*image.RGBA] {
+	if wrap.Mode == WrapNone {
+		return NewPixCalculatorRGBA(dst, r, src, sp, out, outPt)
+	}
+	return newWrapCalculator(out, dst.Pix, dst.Stride, dst.Rect, r, src.Pix, src.Stride, src.Rect, sp, wrap, out.Pix, out.Stride, out.Rect, outPt)
+}
+
+func newWrapCalculator[T image.Image](out T,
+	dstPix []uint8, dstStride int, dstRect image.Rectangle, r image.Rectangle,
+	srcPix []uint8, srcStride int, srcRect image.Rectangle, sp image.Point, wrap SourceWrap,
+	outPix []uint8, outStride int, outRect image.Rectangle, outPt image.Point,
+) *wrapCalculator[T] {
+	bounds := r.Intersect(dstRect).Intersect(outRect.Add(r.Min.Sub(outPt)))
+	if srcRect.Empty() {
+		bounds = image.Rectangle{}
+	}
+	c := &wrapCalculator[T]{
+		out:       out,
+		dstPix:    dstPix,
+		outPix:    outPix,
+		dstStride: dstStride,
+		outStride: outStride,
+		rect:      bounds,
+		srcRow:    make([]int, bounds.Dy()),
+		phase:     make([]int, bounds.Dy()),
+	}
+	if bounds.Empty() {
+		c.rect = image.Rectangle{}
+		return c
+	}
+	c.dstStart = pixStart(dstRect, dstStride, bounds.Min)
+	c.outStart = pixStart(outRect, outStride, outPt.Add(bounds.Min.Sub(r.Min)))
+
+	// The source point of bounds.Min, relative to the source bounds.
+	s0 := sp.Add(bounds.Min.Sub(r.Min)).Sub(srcRect.Min)
+	w, h := srcRect.Dx(), srcRect.Dy()
+	width := bounds.Dx()
+
+	// Clamped sources are not periodic: their rows start at s0.X and have no phase.
+	period := 0
+	if wrap.Mode != WrapClamp {
+		period = w + wrap.Spacing.X
+		if wrap.Mode == WrapMirror {
+			period *= 2
+		}
+	}
+
+	expanded := map[int]int{} // expanded row by source row; -1 is the gap between tiles
+	for row := range c.srcRow {
+		sy, tile, gap := wrapCoord(wrap, s0.Y+row, h, wrap.Spacing.Y)
+		if gap {
+			sy = -1
+		}
+		idx, ok := expanded[sy]
+		if !ok {
+			idx = len(c.rows)
+			expanded[sy] = idx
+			buf := make([]uint8, (width+period)*4)
+			if !gap {
+				expandRow(buf, wrap, srcPix[sy*srcStride:], w, period, s0.X)
+			}
+			c.rows = append(c.rows, buf)
+		}
+		c.srcRow[row] = idx
+		if period > 0 {
+			c.phase[row] = floorMod(s0.X-tile*wrap.RowOffset, period)
+		}
+	}
+	return c
+}
+
+// expandRow fills buf with the pixels of the source row src, of w pixels. Periodic rows start at
+// phase 0; clamped rows start at the source column x0.
+func expandRow(buf []uint8, wrap SourceWrap, src []uint8, w, period, x0 int) {
+	for k := 0; k < len(buf)/4; k++ {
+		v := x0 + k
+		if period > 0 {
+			v = k % period
+		}
+		x, _, gap := wrapCoord(wrap, v, w, wrap.Spacing.X)
+		if gap {
+			continue
+		}
+		copy(buf[k*4:k*4+4], src[x*4:x*4+4])
+	}
+}
+
+// wrapCoord maps the coordinate v, relative to the source bounds, to a source coordinate in
+// [0, size). It also returns the index of the tile holding v and whether v falls in the gap
+// after a tile.
+func wrapCoord(wrap SourceWrap, v, size, spacing int) (x, tile int, gap bool) {
+	if wrap.Mode == WrapClamp {
+		return Clamp(v, 0, size-1), 0, false
+	}
+	period := size + spacing
+	tile = floorDiv(v, period)
+	x = v - tile*period
+	if x >= size {
+		return 0, tile, true
+	}
+	if wrap.Mode == WrapMirror && tile%2 != 0 {
+		x = size - 1 - x
+	}
+	return x, tile, false
+}
+
+// floorDiv divides rounding toward negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// floorMod returns the remainder of floorDiv, which has the sign of b.
+func floorMod(a, b int) int {
+	return a - floorDiv(a, b)*b
+}
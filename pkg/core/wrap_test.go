@@ -0,0 +1,188 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package core
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// letterSource returns an image whose pixels are letters: 'a' onwards from left to right in the
+// red channel, and '0' onwards from top to bottom in the green channel.
+func letterSource(r image.Rectangle) *image.NRGBA {
+	src := image.NewNRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{R: uint8('a' + x - r.Min.X), G: uint8('0' + y - r.Min.Y), A: 255})
+		}
+	}
+	return src
+}
+
+// letterRows returns the source rows of calc as strings of letters, with '_' for transparent
+// pixels and the row letter of the first pixel as a prefix.
+func letterRows(calc PixRowCalculator) []string {
+	var rows []string
+	for y := range calc.Rect().Dy() {
+		_, src, _ := calc.Calculate(y)
+		var b strings.Builder
+		b.WriteByte('0')
+		for i := 0; i < len(src); i += 4 {
+			if src[i+3] == 0 {
+				b.WriteByte('_')
+				continue
+			}
+			if i == 0 {
+				b.Reset()
+				b.WriteByte(src[i+1])
+			}
+			b.WriteByte(src[i])
+		}
+		rows = append(rows, b.String())
+	}
+	return rows
+}
+
+func TestWrappedPixCalculator(t *testing.T) {
+	// A 3x2 source away from the origin.
+	srcRect := image.Rect(10, 20, 13, 22)
+
+	tests := []struct {
+		name string
+		wrap SourceWrap
+		r    image.Rectangle
+		sp   image.Point
+		want []string
+	}{
+		{"Repeat", SourceWrap{Mode: WrapRepeat}, image.Rect(0, 0, 7, 3), image.Pt(11, 20),
+			[]string{"0bcabcab", "1bcabcab", "0bcabcab"}},
+		{"Repeat before the source", SourceWrap{Mode: WrapRepeat}, image.Rect(0, 0, 4, 2), image.Pt(8, 19),
+			[]string{"1bcab", "0bcab"}},
+		{"Mirror", SourceWrap{Mode: WrapMirror}, image.Rect(0, 0, 8, 5), image.Pt(10, 20),
+			[]string{"0abccbaab", "1abccbaab", "1abccbaab", "0abccbaab", "0abccbaab"}},
+		{"Mirror before the source", SourceWrap{Mode: WrapMirror}, image.Rect(0, 0, 8, 2), image.Pt(8, 18),
+			[]string{"1baabccba", "0baabccba"}},
+		{"Clamp", SourceWrap{Mode: WrapClamp}, image.Rect(0, 0, 7, 4), image.Pt(8, 19),
+			[]string{"0aaabccc", "0aaabccc", "1aaabccc", "1aaabccc"}},
+		{"Spacing", SourceWrap{Mode: WrapRepeat, Spacing: image.Pt(1, 2)}, image.Rect(0, 0, 8, 5), image.Pt(10, 20),
+			[]string{"0abc_abc_", "1abc_abc_", "0________", "0________", "0abc_abc_"}},
+		{"Row offset", SourceWrap{Mode: WrapRepeat, RowOffset: 2}, image.Rect(0, 0, 6, 5), image.Pt(10, 20),
+			[]string{"0abcabc", "1abcabc", "0bcabca", "1bcabca", "0cabcab"}},
+		{"Brick", SourceWrap{Mode: WrapRepeat, Spacing: image.Pt(1, 0), RowOffset: -2}, image.Rect(0, 0, 8, 3), image.Pt(10, 20),
+			[]string{"0abc_abc_", "1abc_abc_", "0c_abc_ab"}},
+		{"Mirrored rows of tiles", SourceWrap{Mode: WrapMirror, RowOffset: 1}, image.Rect(0, 0, 6, 3), image.Pt(10, 20),
+			[]string{"0abccba", "1abccba", "1aabccb"}},
+		{"Region away from the origin", SourceWrap{Mode: WrapRepeat}, image.Rect(5, 7, 9, 9), image.Pt(12, 21),
+			[]string{"1cabc", "0cabc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := letterSource(srcRect)
+			dst := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+			out := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+			calc := NewWrappedPixCalculatorNRGBA(dst, tt.r, src, tt.sp, tt.wrap, out, tt.r.Min)
+			if calc.Rect() != tt.r {
+				t.Errorf("Rect() = %v, want %v", calc.Rect(), tt.r)
+			}
+			got := letterRows(calc)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrappedPixCalculator_Mapping(t *testing.T) {
+	// dst and out are away from the origin and clip r.
+	dst := image.NewRGBA(image.Rect(3, 4, 13, 14))
+	out := image.NewRGBA(image.Rect(-5, -5, 5, 5))
+	src := image.NewRGBA(image.Rect(1, 1, 3, 3))
+	for y := range 10 {
+		for x := range 10 {
+			dst.SetRGBA(dst.Rect.Min.X+x, dst.Rect.Min.Y+y, color.RGBA{R: uint8(x), G: uint8(y), A: 255})
+			out.SetRGBA(out.Rect.Min.X+x, out.Rect.Min.Y+y, color.RGBA{R: uint8(x), G: uint8(y), A: 255})
+		}
+	}
+	src.SetRGBA(2, 1, color.RGBA{B: 9, A: 255})
+
+	r := image.Rect(0, 0, 10, 10)
+	outPt := image.Pt(-7, -3)
+	calc := NewWrappedPixCalculatorRGBA(dst, r, src, image.Pt(2, 2), SourceWrap{Mode: WrapRepeat}, out, outPt)
+	// dst clips the top left and out the bottom right.
+	want := image.Rect(3, 4, 10, 8)
+	if calc.Rect() != want {
+		t.Fatalf("Rect() = %v, want %v", calc.Rect(), want)
+	}
+	if calc.Result() != out {
+		t.Error("expected the result to be out")
+	}
+	for y := range want.Dy() {
+		dstRow, srcRow, outRow := calc.Calculate(y)
+		for x := range want.Dx() {
+			p := want.Min.Add(image.Pt(x, y))
+			i := x * 4
+			if dstRow[i] != uint8(p.X-3) || dstRow[i+1] != uint8(p.Y-4) {
+				t.Errorf("dst at %v = %v", p, dstRow[i:i+4])
+			}
+			o := p.Add(outPt).Sub(out.Rect.Min)
+			if outRow[i] != uint8(o.X) || outRow[i+1] != uint8(o.Y) {
+				t.Errorf("out at %v = %v, want %v", p, outRow[i:i+4], o)
+			}
+			// Only the top right pixel of the 2x2 source is blue, at even x and odd y of r.
+			blue := p.X%2 == 0 && p.Y%2 == 1
+			if (srcRow[i+2] == 9) != blue {
+				t.Errorf("src at %v = %v", p, srcRow[i:i+4])
+			}
+		}
+	}
+}
+
+func TestWrappedPixCalculator_Edges(t *testing.T) {
+	dst := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	src := letterSource(image.Rect(0, 0, 2, 2))
+
+	t.Run("WrapNone clips to the source", func(t *testing.T) {
+		calc := NewWrappedPixCalculatorNRGBA(dst, dst.Rect, src, image.Point{}, SourceWrap{}, dst, image.Point{})
+		if calc.Rect() != src.Rect {
+			t.Errorf("Rect() = %v, want %v", calc.Rect(), src.Rect)
+		}
+	})
+
+	t.Run("Empty source", func(t *testing.T) {
+		empty := image.NewNRGBA(image.Rectangle{})
+		calc := NewWrappedPixCalculatorNRGBA(dst, dst.Rect, empty, image.Point{}, SourceWrap{Mode: WrapRepeat}, dst, image.Point{})
+		if !calc.Rect().Empty() {
+			t.Errorf("Rect() = %v, want an empty rectangle", calc.Rect())
+		}
+	})
+
+	t.Run("Serial iterator", func(t *testing.T) {
+		iterate := NewPixelIterator(1).Iterate
+		calc := NewWrappedPixCalculatorNRGBA(dst, dst.Rect, src, image.Point{}, SourceWrap{Mode: WrapMirror}, dst, image.Point{})
+		iterate(calc, func(d, s, _ []uint8) { copy(d, s) })
+		if got := dst.NRGBAAt(2, 3); got.R != 'b' || got.G != '0' {
+			t.Errorf("pixel (2, 3) = %v, want the mirrored source pixel (1, 0)", got)
+		}
+	})
+}
+
+func TestSourceWrap(t *testing.T) {
+	valid := []SourceWrap{{}, {Mode: WrapClamp}, {Mode: WrapMirror, Spacing: image.Pt(3, 0), RowOffset: -4}}
+	for _, w := range valid {
+		if !w.IsValid() {
+			t.Errorf("%+v should be valid", w)
+		}
+	}
+	invalid := []SourceWrap{{Mode: -1}, {Mode: WrapClamp + 1}, {Mode: WrapRepeat, Spacing: image.Pt(0, -1)}}
+	for _, w := range invalid {
+		if w.IsValid() {
+			t.Errorf("%+v should be invalid", w)
+		}
+	}
+	if WrapMirror.String() != "mirror" || WrapMode(9).String() != "WrapMode(9)" {
+		t.Errorf("unexpected names %q and %q", WrapMirror, WrapMode(9))
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package distance computes exact Euclidean distance transforms of masks and builds
+// soft-edged masks (expand, contract, feather, bevels and signed distance fields) from them.
+//
+// Masks are binarized at 50% alpha. All outputs are *image.Alpha images that can be passed
+// directly as the mask of magpie.Draw or used as alpha sources for the composite ops.
+package distance
+
+import (
+	"image"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// Field holds a distance in pixels for each pixel of Rect.
+type Field struct {
+	Rect image.Rectangle
+	// Pix holds the distances in row-major order, Rect.Dx() per row.
+	Pix []float32
+}
+
+// At returns the distance at (x, y). Points outside of Rect return +Inf.
+func (f *Field) At(x, y int) float32 {
+	if !image.Pt(x, y).In(f.Rect) {
+		return float32(math.Inf(1))
+	}
+	return f.Pix[(y-f.Rect.Min.Y)*f.Rect.Dx()+x-f.Rect.Min.X]
+}
+
+// inf is used for pixels without a feature. It is large enough to exceed any squared
+// distance in an image but small enough to keep the parabola intersections finite.
+const inf = 1e20
+
+// Transform returns the distance from the center of each pixel in r to the center of the
+// nearest pixel of mask that is at least 50% opaque. Pixels of the mask outside of r are
+// ignored; if r contains no opaque pixels all distances are +Inf.
+//
+// The transform is the exact separable algorithm of Felzenszwalb and Huttenlocher, which runs
+// in linear time. Rows and then columns are processed in parallel using the configuration's
+// PixelIterator.
+func Transform(cfg core.Config, mask image.Image, r image.Rectangle) *Field {
+	r = r.Intersect(mask.Bounds())
+	inside := binarize(cfg.PixelIterator(), mask, r)
+	return &Field{Rect: r, Pix: distances(cfg.PixelIterator(), inside, r.Dx(), r.Dy(), true)}
+}
+
+// Signed returns the signed distance from the center of each pixel in r to the edge of mask.
+// Distances are negative inside the mask and positive outside of it. The edge lies halfway
+// between the centers of neighboring inside and outside pixels, so pixels next to the edge
+// have a distance of ±0.5.
+func Signed(cfg core.Config, mask image.Image, r image.Rectangle) *Field {
+	r = r.Intersect(mask.Bounds())
+	pixIter := cfg.PixelIterator()
+	inside := binarize(pixIter, mask, r)
+	w, h := r.Dx(), r.Dy()
+	toInside := distances(pixIter, inside, w, h, true)
+	toOutside := distances(pixIter, inside, w, h, false)
+	core.IterateRows(pixIter, h, func(y int) {
+		for i := y * w; i < (y+1)*w; i++ {
+			if inside[i] {
+				toInside[i] = 0.5 - toOutside[i]
+			} else {
+				toInside[i] -= 0.5
+			}
+		}
+	})
+	return &Field{Rect: r, Pix: toInside}
+}
+
+// binarize returns whether each pixel of r in mask is at least 50% opaque.
+func binarize(pixIter core.PixelIterator, mask image.Image, r image.Rectangle) []bool {
+	w := r.Dx()
+	inside := make([]bool, w*r.Dy())
+	core.IterateRows(pixIter, r.Dy(), func(y int) {
+		row := inside[y*w : (y+1)*w]
+		if a, ok := mask.(*image.Alpha); ok {
+			pix := a.Pix[a.PixOffset(r.Min.X, r.Min.Y+y):]
+			for x := range row {
+				row[x] = pix[x] >= 0x80
+			}
+			return
+		}
+		for x := range row {
+			_, _, _, a := mask.At(r.Min.X+x, r.Min.Y+y).RGBA()
+			row[x] = a >= 0x8000
+		}
+	})
+	return inside
+}
+
+// distances returns the Euclidean distance from each pixel to the nearest pixel whose inside
+// value equals feature.
+func distances(pixIter core.PixelIterator, inside []bool, w, h int, feature bool) []float32 {
+	sq := make([]float64, w*h)
+	core.IterateRows(pixIter, h, func(y int) {
+		f := make([]float64, w)
+		for x := range f {
+			f[x] = inf
+			if inside[y*w+x] == feature {
+				f[x] = 0
+			}
+		}
+		transform1D(f, sq[y*w:(y+1)*w], make([]int, w), make([]float64, w+1))
+	})
+
+	out := make([]float32, w*h)
+	core.IterateRows(pixIter, w, func(x int) {
+		f, d := make([]float64, h), make([]float64, h)
+		for y := range f {
+			f[y] = sq[y*w+x]
+		}
+		transform1D(f, d, make([]int, h), make([]float64, h+1))
+		for y, v := range d {
+			if v >= inf {
+				out[y*w+x] = float32(math.Inf(1))
+			} else {
+				out[y*w+x] = float32(math.Sqrt(v))
+			}
+		}
+	})
+	return out
+}
+
+// transform1D computes the squared distance transform d of the sampled function f by finding
+// the lower envelope of the parabolas rooted at each sample. v holds the envelope's parabola
+// locations and z the boundaries between them.
+func transform1D(f, d []float64, v []int, z []float64) {
+	n := len(f)
+	if n == 0 {
+		return
+	}
+	k := 0
+	v[0] = 0
+	z[0], z[1] = math.Inf(-1), math.Inf(1)
+	for q := 1; q < n; q++ {
+		s := intersect(f, v[k], q)
+		// z[0] is -Inf, so the loop always stops at k == 0.
+		for s <= z[k] {
+			k--
+			s = intersect(f, v[k], q)
+		}
+		k++
+		v[k] = q
+		z[k] = s
+		z[k+1] = math.Inf(1)
+	}
+	k = 0
+	for q := range d {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		dq := float64(q - v[k])
+		d[q] = dq*dq + f[v[k]]
+	}
+}
+
+// intersect returns the position where the parabolas rooted at p < q intersect.
+func intersect(f []float64, p, q int) float64 {
+	return ((f[q] + float64(q*q)) - (f[p] + float64(p*p))) / float64(2*q-2*p)
+}
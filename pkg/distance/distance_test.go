@@ -0,0 +1,174 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package distance
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+// square returns a 30x30 mask with an opaque 10x10 square at (10, 10).
+func square() *image.Alpha {
+	mask := image.NewAlpha(image.Rect(0, 0, 30, 30))
+	for y := 10; y < 20; y++ {
+		for x := 10; x < 20; x++ {
+			mask.SetAlpha(x, y, color.Alpha{A: 255})
+		}
+	}
+	return mask
+}
+
+func TestTransformMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	mask := image.NewAlpha(image.Rect(2, 3, 42, 31))
+	var points []image.Point
+	for range 12 {
+		p := image.Pt(2+rng.IntN(40), 3+rng.IntN(28))
+		mask.SetAlpha(p.X, p.Y, color.Alpha{A: 200})
+		points = append(points, p)
+	}
+
+	field := Transform(internal.DefaultConfig, mask, mask.Rect)
+	for y := mask.Rect.Min.Y; y < mask.Rect.Max.Y; y++ {
+		for x := mask.Rect.Min.X; x < mask.Rect.Max.X; x++ {
+			want := math.Inf(1)
+			for _, p := range points {
+				want = min(want, math.Hypot(float64(x-p.X), float64(y-p.Y)))
+			}
+			if got := float64(field.At(x, y)); math.Abs(got-want) > 1e-4 {
+				t.Fatalf("distance at (%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestTransformEmpty(t *testing.T) {
+	field := Transform(internal.DefaultConfig, image.NewAlpha(image.Rect(0, 0, 5, 5)), image.Rect(0, 0, 5, 5))
+	if d := field.At(2, 2); !math.IsInf(float64(d), 1) {
+		t.Errorf("distance without features = %v, want +Inf", d)
+	}
+}
+
+func TestSigned(t *testing.T) {
+	field := Signed(internal.DefaultConfig, square(), image.Rect(0, 0, 30, 30))
+	tests := []struct {
+		p    image.Point
+		want float32
+	}{
+		{image.Pt(9, 15), 0.5},
+		{image.Pt(10, 15), -0.5},
+		{image.Pt(14, 15), -4.5},
+		{image.Pt(5, 15), 4.5},
+		{image.Pt(20, 20), float32(math.Sqrt2) - 0.5},
+	}
+	for _, tt := range tests {
+		if got := field.At(tt.p.X, tt.p.Y); math.Abs(float64(got-tt.want)) > 1e-5 {
+			t.Errorf("signed distance at %v = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestExpandContract(t *testing.T) {
+	cfg := internal.DefaultConfig
+	r := image.Rect(0, 0, 30, 30)
+
+	grown := Expand(cfg, square(), r, 2)
+	if a := grown.AlphaAt(8, 15).A; a != 255 {
+		t.Errorf("expanded alpha inside new edge = %d, want 255", a)
+	}
+	if a := grown.AlphaAt(7, 15).A; a != 0 {
+		t.Errorf("expanded alpha outside new edge = %d, want 0", a)
+	}
+
+	shrunk := Contract(cfg, square(), r, 3)
+	if a := shrunk.AlphaAt(13, 15).A; a != 255 {
+		t.Errorf("contracted alpha inside new edge = %d, want 255", a)
+	}
+	if a := shrunk.AlphaAt(12, 15).A; a != 0 {
+		t.Errorf("contracted alpha outside new edge = %d, want 0", a)
+	}
+
+	same := Expand(cfg, square(), r, 0)
+	for i, v := range square().Pix {
+		if same.Pix[i] != v {
+			t.Fatalf("Expand by 0 changed pixel %d: %d, want %d", i, same.Pix[i], v)
+		}
+	}
+}
+
+func TestFeather(t *testing.T) {
+	for f := range _maxFalloff {
+		out := Feather(internal.DefaultConfig, square(), image.Rect(0, 0, 30, 30), 8, f)
+		if a := out.AlphaAt(15, 15).A; a != 255 {
+			t.Errorf("falloff %d: center alpha = %d, want 255", f, a)
+		}
+		if a := out.AlphaAt(2, 15).A; a != 0 {
+			t.Errorf("falloff %d: far alpha = %d, want 0", f, a)
+		}
+		prev := uint8(0)
+		for x := 4; x <= 15; x++ {
+			a := out.AlphaAt(x, 15).A
+			if a < prev {
+				t.Errorf("falloff %d: alpha decreases toward the center at x=%d", f, x)
+			}
+			prev = a
+		}
+	}
+}
+
+func TestFalloffEnds(t *testing.T) {
+	for f := range _maxFalloff {
+		if v := f.Apply(0); math.Abs(v) > 1e-9 {
+			t.Errorf("falloff %d: Apply(0) = %v, want 0", f, v)
+		}
+		if v := f.Apply(1); math.Abs(v-1) > 1e-9 {
+			t.Errorf("falloff %d: Apply(1) = %v, want 1", f, v)
+		}
+	}
+}
+
+func TestBevels(t *testing.T) {
+	cfg := internal.DefaultConfig
+	r := image.Rect(0, 0, 30, 30)
+
+	inner := InnerBevel(cfg, square(), r, 4, Linear)
+	if a := inner.AlphaAt(5, 15).A; a != 0 {
+		t.Errorf("inner bevel outside = %d, want 0", a)
+	}
+	if a := inner.AlphaAt(15, 15).A; a != 255 {
+		t.Errorf("inner bevel center = %d, want 255", a)
+	}
+	if a := inner.AlphaAt(11, 15).A; a != 96 {
+		t.Errorf("inner bevel ramp = %d, want 96", a)
+	}
+
+	outer := OuterBevel(cfg, square(), r, 4, Linear)
+	if a := outer.AlphaAt(15, 15).A; a != 255 {
+		t.Errorf("outer bevel inside = %d, want 255", a)
+	}
+	if a := outer.AlphaAt(2, 15).A; a != 0 {
+		t.Errorf("outer bevel far = %d, want 0", a)
+	}
+	if a := outer.AlphaAt(8, 15).A; a != 159 {
+		t.Errorf("outer bevel ramp = %d, want 159", a)
+	}
+}
+
+func TestSDF(t *testing.T) {
+	out := SDF(internal.DefaultConfig, square(), image.Rect(0, 0, 30, 30), 4)
+	if a, b := out.AlphaAt(9, 15).A, out.AlphaAt(10, 15).A; a >= 128 || b <= 128 {
+		t.Errorf("edge alphas = %d, %d, want either side of 128", a, b)
+	}
+	if a := out.AlphaAt(15, 15).A; a != 255 {
+		t.Errorf("inside alpha = %d, want 255", a)
+	}
+	if a := out.AlphaAt(0, 0).A; a != 0 {
+		t.Errorf("far alpha = %d, want 0", a)
+	}
+}
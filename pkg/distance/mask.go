@@ -0,0 +1,133 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package distance
+
+import (
+	"image"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// Falloff defines the curve used to ramp alpha across a soft edge.
+type Falloff int
+
+const (
+	// Linear ramps alpha at a constant rate.
+	Linear Falloff = iota
+	// Smooth uses smoothstep, easing in and out at both ends of the ramp.
+	Smooth
+	// EaseIn starts slowly at the transparent end of the ramp (quadratic).
+	EaseIn
+	// EaseOut starts quickly at the transparent end of the ramp (inverse quadratic).
+	EaseOut
+	// Gaussian follows the integral of a Gaussian, matching the profile of a blurred hard edge.
+	Gaussian
+
+	_maxFalloff
+)
+
+// IsValid reports whether the falloff is a known curve.
+func (f Falloff) IsValid() bool {
+	return f >= 0 && f < _maxFalloff
+}
+
+// Apply maps t in [0, 1], the position from the transparent to the opaque end of the ramp,
+// to an alpha value in [0, 1].
+func (f Falloff) Apply(t float64) float64 {
+	t = core.Clamp(t, 0, 1)
+	switch f {
+	case Smooth:
+		return t * t * (3 - 2*t)
+	case EaseIn:
+		return t * t
+	case EaseOut:
+		return 1 - (1-t)*(1-t)
+	case Gaussian:
+		// The ramp covers ±2 standard deviations; rescale so the ends reach exactly 0 and 1.
+		e := math.Erf(math.Sqrt2)
+		return (math.Erf((2*t-1)*math.Sqrt2)/e + 1) / 2
+	case Linear, _maxFalloff:
+	}
+	return t
+}
+
+// Expand returns the mask grown by n pixels with an antialiased edge. Negative values of n
+// contract the mask. The result has bounds r ∩ mask.Bounds().
+func Expand(cfg core.Config, mask image.Image, r image.Rectangle, n float64) *image.Alpha {
+	return render(cfg, mask, r, func(d float64) float64 {
+		return core.Clamp(0.5-(d-n), 0, 1)
+	})
+}
+
+// Contract returns the mask shrunk by n pixels with an antialiased edge.
+func Contract(cfg core.Config, mask image.Image, r image.Rectangle, n float64) *image.Alpha {
+	return Expand(cfg, mask, r, -n)
+}
+
+// Feather returns the mask with a soft edge of the given width centered on the original edge.
+// Alpha ramps from 0 at width/2 outside the edge to 1 at width/2 inside it following falloff.
+func Feather(cfg core.Config, mask image.Image, r image.Rectangle, width float64, falloff Falloff) *image.Alpha {
+	if width <= 0 {
+		return Expand(cfg, mask, r, 0)
+	}
+	return render(cfg, mask, r, func(d float64) float64 {
+		return falloff.Apply(0.5 - d/width)
+	})
+}
+
+// InnerBevel returns a ramp inside the mask that rises from 0 at the edge to 1 at width pixels
+// from it. Pixels outside the mask are transparent. The result is suitable as a height map or
+// as the alpha of a bevel highlight.
+func InnerBevel(cfg core.Config, mask image.Image, r image.Rectangle, width float64, falloff Falloff) *image.Alpha {
+	return render(cfg, mask, r, func(d float64) float64 {
+		if d >= 0 {
+			return 0
+		}
+		if width <= 0 {
+			return 1
+		}
+		return falloff.Apply(-d / width)
+	})
+}
+
+// OuterBevel returns a ramp outside the mask that falls from 1 at the edge to 0 at width pixels
+// from it. Pixels inside the mask are opaque.
+func OuterBevel(cfg core.Config, mask image.Image, r image.Rectangle, width float64, falloff Falloff) *image.Alpha {
+	return render(cfg, mask, r, func(d float64) float64 {
+		if d <= 0 {
+			return 1
+		}
+		if width <= 0 {
+			return 0
+		}
+		return falloff.Apply(1 - d/width)
+	})
+}
+
+// SDF returns the signed distance field of the mask encoded as alpha. The edge maps to 128,
+// alpha increases inside the mask and reaches 255 (or 0 outside) at spread pixels from the edge.
+// This matches the encoding commonly used for distance field text and shape rendering.
+func SDF(cfg core.Config, mask image.Image, r image.Rectangle, spread float64) *image.Alpha {
+	if spread <= 0 {
+		spread = 1
+	}
+	return render(cfg, mask, r, func(d float64) float64 {
+		return core.Clamp(0.5-d/(2*spread), 0, 1)
+	})
+}
+
+// render maps the signed distance of each pixel through fn and stores the results as alpha.
+func render(cfg core.Config, mask image.Image, r image.Rectangle, fn func(d float64) float64) *image.Alpha {
+	field := Signed(cfg, mask, r)
+	out := image.NewAlpha(field.Rect)
+	w := field.Rect.Dx()
+	core.IterateRows(cfg.PixelIterator(), field.Rect.Dy(), func(y int) {
+		pix := out.Pix[y*out.Stride : y*out.Stride+w]
+		for x, d := range field.Pix[y*w : (y+1)*w] {
+			pix[x] = uint8(fn(float64(d))*255 + 0.5)
+		}
+	})
+	return out
+}
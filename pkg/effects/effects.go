@@ -0,0 +1,299 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package effects renders layer styles (drop shadow, glows, inner shadow and stroke)
+// from a layer's alpha and draws them, together with the layer, onto a destination.
+package effects
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/image/nrgba"
+	"github.com/blazeroni/magpie/pkg/internal"
+	"github.com/blazeroni/magpie/pkg/internal/plane"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+// Shadow describes a drop shadow or an inner shadow.
+type Shadow struct {
+	Color   color.Color
+	Opacity float64 // [0, 1]
+	Mode    op.BlendMode
+	// Angle is the direction of the light in degrees, counter-clockwise from the positive x-axis.
+	// The shadow is offset away from the light.
+	Angle    float64
+	Distance float64 // offset in pixels
+	// Spread (drop shadow) or choke (inner shadow) in [0, 1] is the fraction of Size used to
+	// grow the matte before it is blurred.
+	Spread float64
+	Size   float64 // blur size in pixels
+}
+
+// GlowSource defines where an inner glow emanates from.
+type GlowSource int
+
+const (
+	// GlowEdge glows inward from the edges of the layer.
+	GlowEdge GlowSource = iota
+	// GlowCenter glows outward from the center of the layer.
+	GlowCenter
+
+	_maxGlowSource
+)
+
+// Glow describes an outer or inner glow.
+type Glow struct {
+	Color   color.Color
+	Opacity float64 // [0, 1]
+	Mode    op.BlendMode
+	// Spread (outer glow) or choke (inner glow) in [0, 1] is the fraction of Size used to grow
+	// the matte before it is blurred.
+	Spread float64
+	Size   float64 // blur size in pixels
+	// Source only applies to inner glows.
+	Source GlowSource
+}
+
+// StrokePosition defines where a stroke is placed relative to the layer edge.
+type StrokePosition int
+
+const (
+	StrokeOutside StrokePosition = iota
+	StrokeInside
+	StrokeCenter
+
+	_maxStrokePosition
+)
+
+// Stroke describes a solid outline around the layer's alpha.
+type Stroke struct {
+	Color    color.Color
+	Opacity  float64 // [0, 1]
+	Mode     op.BlendMode
+	Size     float64 // width in pixels
+	Position StrokePosition
+}
+
+// Style is a set of layer effects. Nil effects are skipped.
+// Effects are drawn in the same order as Photoshop: drop shadow and outer glow below the
+// layer; inner shadow, inner glow and stroke above it.
+type Style struct {
+	DropShadow  *Shadow
+	OuterGlow   *Glow
+	InnerShadow *Shadow
+	InnerGlow   *Glow
+	Stroke      *Stroke
+}
+
+// DefaultDropShadow returns a drop shadow with Photoshop's default settings.
+func DefaultDropShadow() *Shadow {
+	return &Shadow{Color: color.Black, Opacity: 0.75, Mode: op.Multiply, Angle: 120, Distance: 5, Size: 5}
+}
+
+// DefaultInnerShadow returns an inner shadow with Photoshop's default settings.
+func DefaultInnerShadow() *Shadow {
+	return &Shadow{Color: color.Black, Opacity: 0.75, Mode: op.Multiply, Angle: 120, Distance: 5, Size: 5}
+}
+
+// DefaultOuterGlow returns an outer glow with Photoshop's default settings.
+func DefaultOuterGlow() *Glow {
+	return &Glow{Color: color.NRGBA{R: 255, G: 255, B: 190, A: 255}, Opacity: 0.75, Mode: op.Screen, Size: 5}
+}
+
+// DefaultInnerGlow returns an inner glow with Photoshop's default settings.
+func DefaultInnerGlow() *Glow {
+	return &Glow{Color: color.NRGBA{R: 255, G: 255, B: 190, A: 255}, Opacity: 0.75, Mode: op.Screen, Size: 5, Source: GlowEdge}
+}
+
+// DefaultStroke returns a stroke with Photoshop's default settings.
+func DefaultStroke() *Stroke {
+	return &Stroke{Color: color.NRGBA{R: 255, A: 255}, Opacity: 1, Mode: op.Normal, Size: 3, Position: StrokeOutside}
+}
+
+// IsValid reports whether all the effects of the style can be rendered.
+func (s Style) IsValid() bool {
+	valid := func(c color.Color, opacity float64, mode op.BlendMode) bool {
+		return c != nil && opacity >= 0 && opacity <= 1 &&
+			op.BlendOp{Mode: mode, Compositing: op.CompositeAll}.IsValid()
+	}
+	shadow := func(sh *Shadow) bool {
+		return sh == nil || valid(sh.Color, sh.Opacity, sh.Mode) &&
+			sh.Distance >= 0 && sh.Size >= 0 && sh.Spread >= 0 && sh.Spread <= 1
+	}
+	glow := func(g *Glow) bool {
+		return g == nil || valid(g.Color, g.Opacity, g.Mode) &&
+			g.Size >= 0 && g.Spread >= 0 && g.Spread <= 1 &&
+			g.Source >= 0 && g.Source < _maxGlowSource
+	}
+	stroke := s.Stroke == nil || valid(s.Stroke.Color, s.Stroke.Opacity, s.Stroke.Mode) &&
+		s.Stroke.Size >= 0 && s.Stroke.Position >= 0 && s.Stroke.Position < _maxStrokePosition
+	return shadow(s.DropShadow) && shadow(s.InnerShadow) && glow(s.OuterGlow) && glow(s.InnerGlow) && stroke
+}
+
+// Render draws layer with the effects of style onto dst in a single call.
+//
+// The arguments follow magpie.Draw: r is the region of dst and sp is the point in layer aligned
+// with r.Min. Effects that extend past the layer (shadows, glows, outside strokes) are only
+// rendered within r, so r should include enough margin around the layer.
+// Each effect is blended with the existing blend kernels using CompositeAll compositing.
+func Render(cfg core.Config, dst image.Image, r image.Rectangle, layer image.Image, sp image.Point, style Style, output core.Output) (image.Image, error) {
+	if !style.IsValid() {
+		return nil, errors.New("invalid layer style")
+	}
+	orig := r.Min
+	r = r.Intersect(dst.Bounds())
+	sp = sp.Add(r.Min.Sub(orig))
+
+	model := cfg.DefaultColorModel()
+	if core.IsColorModelSupported(dst.ColorModel()) {
+		model = dst.ColorModel()
+	}
+	out, outPt, err := core.ResolveOutput(output, cfg.DefaultOutputMode(), dst, r, model)
+	if err != nil {
+		return nil, err
+	}
+	if r.Empty() {
+		return out, nil
+	}
+
+	pixIter := cfg.PixelIterator()
+	w, h := r.Dx(), r.Dy()
+	canvas := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(canvas, canvas.Rect, dst, r.Min, draw.Src)
+	content := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(content, content.Rect, layer, sp, draw.Src)
+
+	// covered marks the pixels drawn by the layer or an effect; the others keep their value.
+	alpha := plane.New(w, h)
+	covered := make([]bool, w*h)
+	for i := range alpha.Pix {
+		alpha.Pix[i] = float32(content.Pix[i*4+3]) / 255
+		covered[i] = content.Pix[i*4+3] != 0
+	}
+
+	e := renderer{pixIter: pixIter, canvas: canvas, alpha: alpha, covered: covered}
+	if s := style.DropShadow; s != nil {
+		m := e.spreadBlur(e.offset(alpha, s.Angle, s.Distance), s.Spread, s.Size)
+		e.blend(m, s.Color, s.Opacity, s.Mode)
+	}
+	if g := style.OuterGlow; g != nil {
+		e.blend(e.spreadBlur(alpha, g.Spread, g.Size), g.Color, g.Opacity, g.Mode)
+	}
+
+	nrgba.CompositeSourceOver(pixIter, core.NewPixCalculatorNRGBA(canvas, canvas.Rect, content, content.Rect.Min, canvas, canvas.Rect.Min))
+
+	if s := style.InnerShadow; s != nil {
+		m := e.spreadBlur(invert(e.offset(alpha, s.Angle, s.Distance)), s.Spread, s.Size)
+		e.blend(multiply(m, alpha), s.Color, s.Opacity, s.Mode)
+	}
+	if g := style.InnerGlow; g != nil {
+		var m *plane.Plane
+		if g.Source == GlowCenter {
+			m = invert(e.spreadBlur(invert(alpha), g.Spread, g.Size))
+		} else {
+			m = e.spreadBlur(invert(alpha), g.Spread, g.Size)
+		}
+		e.blend(multiply(m, alpha), g.Color, g.Opacity, g.Mode)
+	}
+	if s := style.Stroke; s != nil {
+		e.blend(e.stroke(s.Size, s.Position), s.Color, s.Opacity, s.Mode)
+	}
+
+	internal.CopyToOutput(dst, r, out, outPt)
+	if err = internal.WriteNRGBAMasked(pixIter, canvas, covered, out, outPt); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// renderer holds the state shared by the individual effects.
+type renderer struct {
+	pixIter core.PixelIterator
+	canvas  *image.NRGBA
+	alpha   *plane.Plane
+	covered []bool
+}
+
+// offset shifts the matte away from a light at angle degrees by distance pixels.
+func (e renderer) offset(m *plane.Plane, angle, distance float64) *plane.Plane {
+	rad := angle * math.Pi / 180
+	dx := int(math.Round(-math.Cos(rad) * distance))
+	dy := int(math.Round(math.Sin(rad) * distance))
+	out := plane.New(m.W, m.H)
+	core.IterateRows(e.pixIter, m.H, func(y int) {
+		sy := y - dy
+		if sy < 0 || sy >= m.H {
+			return
+		}
+		in, row := m.Row(sy), out.Row(y)
+		for x := range row {
+			if sx := x - dx; sx >= 0 && sx < m.W {
+				row[x] = in[sx]
+			}
+		}
+	})
+	return out
+}
+
+// spreadBlur grows the matte by spread*size pixels and blurs it over the remaining size.
+func (e renderer) spreadBlur(m *plane.Plane, spread, size float64) *plane.Plane {
+	if grow := spread * size; grow >= 1 {
+		m = plane.Dilate(e.pixIter, m, grow)
+	}
+	// The blur size is treated as a radius covering about two standard deviations.
+	return plane.GaussianBlur(e.pixIter, m, (1-spread)*size/2)
+}
+
+// stroke returns the coverage of a stroke of the given width around the layer alpha.
+func (e renderer) stroke(size float64, position StrokePosition) *plane.Plane {
+	var outer, inner *plane.Plane
+	switch position {
+	case StrokeInside:
+		outer, inner = e.alpha, plane.Erode(e.pixIter, e.alpha, size)
+	case StrokeCenter:
+		outer, inner = plane.Dilate(e.pixIter, e.alpha, size/2), plane.Erode(e.pixIter, e.alpha, size/2)
+	case StrokeOutside, _maxStrokePosition:
+		outer, inner = plane.Dilate(e.pixIter, e.alpha, size), e.alpha
+	}
+	return multiply(outer, invert(inner))
+}
+
+// blend draws a solid color layer with the coverage of m onto the canvas using mode.
+func (e renderer) blend(m *plane.Plane, c color.Color, opacity float64, mode op.BlendMode) {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA) //nolint:errcheck
+	scale := float32(opacity) * float32(nc.A)
+	layer := image.NewNRGBA(e.canvas.Rect)
+	core.IterateRows(e.pixIter, m.H, func(y int) {
+		pix, covered := layer.Pix[y*layer.Stride:], e.covered[y*m.W:]
+		for x, v := range m.Row(y) {
+			pix[x*4], pix[x*4+1], pix[x*4+2] = nc.R, nc.G, nc.B
+			pix[x*4+3] = uint8(core.Clamp(v, 0, 1)*scale + 0.5)
+			if pix[x*4+3] != 0 {
+				covered[x] = true
+			}
+		}
+	})
+	o := op.BlendOp{Mode: mode, Compositing: op.CompositeAll}
+	o.ApplyNRGBA(e.pixIter, core.NewPixCalculatorNRGBA(e.canvas, e.canvas.Rect, layer, layer.Rect.Min, e.canvas, e.canvas.Rect.Min))
+}
+
+func invert(m *plane.Plane) *plane.Plane {
+	out := plane.New(m.W, m.H)
+	for i, v := range m.Pix {
+		out.Pix[i] = 1 - v
+	}
+	return out
+}
+
+func multiply(a, b *plane.Plane) *plane.Plane {
+	out := plane.New(a.W, a.H)
+	for i, v := range a.Pix {
+		out.Pix[i] = v * b.Pix[i]
+	}
+	return out
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package effects
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+var (
+	white = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	blue  = color.NRGBA{B: 255, A: 255}
+)
+
+// scene returns a white background and a layer with a blue square in the middle.
+func scene() (*image.NRGBA, *image.NRGBA) {
+	bg := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	draw.Draw(bg, bg.Rect, image.NewUniform(white), image.Point{}, draw.Src)
+	layer := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	draw.Draw(layer, image.Rect(10, 10, 30, 30), image.NewUniform(blue), image.Point{}, draw.Src)
+	return bg, layer
+}
+
+func render(t *testing.T, style Style) *image.NRGBA {
+	t.Helper()
+	bg, layer := scene()
+	out, err := Render(internal.DefaultConfig, bg, bg.Rect, layer, image.Point{}, style, core.ToNewImage())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	return out.(*image.NRGBA)
+}
+
+func TestRenderNoEffects(t *testing.T) {
+	out := render(t, Style{})
+	if c := out.NRGBAAt(20, 20); c != blue {
+		t.Errorf("layer = %v, want %v", c, blue)
+	}
+	if c := out.NRGBAAt(2, 2); c != white {
+		t.Errorf("background = %v, want %v", c, white)
+	}
+}
+
+func TestRenderRGBAUncovered(t *testing.T) {
+	// Translucent premultiplied colors that don't survive a round trip through NRGBA.
+	dst := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	for i := 0; i < len(dst.Pix); i += 4 {
+		copy(dst.Pix[i:], []uint8{7, 131, uint8(i / 4 % 139), 138})
+	}
+	orig := bytes.Clone(dst.Pix)
+	empty := image.NewNRGBA(dst.Rect)
+
+	for name, output := range map[string]core.Output{"new image": core.ToNewImage(), "dst": core.ToImage(dst, image.Point{})} {
+		out, err := Render(internal.DefaultConfig, dst, dst.Rect, empty, image.Point{}, Style{}, output)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if !bytes.Equal(out.(*image.RGBA).Pix, orig) {
+			t.Errorf("%s: an empty style on a transparent layer changed the image", name)
+		}
+	}
+
+	// Only the pixels around the layer are drawn by the glow.
+	_, layer := scene()
+	out, err := Render(internal.DefaultConfig, dst, dst.Rect, layer, image.Point{}, Style{OuterGlow: DefaultOuterGlow()}, core.ToNewImage())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got, want := out.(*image.RGBA).RGBAAt(0, 0), dst.RGBAAt(0, 0); got != want {
+		t.Errorf("pixel away from the glow = %v, want %v", got, want)
+	}
+}
+
+func TestDropShadow(t *testing.T) {
+	s := DefaultDropShadow()
+	s.Size = 0
+	out := render(t, Style{DropShadow: s})
+
+	// The light comes from 120°, so the shadow is offset down and to the right.
+	if c := out.NRGBAAt(31, 32); c.R >= 128 {
+		t.Errorf("shadow below right = %v, want dark", c)
+	}
+	if c := out.NRGBAAt(8, 8); c != white {
+		t.Errorf("above left = %v, want %v", c, white)
+	}
+	if c := out.NRGBAAt(20, 20); c != blue {
+		t.Errorf("layer = %v, want %v", c, blue)
+	}
+}
+
+func TestGlows(t *testing.T) {
+	g := DefaultOuterGlow()
+	g.Color = color.NRGBA{R: 255, A: 255}
+	g.Mode = op.Normal
+	out := render(t, Style{OuterGlow: g})
+	if c := out.NRGBAAt(8, 20); c.G == 255 {
+		t.Errorf("outer glow = %v, want tinted", c)
+	}
+	if c := out.NRGBAAt(0, 20); c != white {
+		t.Errorf("far from layer = %v, want %v", c, white)
+	}
+
+	g = DefaultInnerGlow()
+	g.Color = color.NRGBA{R: 255, A: 255}
+	g.Mode = op.Normal
+	out = render(t, Style{InnerGlow: g})
+	if c := out.NRGBAAt(10, 20); c.R == 0 {
+		t.Errorf("inner glow at edge = %v, want tinted", c)
+	}
+	if c := out.NRGBAAt(20, 20); c != blue {
+		t.Errorf("inner glow at center = %v, want %v", c, blue)
+	}
+	if c := out.NRGBAAt(8, 20); c != white {
+		t.Errorf("inner glow outside = %v, want %v", c, white)
+	}
+}
+
+func TestInnerShadow(t *testing.T) {
+	s := DefaultInnerShadow()
+	s.Color = color.NRGBA{R: 255, A: 255}
+	s.Mode = op.Normal
+	s.Size = 0
+	out := render(t, Style{InnerShadow: s})
+	// Offset down and right, so the shadow shows along the top left inside edges.
+	if c := out.NRGBAAt(11, 11); c.R == 0 {
+		t.Errorf("top left inside = %v, want shadow", c)
+	}
+	if c := out.NRGBAAt(28, 28); c != blue {
+		t.Errorf("bottom right inside = %v, want %v", c, blue)
+	}
+}
+
+func TestStroke(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	tests := []struct {
+		position      StrokePosition
+		stroked, kept image.Point
+	}{
+		{StrokeOutside, image.Pt(8, 20), image.Pt(10, 20)},
+		{StrokeInside, image.Pt(11, 20), image.Pt(8, 20)},
+		{StrokeCenter, image.Pt(9, 20), image.Pt(20, 20)},
+	}
+	for _, tt := range tests {
+		s := DefaultStroke()
+		s.Position = tt.position
+		out := render(t, Style{Stroke: s})
+		if c := out.NRGBAAt(tt.stroked.X, tt.stroked.Y); c != red {
+			t.Errorf("position %d: stroked pixel = %v, want %v", tt.position, c, red)
+		}
+		if c := out.NRGBAAt(tt.kept.X, tt.kept.Y); c == red {
+			t.Errorf("position %d: pixel %v is stroked", tt.position, tt.kept)
+		}
+	}
+}
+
+func TestRenderInvalid(t *testing.T) {
+	bg, layer := scene()
+	s := DefaultStroke()
+	s.Opacity = 2
+	if _, err := Render(internal.DefaultConfig, bg, bg.Rect, layer, image.Point{}, Style{Stroke: s}, core.ToNewImage()); err == nil {
+		t.Error("expected error for invalid opacity")
+	}
+}
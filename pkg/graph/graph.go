@@ -0,0 +1,246 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package graph evaluates lazy render graphs: directed acyclic graphs of sources, ops, filters
+// and transforms that are only computed when a region of the output is requested.
+//
+// Requesting a region propagates it backwards through the graph. Every node asks its inputs
+// only for the pixels it needs, including the margin that neighborhood filters read around
+// each pixel, so rendering a small crop of a large composition touches only a small part of
+// every image. A node that feeds several others is computed once for the union of their
+// requests. Results are cached per node and reused by later requests that they cover, alone or
+// together.
+package graph
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"sync"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// Node is a step of a render graph.
+//
+// The pixels of a node are defined within Bounds and transparent elsewhere. Render computes a
+// region of them from the pixels of the inputs: inputs[i] covers InputRegion(i, r) clipped to
+// the bounds of the i-th input. Results use the default color model of the configuration, and
+// the inputs are given in that model as well. Render must not modify its inputs, and may
+// return them or a part of them when it does not change any pixel. Graphs key their caches by
+// node, so implementations should be pointer types.
+type Node interface {
+	Bounds() image.Rectangle
+	Inputs() []Node
+	InputRegion(i int, r image.Rectangle) image.Rectangle
+	Render(cfg core.Config, r image.Rectangle, inputs []image.Image) (image.Image, error)
+}
+
+// Graph renders nodes and caches their results. Nodes are immutable, so a cached result stays
+// valid until Invalidate is called for a node whose pixels changed, e.g. a source image that
+// was drawn on. A Graph is safe for concurrent use; renders are serialized and each node is
+// computed with the PixelIterator of the configuration.
+//
+// The cache of a node holds every region it was rendered for, except regions contained in a
+// later one, so that requests alternating between regions are not recomputed.
+type Graph struct {
+	cfg   core.Config
+	mu    sync.Mutex
+	cache map[Node][]image.Image
+}
+
+// New creates a Graph that renders with cfg. The default color model of cfg must be supported.
+func New(cfg core.Config) *Graph {
+	return &Graph{
+		cfg:   cfg,
+		cache: make(map[Node][]image.Image),
+	}
+}
+
+// Render returns the pixels of n within r, clipped to the bounds of n. Only the regions of the
+// nodes that n depends on which are needed for r and not already cached are computed.
+// The result may share pixels with the cache and with source images and must not be modified.
+func (g *Graph) Render(n Node, r image.Rectangle) (image.Image, error) {
+	if !core.IsColorModelSupported(g.cfg.DefaultColorModel()) {
+		return nil, errors.New("unsupported color model")
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	order, err := sortNodes(n)
+	if err != nil {
+		return nil, err
+	}
+
+	// Propagate the requested regions from the output to the sources. order lists the inputs of
+	// every node before the node itself, so walking it backwards visits all consumers of a node
+	// before the node.
+	roi := map[Node]image.Rectangle{n: r.Intersect(n.Bounds())}
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		want := roi[node]
+		if want.Empty() || g.covers(node, want) {
+			continue
+		}
+		for j, in := range node.Inputs() {
+			need := node.InputRegion(j, want).Intersect(in.Bounds())
+			if !need.Empty() {
+				roi[in] = roi[in].Union(need)
+			}
+		}
+	}
+
+	for _, node := range order {
+		want := roi[node]
+		if want.Empty() || g.covers(node, want) {
+			continue
+		}
+		inputs := make([]image.Image, len(node.Inputs()))
+		for j, in := range node.Inputs() {
+			inputs[j] = g.cached(in, node.InputRegion(j, want).Intersect(in.Bounds()))
+		}
+		img, err := node.Render(g.cfg, want, inputs)
+		if err != nil {
+			return nil, err
+		}
+		g.store(node, img)
+	}
+	return g.cached(n, roi[n]), nil
+}
+
+// Invalidate drops the cached results of n and of every node that depends on it.
+func (g *Graph) Invalidate(n Node) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	depends := map[Node]bool{n: true}
+	var visit func(node Node) bool
+	visit = func(node Node) bool {
+		if d, ok := depends[node]; ok {
+			return d
+		}
+		depends[node] = false
+		for _, in := range node.Inputs() {
+			if visit(in) {
+				depends[node] = true
+			}
+		}
+		return depends[node]
+	}
+	for node := range g.cache {
+		if visit(node) {
+			delete(g.cache, node)
+		}
+	}
+}
+
+// Reset drops all cached results.
+func (g *Graph) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	clear(g.cache)
+}
+
+// store adds img to the cached results of n and drops the results that it contains.
+func (g *Graph) store(n Node, img image.Image) {
+	tiles := g.cache[n][:0]
+	for _, tile := range g.cache[n] {
+		if !tile.Bounds().In(img.Bounds()) {
+			tiles = append(tiles, tile)
+		}
+	}
+	g.cache[n] = append(tiles, img)
+}
+
+// covers reports whether the cached results of n together contain r.
+func (g *Graph) covers(n Node, r image.Rectangle) bool {
+	bounds := make([]image.Rectangle, len(g.cache[n]))
+	for i, tile := range g.cache[n] {
+		bounds[i] = tile.Bounds()
+	}
+	return covered(r, bounds)
+}
+
+// cached returns the part r of the cached results of n, or an empty image if r is empty.
+// Regions that span several results are copied into a new image.
+func (g *Graph) cached(n Node, r image.Rectangle) image.Image {
+	if r.Empty() {
+		img, _ := core.NewImage(g.cfg.DefaultColorModel(), image.Rectangle{})
+		return img
+	}
+	tiles := g.cache[n]
+	for _, tile := range tiles {
+		if r.In(tile.Bounds()) {
+			return subImage(tile, r)
+		}
+	}
+	img, _ := core.NewImage(g.cfg.DefaultColorModel(), r)
+	for _, tile := range tiles {
+		if part := r.Intersect(tile.Bounds()); !part.Empty() {
+			draw.Draw(img.(draw.Image), part, tile, part.Min, draw.Src)
+		}
+	}
+	return img
+}
+
+// covered reports whether the union of rs contains r.
+func covered(r image.Rectangle, rs []image.Rectangle) bool {
+	if r.Empty() {
+		return true
+	}
+	if len(rs) == 0 {
+		return false
+	}
+	s := r.Intersect(rs[0])
+	if s.Empty() {
+		return covered(r, rs[1:])
+	}
+	// The parts of r above, below, left and right of s must be covered by the other rectangles.
+	parts := []image.Rectangle{
+		{Min: r.Min, Max: image.Pt(r.Max.X, s.Min.Y)},
+		{Min: image.Pt(r.Min.X, s.Max.Y), Max: r.Max},
+		{Min: image.Pt(r.Min.X, s.Min.Y), Max: image.Pt(s.Min.X, s.Max.Y)},
+		{Min: image.Pt(s.Max.X, s.Min.Y), Max: image.Pt(r.Max.X, s.Max.Y)},
+	}
+	for _, part := range parts {
+		if !covered(part, rs[1:]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortNodes returns the nodes that n depends on, and n itself, with every node after its inputs.
+func sortNodes(n Node) ([]Node, error) {
+	const (
+		visiting = iota + 1
+		done
+	)
+	var order []Node
+	state := make(map[Node]int)
+	var visit func(node Node) error
+	visit = func(node Node) error {
+		switch state[node] {
+		case visiting:
+			return errors.New("render graph contains a cycle")
+		case done:
+			return nil
+		}
+		state[node] = visiting
+		for _, in := range node.Inputs() {
+			if in == nil {
+				return errors.New("render graph node has a nil input")
+			}
+			if err := visit(in); err != nil {
+				return err
+			}
+		}
+		state[node] = done
+		order = append(order, node)
+		return nil
+	}
+	if err := visit(n); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
@@ -0,0 +1,325 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package graph
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+	"github.com/blazeroni/magpie/pkg/morph"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+func randomNRGBA(r image.Rectangle, seed uint64) *image.NRGBA {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	img := image.NewNRGBA(r)
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rng.IntN(256))
+	}
+	return img
+}
+
+// countingNode records the regions that a node is rendered for.
+type countingNode struct {
+	Node
+	renders []image.Rectangle
+}
+
+func (n *countingNode) Render(cfg core.Config, r image.Rectangle, inputs []image.Image) (image.Image, error) {
+	n.renders = append(n.renders, r)
+	return n.Node.Render(cfg, r, inputs)
+}
+
+func config(model color.Model) core.Config {
+	return internal.NewConfig(core.NewSerialPixelIterator(), core.DefaultOutputToNewImage, model)
+}
+
+var multiply = op.BlendOp{Mode: op.Multiply, Compositing: op.CompositeAll}
+
+// composition multiplies a layer over a background and dilates the result.
+func composition(bg, layer image.Image) (out, bgNode, layerNode *countingNode) {
+	bgNode = &countingNode{Node: Source(bg)}
+	layerNode = &countingNode{Node: Source(layer)}
+	blended := Draw(bgNode, Translate(layerNode, image.Pt(50, 40)), multiply)
+	out = &countingNode{Node: Morph(blended, morph.Op{Mode: morph.Dilate, Element: morph.Square(2)})}
+	return out, bgNode, layerNode
+}
+
+func assertSamePixels(t *testing.T, got, want image.Image, r image.Rectangle) {
+	t.Helper()
+	if got.Bounds() != r {
+		t.Fatalf("bounds = %v, want %v", got.Bounds(), r)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if g, w := got.At(x, y), want.At(x, y); g != w {
+				t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, g, w)
+			}
+		}
+	}
+}
+
+func TestRenderMatchesDirect(t *testing.T) {
+	bg := randomNRGBA(image.Rect(0, 0, 200, 150), 1)
+	layer := randomNRGBA(image.Rect(0, 0, 80, 60), 2)
+
+	// The same composition computed on whole images.
+	want := image.NewNRGBA(bg.Rect)
+	copy(want.Pix, bg.Pix)
+	lr := layer.Rect.Add(image.Pt(50, 40))
+	multiply.ApplyNRGBA(core.SerialPixelIterator{}, core.NewPixCalculatorNRGBA(want, lr, layer, image.Point{}, want, lr.Min))
+	want, err := morph.Apply(config(color.NRGBAModel), want, want.Rect, morph.Op{Mode: morph.Dilate, Element: morph.Square(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []image.Rectangle{bg.Rect, image.Rect(60, 50, 100, 80), image.Rect(-10, 140, 30, 170)} {
+		out, _, _ := composition(bg, layer)
+		got, err := New(config(color.NRGBAModel)).Render(out, r)
+		if err != nil {
+			t.Fatalf("Render(%v) failed: %v", r, err)
+		}
+		assertSamePixels(t, got, want, r.Intersect(bg.Rect))
+	}
+}
+
+func TestRenderRGBA(t *testing.T) {
+	bg := randomNRGBA(image.Rect(0, 0, 120, 90), 3)
+	layer := randomNRGBA(image.Rect(0, 0, 80, 60), 4)
+	r := image.Rect(40, 30, 90, 70)
+
+	full, _, _ := composition(bg, layer)
+	want, err := New(config(color.RGBAModel)).Render(full, bg.Rect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crop, _, _ := composition(bg, layer)
+	got, err := New(config(color.RGBAModel)).Render(crop, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.(*image.RGBA); !ok {
+		t.Fatalf("result is %T, want *image.RGBA", got)
+	}
+	assertSamePixels(t, got, want, r)
+}
+
+func TestRenderCropDissolve(t *testing.T) {
+	// Dissolve picks its pattern from the pixel coordinates, so a crop must see the coordinates of
+	// the whole image.
+	bg := randomNRGBA(image.Rect(0, 0, 64, 64), 5)
+	layer := randomNRGBA(image.Rect(0, 0, 64, 64), 6)
+	dissolve := op.BlendOp{Mode: op.Dissolve, Compositing: op.CompositeAll, Seed: 7}
+	iterators := map[string]core.PixelIterator{
+		"Serial":   core.NewSerialPixelIterator(),
+		"Parallel": core.NewParallelPixelIterator(4),
+	}
+
+	for _, model := range []color.Model{color.NRGBAModel, color.RGBAModel} {
+		for name, iter := range iterators {
+			cfg := internal.NewConfig(iter, core.DefaultOutputToNewImage, model)
+			out := Draw(Source(bg), Source(layer), dissolve)
+			want, err := New(cfg).Render(out, bg.Rect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range []image.Rectangle{image.Rect(32, 32, 48, 48), image.Rect(5, 50, 60, 53)} {
+				got, err := New(cfg).Render(out, r)
+				if err != nil {
+					t.Fatalf("%s: Render(%v) failed: %v", name, r, err)
+				}
+				assertSamePixels(t, got, want, r)
+			}
+		}
+	}
+}
+
+func TestRegionPropagation(t *testing.T) {
+	bg := randomNRGBA(image.Rect(0, 0, 2000, 1500), 5)
+	layer := randomNRGBA(image.Rect(0, 0, 80, 60), 6)
+	out, bgNode, layerNode := composition(bg, layer)
+
+	r := image.Rect(100, 60, 140, 90)
+	if _, err := New(config(color.NRGBAModel)).Render(out, r); err != nil {
+		t.Fatal(err)
+	}
+	// The dilation reads twice the radius of its element around the requested region.
+	need := r.Inset(-4)
+	if len(bgNode.renders) != 1 || bgNode.renders[0] != need {
+		t.Errorf("background rendered for %v, want [%v]", bgNode.renders, need)
+	}
+	if want := need.Sub(image.Pt(50, 40)).Intersect(layer.Rect); len(layerNode.renders) != 1 || layerNode.renders[0] != want {
+		t.Errorf("layer rendered for %v, want [%v]", layerNode.renders, want)
+	}
+
+	t.Run("Outside of a source", func(t *testing.T) {
+		out, _, layerNode := composition(bg, layer)
+		if _, err := New(config(color.NRGBAModel)).Render(out, image.Rect(1000, 1000, 1100, 1100)); err != nil {
+			t.Fatal(err)
+		}
+		if len(layerNode.renders) != 0 {
+			t.Errorf("layer rendered for %v, want no renders", layerNode.renders)
+		}
+	})
+}
+
+func TestCache(t *testing.T) {
+	bg := randomNRGBA(image.Rect(0, 0, 200, 150), 7)
+	layer := randomNRGBA(image.Rect(0, 0, 80, 60), 8)
+	out, bgNode, layerNode := composition(bg, layer)
+	g := New(config(color.NRGBAModel))
+
+	r := image.Rect(60, 50, 100, 80)
+	first, err := g.Render(out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Requests within a cached region are served from the cache.
+	for _, sub := range []image.Rectangle{r, image.Rect(70, 60, 80, 70)} {
+		got, err := g.Render(out, sub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSamePixels(t, got, first, sub)
+	}
+	if len(out.renders) != 1 || len(bgNode.renders) != 1 || len(layerNode.renders) != 1 {
+		t.Fatalf("renders = %d, %d, %d; want 1 each", len(out.renders), len(bgNode.renders), len(layerNode.renders))
+	}
+
+	// Changing the background invalidates it and the nodes that depend on it, but not the layer.
+	bg.Set(70, 60, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	g.Invalidate(bgNode)
+	got, err := g.Render(out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.renders) != 2 || len(bgNode.renders) != 2 || len(layerNode.renders) != 1 {
+		t.Fatalf("renders = %d, %d, %d; want 2, 2, 1", len(out.renders), len(bgNode.renders), len(layerNode.renders))
+	}
+	if string(got.(*image.NRGBA).Pix) == string(first.(*image.NRGBA).Pix) {
+		t.Error("expected the change to the background to be rendered")
+	}
+
+	g.Reset()
+	if _, err := g.Render(out, r); err != nil {
+		t.Fatal(err)
+	}
+	if len(layerNode.renders) != 2 {
+		t.Errorf("layer renders = %d after Reset, want 2", len(layerNode.renders))
+	}
+}
+
+func TestCacheRegions(t *testing.T) {
+	bg := randomNRGBA(image.Rect(0, 0, 200, 150), 12)
+	layer := randomNRGBA(image.Rect(0, 0, 80, 60), 13)
+	whole, _, _ := composition(bg, layer)
+	full, err := New(config(color.NRGBAModel)).Render(whole, bg.Rect)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, bgNode, _ := composition(bg, layer)
+	g := New(config(color.NRGBAModel))
+	a, b := image.Rect(20, 20, 60, 60), image.Rect(60, 40, 120, 90)
+	// Alternating regions, and a region spanning both, are served from the cache.
+	for _, r := range []image.Rectangle{a, b, a, b, image.Rect(40, 45, 80, 55)} {
+		got, err := g.Render(out, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSamePixels(t, got, full, r)
+	}
+	if len(out.renders) != 2 || len(bgNode.renders) != 2 {
+		t.Fatalf("renders = %d, %d; want 2 each", len(out.renders), len(bgNode.renders))
+	}
+
+	// A region partly outside of the cached ones is rendered again, and replaces those it contains.
+	r := image.Rect(10, 10, 130, 100)
+	got, err := g.Render(out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSamePixels(t, got, full, r)
+	if len(out.renders) != 3 || len(g.cache[out]) != 1 {
+		t.Errorf("renders = %d, cached regions = %d; want 3, 1", len(out.renders), len(g.cache[out]))
+	}
+}
+
+func TestSharedNode(t *testing.T) {
+	src := &countingNode{Node: Source(randomNRGBA(image.Rect(0, 0, 50, 50), 9))}
+	shifted := Translate(src, image.Pt(10, 0))
+	out := Draw(src, shifted, multiply)
+
+	r := image.Rect(20, 20, 30, 30)
+	if _, err := New(config(color.NRGBAModel)).Render(out, r); err != nil {
+		t.Fatal(err)
+	}
+	// Both consumers are served by a single render of their union.
+	if want := image.Rect(10, 20, 30, 30); len(src.renders) != 1 || src.renders[0] != want {
+		t.Errorf("source rendered for %v, want [%v]", src.renders, want)
+	}
+}
+
+func TestCrop(t *testing.T) {
+	img := randomNRGBA(image.Rect(0, 0, 40, 40), 10)
+	src := &countingNode{Node: Source(img)}
+	crop := Crop(src, image.Rect(10, 10, 20, 20))
+	if b := crop.Bounds(); b != image.Rect(10, 10, 20, 20) {
+		t.Errorf("bounds = %v", b)
+	}
+	got, err := New(config(color.NRGBAModel)).Render(crop, image.Rect(0, 0, 15, 40))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSamePixels(t, got, img, image.Rect(10, 10, 15, 20))
+}
+
+func TestConvertSource(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 4, 4))
+	draw.Draw(gray, gray.Rect, image.NewUniform(color.Gray{Y: 100}), image.Point{}, draw.Src)
+	got, err := New(config(color.NRGBAModel)).Render(Source(gray), gray.Rect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := got.(*image.NRGBA).NRGBAAt(1, 1); c != (color.NRGBA{R: 100, G: 100, B: 100, A: 255}) {
+		t.Errorf("pixel = %v", c)
+	}
+}
+
+// cycleNode depends on itself.
+type cycleNode struct{ Node }
+
+func (n *cycleNode) Inputs() []Node { return []Node{n} }
+
+func TestRenderInvalid(t *testing.T) {
+	src := Source(randomNRGBA(image.Rect(0, 0, 4, 4), 11))
+	r := image.Rect(0, 0, 4, 4)
+	tests := []struct {
+		name string
+		node Node
+	}{
+		{"Invalid op", Draw(src, src, op.CustomBlendOp{Compositing: op.CompositeAll})},
+		{"Nil op", Draw(src, src, nil)},
+		{"Nil input", Draw(src, nil, multiply)},
+		{"Cycle", &cycleNode{Node: src}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(config(color.NRGBAModel)).Render(tt.node, r); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	t.Run("Unsupported color model", func(t *testing.T) {
+		if _, err := New(config(color.GrayModel)).Render(src, r); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/morph"
+)
+
+var (
+	_ Node = (*sourceNode)(nil)
+	_ Node = (*drawNode)(nil)
+	_ Node = (*morphNode)(nil)
+	_ Node = (*translateNode)(nil)
+	_ Node = (*cropNode)(nil)
+)
+
+// region Source
+
+type sourceNode struct {
+	img image.Image
+}
+
+// Source returns a node with the pixels of img. Images in the color model of the configuration
+// are used directly; others are converted region by region as they are requested.
+func Source(img image.Image) Node {
+	return &sourceNode{img: img}
+}
+
+func (n *sourceNode) Bounds() image.Rectangle { return n.img.Bounds() }
+
+func (n *sourceNode) Inputs() []Node { return nil }
+
+func (n *sourceNode) InputRegion(int, image.Rectangle) image.Rectangle { return image.Rectangle{} }
+
+func (n *sourceNode) Render(cfg core.Config, r image.Rectangle, _ []image.Image) (image.Image, error) {
+	if n.img.ColorModel() == cfg.DefaultColorModel() {
+		if img, ok := n.img.(subImager); ok {
+			return img.SubImage(r), nil
+		}
+	}
+	return cover(cfg.DefaultColorModel(), n.img, r), nil
+}
+
+// endregion Source
+
+// region Draw
+
+type drawNode struct {
+	dst, src Node
+	op       core.Op
+}
+
+// Draw returns a node that applies op with the pixels of dst as destination and the pixels of
+// src at the same position as source, like magpie.Draw with an aligned source. Use Translate to
+// move src. The result covers both inputs, since a compositing op may keep pixels of either.
+func Draw(dst, src Node, op core.Op) Node {
+	return &drawNode{dst: dst, src: src, op: op}
+}
+
+func (n *drawNode) Bounds() image.Rectangle { return n.dst.Bounds().Union(n.src.Bounds()) }
+
+func (n *drawNode) Inputs() []Node { return []Node{n.dst, n.src} }
+
+func (n *drawNode) InputRegion(_ int, r image.Rectangle) image.Rectangle { return r }
+
+func (n *drawNode) Render(cfg core.Config, r image.Rectangle, inputs []image.Image) (image.Image, error) {
+	if n.op == nil || !n.op.IsValid() {
+		return nil, errors.New("invalid operation")
+	}
+	model := cfg.DefaultColorModel()
+	dst, src := cover(model, inputs[0], r), cover(model, inputs[1], r)
+	out, _ := core.NewImage(model, r)
+	// The calculator covers r in image coordinates, so that ops depending on the position of the
+	// pixels, such as Dissolve, render any region the same as the whole image.
+	switch model {
+	case color.NRGBAModel:
+		n.op.ApplyNRGBA(cfg.PixelIterator(), core.NewPixCalculatorNRGBA(dst.(*image.NRGBA), r, src.(*image.NRGBA), r.Min, out.(*image.NRGBA), r.Min))
+	case color.RGBAModel:
+		n.op.ApplyRGBA(cfg.PixelIterator(), core.NewPixCalculatorRGBA(dst.(*image.RGBA), r, src.(*image.RGBA), r.Min, out.(*image.RGBA), r.Min))
+	default:
+		return nil, fmt.Errorf("unsupported color model %v", model)
+	}
+	return out, nil
+}
+
+// endregion Draw
+
+// region Morph
+
+type morphNode struct {
+	in     Node
+	op     morph.Op
+	margin int
+}
+
+// Morph returns a node that applies a morphological operation to every channel of in, as
+// morph.Apply does for the whole image. Each pixel reads the pixels within twice the extent of
+// the element around it, which is the margin requested from in.
+func Morph(in Node, op morph.Op) Node {
+	eb := op.Element.Bounds()
+	margin := max(-eb.Min.X, -eb.Min.Y, eb.Max.X-1, eb.Max.Y-1, 0)
+	return &morphNode{in: in, op: op, margin: 2 * margin}
+}
+
+func (n *morphNode) Bounds() image.Rectangle { return n.in.Bounds() }
+
+func (n *morphNode) Inputs() []Node { return []Node{n.in} }
+
+func (n *morphNode) InputRegion(_ int, r image.Rectangle) image.Rectangle {
+	return r.Inset(-n.margin)
+}
+
+func (n *morphNode) Render(cfg core.Config, r image.Rectangle, inputs []image.Image) (image.Image, error) {
+	switch in := inputs[0].(type) {
+	case *image.NRGBA:
+		return morph.Apply(cfg, in, r, n.op)
+	case *image.RGBA:
+		return morph.Apply(cfg, in, r, n.op)
+	default:
+		return nil, fmt.Errorf("unsupported color model %v", in.ColorModel())
+	}
+}
+
+// endregion Morph
+
+// region Transforms
+
+type translateNode struct {
+	in Node
+	d  image.Point
+}
+
+// Translate returns a node that moves the pixels of in by d.
+func Translate(in Node, d image.Point) Node {
+	return &translateNode{in: in, d: d}
+}
+
+func (n *translateNode) Bounds() image.Rectangle { return n.in.Bounds().Add(n.d) }
+
+func (n *translateNode) Inputs() []Node { return []Node{n.in} }
+
+func (n *translateNode) InputRegion(_ int, r image.Rectangle) image.Rectangle { return r.Sub(n.d) }
+
+func (n *translateNode) Render(_ core.Config, _ image.Rectangle, inputs []image.Image) (image.Image, error) {
+	// Moving the bounds of a view is enough; the pixels are shared with the input.
+	switch in := inputs[0].(type) {
+	case *image.NRGBA:
+		return &image.NRGBA{Pix: in.Pix, Stride: in.Stride, Rect: in.Rect.Add(n.d)}, nil
+	case *image.RGBA:
+		return &image.RGBA{Pix: in.Pix, Stride: in.Stride, Rect: in.Rect.Add(n.d)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported color model %v", in.ColorModel())
+	}
+}
+
+type cropNode struct {
+	in   Node
+	rect image.Rectangle
+}
+
+// Crop returns a node with the pixels of in within rect. Pixels outside of rect are transparent.
+func Crop(in Node, rect image.Rectangle) Node {
+	return &cropNode{in: in, rect: rect}
+}
+
+func (n *cropNode) Bounds() image.Rectangle { return n.in.Bounds().Intersect(n.rect) }
+
+func (n *cropNode) Inputs() []Node { return []Node{n.in} }
+
+func (n *cropNode) InputRegion(_ int, r image.Rectangle) image.Rectangle { return r.Intersect(n.rect) }
+
+func (n *cropNode) Render(_ core.Config, _ image.Rectangle, inputs []image.Image) (image.Image, error) {
+	return inputs[0], nil
+}
+
+// endregion Transforms
+
+// subImage returns the part r of img, which must be an NRGBA or RGBA image containing r.
+func subImage(img image.Image, r image.Rectangle) image.Image {
+	if img.Bounds() == r {
+		return img
+	}
+	return img.(subImager).SubImage(r)
+}
+
+type subImager interface {
+	SubImage(r image.Rectangle) image.Image
+}
+
+// cover returns img if it is in the given model with bounds r, and otherwise a new image with
+// bounds r holding the pixels of img, transparent where img does not cover r.
+func cover(model color.Model, img image.Image, r image.Rectangle) image.Image {
+	if img.ColorModel() == model && img.Bounds() == r {
+		return img
+	}
+	out, _ := core.NewImage(model, r)
+	draw.Draw(out.(draw.Image), r, img, r.Min, draw.Src)
+	return out
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package hdr provides a floating point, high dynamic range image type and a
+// pure Go reader and writer for Radiance RGBE (.hdr) files.
+package hdr
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+var _ image.Image = (*Image)(nil)
+
+// Image is an in-memory image of linear, floating point RGB samples.
+// Samples are unbounded; values above 1 represent highlights brighter than display white.
+type Image struct {
+	// Pix holds the image's samples in R, G, B order.
+	// The sample at (x, y) starts at Pix[(y-Rect.Min.Y)*Stride + (x-Rect.Min.X)*3].
+	Pix []float32
+	// Stride is the Pix stride (in samples) between vertically adjacent pixels.
+	Stride int
+	// Rect is the image's bounds.
+	Rect image.Rectangle
+}
+
+// NewImage returns a new Image with the given bounds.
+func NewImage(r image.Rectangle) *Image {
+	return &Image{
+		Pix:    make([]float32, 3*r.Dx()*r.Dy()),
+		Stride: 3 * r.Dx(),
+		Rect:   r,
+	}
+}
+
+// ColorModel returns color.RGBA64Model. Colors returned by At are clamped to [0, 1].
+func (p *Image) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+// Bounds returns the image bounds.
+func (p *Image) Bounds() image.Rectangle {
+	return p.Rect
+}
+
+// At returns the color at (x, y) clamped to the displayable range.
+func (p *Image) At(x, y int) color.Color {
+	if !(image.Point{X: x, Y: y}.In(p.Rect)) {
+		return color.RGBA64{}
+	}
+	r, g, b := p.RGBAt(x, y)
+	return color.RGBA64{R: clamp16(r), G: clamp16(g), B: clamp16(b), A: 0xffff}
+}
+
+// PixOffset returns the index of the first sample of the pixel at (x, y).
+func (p *Image) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*3
+}
+
+// RGBAt returns the linear RGB samples at (x, y).
+func (p *Image) RGBAt(x, y int) (r, g, b float32) {
+	if !(image.Point{X: x, Y: y}.In(p.Rect)) {
+		return 0, 0, 0
+	}
+	i := p.PixOffset(x, y)
+	return p.Pix[i], p.Pix[i+1], p.Pix[i+2]
+}
+
+// SetRGB sets the linear RGB samples at (x, y).
+func (p *Image) SetRGB(x, y int, r, g, b float32) {
+	if !(image.Point{X: x, Y: y}.In(p.Rect)) {
+		return
+	}
+	i := p.PixOffset(x, y)
+	p.Pix[i], p.Pix[i+1], p.Pix[i+2] = r, g, b
+}
+
+// Luminance returns the Rec. 709 relative luminance of a linear RGB color.
+func Luminance(r, g, b float32) float32 {
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// FromImage converts an sRGB encoded image to a linear HDR image.
+// Alpha is ignored; colors are un-premultiplied before linearization.
+func FromImage(img image.Image) *Image {
+	bounds := img.Bounds()
+	out := NewImage(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBA64Model.Convert(img.At(x, y)).(color.NRGBA64) //nolint:errcheck
+			out.SetRGB(x, y,
+				float32(SRGBToLinear(float64(c.R)/0xffff)),
+				float32(SRGBToLinear(float64(c.G)/0xffff)),
+				float32(SRGBToLinear(float64(c.B)/0xffff)),
+			)
+		}
+	}
+	return out
+}
+
+// SRGBToLinear converts an sRGB encoded value in [0, 1] to linear light.
+func SRGBToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// LinearToSRGB converts a linear light value in [0, 1] to its sRGB encoding.
+func LinearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+func clamp16(v float32) uint16 {
+	switch {
+	case !(v > 0):
+		return 0
+	case v >= 1:
+		return 0xffff
+	default:
+		return uint16(v*0xffff + 0.5)
+	}
+}
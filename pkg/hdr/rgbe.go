@@ -0,0 +1,425 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package hdr
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	image.RegisterFormat("hdr", "#?RADIANCE", decodeImage, DecodeConfig)
+	image.RegisterFormat("hdr", "#?RGBE", decodeImage, DecodeConfig)
+}
+
+const (
+	formatRGBE = "32-bit_rle_rgbe"
+
+	// minRLEWidth and maxRLEWidth bound the scanline widths that may use the run-length encoding.
+	minRLEWidth = 8
+	maxRLEWidth = 0x7fff
+
+	// maxDimension bounds the width and height in headers, so that their product can't overflow.
+	maxDimension = math.MaxInt32
+)
+
+// DefaultMaxPixels is the default of DecodeOptions.MaxPixels: 16 megapixels, which decode to
+// 192 MiB of samples.
+const DefaultMaxPixels = 1 << 24
+
+// DecodeOptions are the options of DecodeWithOptions.
+type DecodeOptions struct {
+	// MaxPixels is the maximum number of pixels of a decoded image. Zero means
+	// DefaultMaxPixels.
+	MaxPixels int
+}
+
+var errInvalid = errors.New("hdr: invalid format")
+
+type header struct {
+	width, height int
+	flipY         bool
+	flipX         bool
+	exposure      float64
+}
+
+// Decode reads a Radiance RGBE image from r with the default DecodeOptions.
+// Pixel values are scaled by the inverse of any EXPOSURE recorded in the header so that
+// the returned samples are in the original radiance units.
+func Decode(r io.Reader) (*Image, error) {
+	return DecodeWithOptions(r, DecodeOptions{})
+}
+
+// DecodeWithOptions reads a Radiance RGBE image from r as Decode does. Images with more than
+// o.MaxPixels pixels are rejected, and memory for the samples is allocated as scanlines are
+// read rather than from the size in the header.
+func DecodeWithOptions(r io.Reader, o DecodeOptions) (*Image, error) {
+	maxPixels := o.MaxPixels
+	if maxPixels == 0 {
+		maxPixels = DefaultMaxPixels
+	}
+	br := bufio.NewReader(r)
+	h, err := readHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	if h.width > maxPixels || h.height > maxPixels || h.height > 0 && h.width > maxPixels/h.height {
+		return nil, fmt.Errorf("hdr: image of %dx%d pixels is too large", h.width, h.height)
+	}
+
+	img := &Image{Stride: 3 * h.width, Rect: image.Rect(0, 0, h.width, h.height)}
+	if h.width == 0 || h.height == 0 {
+		img.Pix = []float32{}
+		return img, nil
+	}
+	scanline := make([]uint8, h.width*4)
+	scale := float32(1 / h.exposure)
+	for range h.height {
+		if err = readScanline(br, scanline); err != nil {
+			return nil, err
+		}
+		for col := range h.width {
+			i := col * 4
+			if h.flipX {
+				i = (h.width - 1 - col) * 4
+			}
+			r, g, b := rgbeToFloat(scanline[i], scanline[i+1], scanline[i+2], scanline[i+3])
+			img.Pix = append(img.Pix, r*scale, g*scale, b*scale)
+		}
+	}
+	if h.flipY {
+		// The scanlines were stored bottom up.
+		tmp := make([]float32, img.Stride)
+		for y := range h.height / 2 {
+			top, bottom := img.Pix[y*img.Stride:][:img.Stride], img.Pix[(h.height-1-y)*img.Stride:][:img.Stride]
+			copy(tmp, top)
+			copy(top, bottom)
+			copy(bottom, tmp)
+		}
+	}
+	return img, nil
+}
+
+// DecodeConfig returns the dimensions of a Radiance RGBE image without decoding the pixels.
+func DecodeConfig(r io.Reader) (image.Config, error) {
+	h, err := readHeader(bufio.NewReader(r))
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{
+		ColorModel: color.RGBA64Model,
+		Width:      h.width,
+		Height:     h.height,
+	}, nil
+}
+
+func decodeImage(r io.Reader) (image.Image, error) {
+	return Decode(r)
+}
+
+// Encode writes img to w in the Radiance RGBE format using run-length encoded scanlines.
+func Encode(w io.Writer, img *Image) error {
+	bw := bufio.NewWriter(w)
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	if _, err := fmt.Fprintf(bw, "#?RADIANCE\nFORMAT=%s\n\n-Y %d +X %d\n", formatRGBE, height, width); err != nil {
+		return err
+	}
+
+	scanline := make([]uint8, width*4)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := range width {
+			r, g, b := img.RGBAt(img.Rect.Min.X+x, y)
+			scanline[x*4], scanline[x*4+1], scanline[x*4+2], scanline[x*4+3] = floatToRGBE(r, g, b)
+		}
+		if err := writeScanline(bw, scanline); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func readHeader(br *bufio.Reader) (header, error) {
+	h := header{exposure: 1}
+
+	magic, err := readLine(br)
+	if err != nil {
+		return h, err
+	}
+	if magic != "#?RADIANCE" && magic != "#?RGBE" {
+		return h, errInvalid
+	}
+
+	format := formatRGBE
+	for {
+		line, err := readLine(br)
+		if err != nil {
+			return h, err
+		}
+		if line == "" {
+			break
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "FORMAT":
+			format = strings.TrimSpace(value)
+		case "EXPOSURE":
+			// Multiple EXPOSURE lines are cumulative.
+			e, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || e <= 0 {
+				return h, fmt.Errorf("hdr: invalid exposure %q", value)
+			}
+			h.exposure *= e
+		}
+	}
+	if format != formatRGBE {
+		// XYZE pixels would need a conversion to the RGB primaries of Image.
+		return h, fmt.Errorf("hdr: unsupported format %q", format)
+	}
+
+	resolution, err := readLine(br)
+	if err != nil {
+		return h, err
+	}
+	fields := strings.Fields(resolution)
+	if len(fields) != 4 {
+		return h, fmt.Errorf("hdr: invalid resolution %q", resolution)
+	}
+	for _, f := range []string{fields[0], fields[2]} {
+		if len(f) != 2 || f[0] != '+' && f[0] != '-' || f[1] != 'X' && f[1] != 'Y' {
+			return h, fmt.Errorf("hdr: invalid resolution %q", resolution)
+		}
+	}
+	// Only the standard scanline orientations (rows of X, ordered by Y) are supported.
+	if fields[0][1] != 'Y' || fields[2][1] != 'X' {
+		return h, fmt.Errorf("hdr: unsupported orientation %q", resolution)
+	}
+	h.flipY = fields[0][0] == '+'
+	h.flipX = fields[2][0] == '-'
+	if h.height, err = strconv.Atoi(fields[1]); err != nil || h.height < 0 {
+		return h, fmt.Errorf("hdr: invalid resolution %q", resolution)
+	}
+	if h.width, err = strconv.Atoi(fields[3]); err != nil || h.width < 0 {
+		return h, fmt.Errorf("hdr: invalid resolution %q", resolution)
+	}
+	if h.width > maxDimension || h.height > maxDimension {
+		return h, fmt.Errorf("hdr: image of %dx%d pixels is too large", h.width, h.height)
+	}
+	return h, nil
+}
+
+func readLine(br *bufio.Reader) (string, error) {
+	line, err := br.ReadString('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return "", io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readScanline reads one scanline of RGBE pixels into dst, handling flat,
+// old-style run-length and new-style (per component) run-length encodings.
+func readScanline(br *bufio.Reader, dst []uint8) error {
+	width := len(dst) / 4
+	if width < minRLEWidth || width > maxRLEWidth {
+		return readOldScanline(br, dst)
+	}
+
+	var prefix [4]uint8
+	if _, err := io.ReadFull(br, prefix[:]); err != nil {
+		return unexpected(err)
+	}
+	if prefix[0] != 2 || prefix[1] != 2 || prefix[2]&0x80 != 0 {
+		copy(dst, prefix[:])
+		return readOldScanlineFrom(br, dst, 1)
+	}
+	if int(prefix[2])<<8|int(prefix[3]) != width {
+		return errors.New("hdr: scanline width mismatch")
+	}
+
+	// New-style run-length encoding stores each component separately.
+	for c := range 4 {
+		for x := 0; x < width; {
+			count, err := br.ReadByte()
+			if err != nil {
+				return unexpected(err)
+			}
+			if count > 128 {
+				n := int(count - 128)
+				if x+n > width {
+					return errInvalid
+				}
+				v, err := br.ReadByte()
+				if err != nil {
+					return unexpected(err)
+				}
+				for ; n > 0; n-- {
+					dst[x*4+c] = v
+					x++
+				}
+			} else {
+				n := int(count)
+				if n == 0 || x+n > width {
+					return errInvalid
+				}
+				for ; n > 0; n-- {
+					v, err := br.ReadByte()
+					if err != nil {
+						return unexpected(err)
+					}
+					dst[x*4+c] = v
+					x++
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func readOldScanline(br *bufio.Reader, dst []uint8) error {
+	return readOldScanlineFrom(br, dst, 0)
+}
+
+// readOldScanlineFrom reads flat or old-style run-length pixels starting at pixel start.
+// A pixel of (1, 1, 1, n) repeats the previous pixel n << shift times, where shift grows by
+// 8 for each consecutive repeat pixel.
+func readOldScanlineFrom(br *bufio.Reader, dst []uint8, start int) error {
+	width := len(dst) / 4
+	shift := uint(0)
+	x := start
+	if x > 0 && dst[0] == 1 && dst[1] == 1 && dst[2] == 1 {
+		return errInvalid
+	}
+	for x < width {
+		var px [4]uint8
+		if _, err := io.ReadFull(br, px[:]); err != nil {
+			return unexpected(err)
+		}
+		if px[0] == 1 && px[1] == 1 && px[2] == 1 {
+			if x == 0 {
+				return errInvalid
+			}
+			n := int(px[3]) << shift
+			if x+n > width {
+				return errInvalid
+			}
+			prev := dst[(x-1)*4 : x*4]
+			for ; n > 0; n-- {
+				copy(dst[x*4:], prev)
+				x++
+			}
+			shift += 8
+			continue
+		}
+		copy(dst[x*4:], px[:])
+		x++
+		shift = 0
+	}
+	return nil
+}
+
+func writeScanline(w *bufio.Writer, scanline []uint8) error {
+	width := len(scanline) / 4
+	if width < minRLEWidth || width > maxRLEWidth {
+		_, err := w.Write(scanline)
+		return err
+	}
+
+	if _, err := w.Write([]uint8{2, 2, uint8(width >> 8), uint8(width)}); err != nil {
+		return err
+	}
+	component := make([]uint8, width)
+	for c := range 4 {
+		for x := range width {
+			component[x] = scanline[x*4+c]
+		}
+		if err := writeRLEComponent(w, component); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRLEComponent writes a single component of a scanline using runs of at least 4
+// identical values and literal dumps of up to 128 values otherwise.
+func writeRLEComponent(w *bufio.Writer, data []uint8) error {
+	const minRun = 4
+	for i := 0; i < len(data); {
+		// Find the next run of at least minRun identical bytes.
+		runStart := i
+		runLen := 0
+		for runStart < len(data) {
+			runLen = 1
+			for runStart+runLen < len(data) && runLen < 127 && data[runStart+runLen] == data[runStart] {
+				runLen++
+			}
+			if runLen >= minRun {
+				break
+			}
+			runStart += runLen
+		}
+		if runLen < minRun {
+			runStart = len(data)
+		}
+
+		// Dump the literal values preceding the run.
+		for i < runStart {
+			n := min(runStart-i, 128)
+			if err := w.WriteByte(uint8(n)); err != nil {
+				return err
+			}
+			if _, err := w.Write(data[i : i+n]); err != nil {
+				return err
+			}
+			i += n
+		}
+
+		if runStart < len(data) {
+			if _, err := w.Write([]uint8{uint8(128 + runLen), data[runStart]}); err != nil {
+				return err
+			}
+			i = runStart + runLen
+		}
+	}
+	return nil
+}
+
+func rgbeToFloat(r, g, b, e uint8) (float32, float32, float32) {
+	if e == 0 {
+		return 0, 0, 0
+	}
+	f := math.Ldexp(1, int(e)-(128+8))
+	return float32((float64(r) + 0.5) * f), float32((float64(g) + 0.5) * f), float32((float64(b) + 0.5) * f)
+}
+
+func floatToRGBE(r, g, b float32) (uint8, uint8, uint8, uint8) {
+	v := float64(max(r, g, b))
+	if v < 1e-32 {
+		return 0, 0, 0, 0
+	}
+	m, e := math.Frexp(v)
+	scale := m * 256 / v
+	return uint8(math.Max(0, float64(r)) * scale),
+		uint8(math.Max(0, float64(g)) * scale),
+		uint8(math.Max(0, float64(b)) * scale),
+		uint8(e + 128)
+}
+
+func unexpected(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
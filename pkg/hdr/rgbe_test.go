@@ -0,0 +1,157 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package hdr
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"testing"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	for _, width := range []int{4, 16, 200} {
+		img := NewImage(image.Rect(0, 0, width, 3))
+		for y := range 3 {
+			for x := range width {
+				// Mix of runs and varied values to exercise both RLE paths.
+				v := float32(x/5) * 0.37
+				img.SetRGB(x, y, v, v*float32(y+1)*10, 0.001)
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := Encode(&buf, img); err != nil {
+			t.Fatalf("Encode(width=%d) failed: %v", width, err)
+		}
+		decoded, err := Decode(&buf)
+		if err != nil {
+			t.Fatalf("Decode(width=%d) failed: %v", width, err)
+		}
+		if !decoded.Rect.Eq(img.Rect) {
+			t.Fatalf("Decode(width=%d) bounds = %v, want %v", width, decoded.Rect, img.Rect)
+		}
+		for i, want := range img.Pix {
+			got := decoded.Pix[i]
+			// RGBE has 8 bits of mantissa shared by the largest channel of each pixel.
+			px := i - i%3
+			limit := 0.01 * math.Max(1, float64(max(img.Pix[px], img.Pix[px+1], img.Pix[px+2])))
+			if math.Abs(float64(got-want)) > limit {
+				t.Fatalf("width=%d: sample %d = %v, want %v", width, i, got, want)
+			}
+		}
+	}
+}
+
+func TestDecodeRegistered(t *testing.T) {
+	img := NewImage(image.Rect(0, 0, 10, 2))
+	img.SetRGB(3, 1, 4, 2, 1)
+	var buf bytes.Buffer
+	if err := Encode(&buf, img); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("image.DecodeConfig failed: %v", err)
+	}
+	if format != "hdr" || cfg.Width != 10 || cfg.Height != 2 {
+		t.Errorf("image.DecodeConfig = %v, %q; want 10x2, \"hdr\"", cfg, format)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("image.Decode failed: %v", err)
+	}
+	if _, ok := decoded.(*Image); !ok {
+		t.Errorf("image.Decode returned %T, want *Image", decoded)
+	}
+}
+
+func TestDecodeFlatAndOldRLE(t *testing.T) {
+	// 2x2 image with flat pixels on the first row and an old-style repeat on the second.
+	data := []byte("#?RGBE\nEXPOSURE=2\n\n+Y 2 +X 2\n")
+	data = append(data,
+		128, 64, 32, 129, 0, 0, 0, 0, // first scanline, stored bottom-up
+		128, 128, 128, 128, 1, 1, 1, 1, // second scanline: repeat previous pixel once
+	)
+
+	img, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	// +Y stores rows bottom to top, so the first scanline is y=1.
+	if r, g, b := img.RGBAt(0, 1); math.Abs(float64(r)-0.5) > 0.01 || math.Abs(float64(g)-0.25) > 0.01 || math.Abs(float64(b)-0.125) > 0.01 {
+		t.Errorf("RGBAt(0, 1) = %v, %v, %v; want ~0.5, 0.25, 0.125 after exposure", r, g, b)
+	}
+	if r, _, _ := img.RGBAt(1, 1); r != 0 {
+		t.Errorf("RGBAt(1, 1) = %v, want 0", r)
+	}
+	r0, _, _ := img.RGBAt(0, 0)
+	r1, _, _ := img.RGBAt(1, 0)
+	if r0 == 0 || r0 != r1 {
+		t.Errorf("old-style run not expanded: RGBAt(0, 0) = %v, RGBAt(1, 0) = %v", r0, r1)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := map[string]string{
+		"bad magic":       "P6\n",
+		"bad format":      "#?RADIANCE\nFORMAT=foo\n\n-Y 1 +X 1\n",
+		"bad resolution":  "#?RADIANCE\n\n-Y one +X 1\n",
+		"bad orientation": "#?RADIANCE\n\n-X 1 +Y 1\n",
+		"missing signs":   "#?RADIANCE\n\nY 1 X 1\n",
+		"bad signs":       "#?RADIANCE\n\n*Y 1 +X 1\n",
+		"too large":       "#?RADIANCE\n\n-Y 2000000000 +X 2000000000\n",
+		"too wide":        "#?RADIANCE\n\n-Y 0 +X 9223372036854775807\n",
+		"xyze":            "#?RADIANCE\nFORMAT=32-bit_rle_xyze\n\n-Y 1 +X 1\n\x80\x80\x80\x81",
+		"truncated":       "#?RADIANCE\n\n-Y 1 +X 1\n\x01",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Decode(bytes.NewReader([]byte(data))); err == nil {
+				t.Error("Decode should have failed")
+			}
+		})
+	}
+}
+
+func TestDecodeMaxPixels(t *testing.T) {
+	// 4097x4097 is just over DefaultMaxPixels; the header alone must be rejected.
+	data := []byte("#?RADIANCE\n\n-Y 4097 +X 4097\n")
+	if _, err := Decode(bytes.NewReader(data)); err == nil {
+		t.Error("Decode should have rejected an image over DefaultMaxPixels")
+	}
+
+	var buf bytes.Buffer
+	src := NewImage(image.Rect(0, 0, 3, 2))
+	if err := Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecodeWithOptions(bytes.NewReader(buf.Bytes()), DecodeOptions{MaxPixels: 5}); err == nil {
+		t.Error("DecodeWithOptions should have rejected 6 pixels with MaxPixels 5")
+	}
+	img, err := DecodeWithOptions(bytes.NewReader(buf.Bytes()), DecodeOptions{MaxPixels: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Rect != src.Rect || len(img.Pix) != len(src.Pix) {
+		t.Errorf("got %v with %d samples, want %v with %d", img.Rect, len(img.Pix), src.Rect, len(src.Pix))
+	}
+
+	// A header that passes the limit but is followed by no data fails without allocating the image.
+	data = []byte("#?RADIANCE\n\n-Y 100000 +X 100000\n")
+	if _, err := DecodeWithOptions(bytes.NewReader(data), DecodeOptions{MaxPixels: math.MaxInt}); err == nil {
+		t.Error("DecodeWithOptions should have failed on missing scanlines")
+	}
+}
+
+func TestImageAt(t *testing.T) {
+	img := NewImage(image.Rect(0, 0, 1, 1))
+	img.SetRGB(0, 0, 2, 0.5, -1)
+	r, g, b, a := img.At(0, 0).RGBA()
+	if r != 0xffff || g != 0x8000 || b != 0 || a != 0xffff {
+		t.Errorf("At(0, 0) = %#x, %#x, %#x, %#x; want clamped values", r, g, b, a)
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package histogram
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"math"
+	"slices"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+// Mode defines the automatic adjustment.
+type Mode int
+
+const (
+	// AutoLevels stretches each color channel separately so that its range covers [0, 255].
+	// This also neutralizes color casts.
+	AutoLevels Mode = iota
+	// AutoContrast stretches all color channels by the same amount, preserving hues.
+	AutoContrast
+	// Equalize remaps luma so that its histogram is as flat as possible.
+	Equalize
+	// CLAHE equalizes luma within a grid of tiles, limiting the contrast gain of each tile
+	// and interpolating between neighboring tiles.
+	CLAHE
+
+	_maxMode
+)
+
+// Default values used when the corresponding Op fields are zero, and a typical Clip.
+const (
+	DefaultClip      = 0.001
+	DefaultTiles     = 8
+	DefaultClipLimit = 3
+)
+
+// Op describes an automatic adjustment.
+type Op struct {
+	Mode Mode
+	// Clip is the fraction of pixels in [0, 0.5) ignored at each end of the histogram when
+	// AutoLevels and AutoContrast determine the range to stretch. Ignored pixels are clipped.
+	Clip float64
+	// Tiles is the number of CLAHE tiles along each axis. Zero means DefaultTiles.
+	Tiles int
+	// ClipLimit caps each bin of a CLAHE tile histogram at this multiple of the average bin
+	// count; the excess is redistributed over all bins. Lower limits give gentler results;
+	// it must be at least 1. Zero means DefaultClipLimit.
+	ClipLimit float64
+}
+
+// IsValid reports whether the operation can be applied.
+func (o Op) IsValid() bool {
+	return o.Mode >= 0 && o.Mode < _maxMode &&
+		o.Clip >= 0 && o.Clip < 0.5 && o.Tiles >= 0 &&
+		(o.ClipLimit == 0 || o.ClipLimit >= 1)
+}
+
+// Apply analyzes the region r of dst and adjusts it.
+//
+// Colors are adjusted non-premultiplied and alpha is preserved. Transparent pixels neither
+// contribute to the histograms nor change. Equalize and CLAHE shift all three channels by the
+// change in luma, which leaves the chroma of each pixel untouched unless a channel clips.
+func Apply(cfg core.Config, dst image.Image, r image.Rectangle, o Op, output core.Output) (image.Image, error) {
+	if !o.IsValid() {
+		return nil, errors.New("invalid histogram operation")
+	}
+	r = r.Intersect(dst.Bounds())
+
+	model := cfg.DefaultColorModel()
+	if core.IsColorModelSupported(dst.ColorModel()) {
+		model = dst.ColorModel()
+	}
+	out, outPt, err := core.ResolveOutput(output, cfg.DefaultOutputMode(), dst, r, model)
+	if err != nil {
+		return nil, err
+	}
+	if r.Empty() {
+		return out, nil
+	}
+
+	pixIter := cfg.PixelIterator()
+	img := toNRGBA(dst, r)
+	orig := slices.Clone(img.Pix)
+	switch o.Mode {
+	case AutoLevels:
+		hs := compute(pixIter, img)
+		mapChannels(pixIter, img, [3]*[256]uint8{
+			stretch(&hs.R, o.Clip), stretch(&hs.G, o.Clip), stretch(&hs.B, o.Clip),
+		})
+	case AutoContrast:
+		hs := compute(pixIter, img)
+		var all Histogram
+		all.add(&hs.R)
+		all.add(&hs.G)
+		all.add(&hs.B)
+		lut := stretch(&all, o.Clip)
+		mapChannels(pixIter, img, [3]*[256]uint8{lut, lut, lut})
+	case Equalize:
+		lut := equalize(&compute(pixIter, img).Luma)
+		mapLuma(pixIter, img, func(x, y int, v uint8) uint8 { return lut[v] })
+	case CLAHE:
+		clahe(pixIter, img, o)
+	}
+
+	// Only the pixels that the adjustment changed are written, so that the others, such as all
+	// of them when the lookup tables are the identity, keep their value in the model of out.
+	internal.CopyToOutput(dst, r, out, outPt)
+	changed := make([]bool, len(img.Pix)/4)
+	for i := range changed {
+		changed[i] = !bytes.Equal(img.Pix[i*4:i*4+4], orig[i*4:i*4+4])
+	}
+	if err = internal.WriteNRGBAMasked(pixIter, img, changed, out, outPt); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// stretch returns a lookup table mapping the range between the clip and 1-clip percentiles
+// of h linearly to [0, 255]. Degenerate ranges map to the identity.
+func stretch(h *Histogram, clip float64) *[256]uint8 {
+	lo, hi := float64(h.Percentile(clip)), float64(h.Percentile(1-clip))
+	lut := identity()
+	if hi <= lo {
+		return lut
+	}
+	for v := range lut {
+		lut[v] = uint8(core.Clamp(math.Round((float64(v)-lo)*255/(hi-lo)), 0, 255))
+	}
+	return lut
+}
+
+// equalize returns the lookup table that maps the cumulative distribution of h linearly to
+// [0, 255], with the smallest counted value mapping to 0.
+func equalize(h *Histogram) *[256]uint8 {
+	total := h.Total()
+	first := h[h.Min()]
+	lut := identity()
+	if total == first {
+		return lut
+	}
+	var cum uint64
+	for v, c := range h {
+		cum += c
+		if cum < first {
+			lut[v] = 0
+			continue
+		}
+		lut[v] = uint8((float64(cum-first)*255)/float64(total-first) + 0.5)
+	}
+	return lut
+}
+
+func identity() *[256]uint8 {
+	var lut [256]uint8
+	for v := range lut {
+		lut[v] = uint8(v)
+	}
+	return &lut
+}
+
+// mapChannels applies one lookup table per color channel to the non-transparent pixels of img.
+func mapChannels(pixIter core.PixelIterator, img *image.NRGBA, luts [3]*[256]uint8) {
+	core.IterateRows(pixIter, img.Rect.Dy(), func(y int) {
+		pix := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		for i := 0; i < len(pix); i += 4 {
+			if pix[i+3] == 0 {
+				continue
+			}
+			pix[i], pix[i+1], pix[i+2] = luts[0][pix[i]], luts[1][pix[i+1]], luts[2][pix[i+2]]
+		}
+	})
+}
+
+// mapLuma replaces the luma of the non-transparent pixels of img with the value returned by
+// fn, shifting all channels by the same amount so Cb and Cr stay the same.
+func mapLuma(pixIter core.PixelIterator, img *image.NRGBA, fn func(x, y int, v uint8) uint8) {
+	core.IterateRows(pixIter, img.Rect.Dy(), func(y int) {
+		pix := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		for i := 0; i < len(pix); i += 4 {
+			if pix[i+3] == 0 {
+				continue
+			}
+			v := luma(pix[i], pix[i+1], pix[i+2])
+			d := int(fn(i/4, y, v)) - int(v)
+			for c := range 3 {
+				pix[i+c] = uint8(max(0, min(255, int(pix[i+c])+d)))
+			}
+		}
+	})
+}
+
+// clahe equalizes img in tiles. Each pixel's luma is mapped through the lookup tables of the
+// four nearest tile centers and bilinearly interpolated.
+func clahe(pixIter core.PixelIterator, img *image.NRGBA, o Op) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	tiles := o.Tiles
+	if tiles == 0 {
+		tiles = DefaultTiles
+	}
+	limit := o.ClipLimit
+	if limit == 0 {
+		limit = DefaultClipLimit
+	}
+	tx, ty := min(tiles, w), min(tiles, h)
+	tw, th := (w+tx-1)/tx, (h+ty-1)/ty
+	// Rounding the tile size up may leave trailing tiles empty; drop them.
+	tx, ty = (w+tw-1)/tw, (h+th-1)/th
+
+	luts := make([]*[256]uint8, tx*ty)
+	core.IterateRows(pixIter, len(luts), func(t int) {
+		rect := image.Rect(t%tx*tw, t/tx*th, min(t%tx*tw+tw, w), min(t/tx*th+th, h))
+		var hist Histogram
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				p := img.Pix[y*img.Stride+x*4:]
+				if p[3] != 0 {
+					hist[luma(p[0], p[1], p[2])]++
+				}
+			}
+		}
+		clipHistogram(&hist, limit)
+		luts[t] = equalizeTile(&hist)
+	})
+
+	// grid returns the tiles around the pixel coordinate p and the weight of the second one.
+	grid := func(p, size, n int) (int, int, float64) {
+		f := (float64(p)+0.5)/float64(size) - 0.5
+		i := int(math.Floor(f))
+		if i < 0 {
+			return 0, 0, 0
+		}
+		if i >= n-1 {
+			return n - 1, n - 1, 0
+		}
+		return i, i + 1, f - float64(i)
+	}
+	mapLuma(pixIter, img, func(x, y int, v uint8) uint8 {
+		x0, x1, fx := grid(x, tw, tx)
+		y0, y1, fy := grid(y, th, ty)
+		top := float64(luts[y0*tx+x0][v])*(1-fx) + float64(luts[y0*tx+x1][v])*fx
+		bottom := float64(luts[y1*tx+x0][v])*(1-fx) + float64(luts[y1*tx+x1][v])*fx
+		return uint8(top*(1-fy) + bottom*fy + 0.5)
+	})
+}
+
+// clipHistogram caps the bins of h at limit times the average bin count and spreads the
+// excess evenly over all bins.
+func clipHistogram(h *Histogram, limit float64) {
+	ceiling := uint64(max(1, limit*float64(h.Total())/256))
+	var excess uint64
+	for v, c := range h {
+		if c > ceiling {
+			excess += c - ceiling
+			h[v] = ceiling
+		}
+	}
+	share, rest := excess/256, excess%256
+	for v := range h {
+		h[v] += share
+	}
+	// Spread the remainder across the whole range rather than the first bins.
+	if rest > 0 {
+		step := 256 / rest
+		for v := uint64(0); v < rest; v++ {
+			h[v*step]++
+		}
+	}
+}
+
+// equalizeTile returns the lookup table mapping the cumulative distribution of h to [0, 255].
+// Unlike equalize it does not pin the darkest value to 0, which would exaggerate the contrast
+// of flat tiles.
+func equalizeTile(h *Histogram) *[256]uint8 {
+	total := h.Total()
+	if total == 0 {
+		return identity()
+	}
+	var lut [256]uint8
+	var cum uint64
+	for v, c := range h {
+		cum += c
+		lut[v] = uint8(float64(cum)*255/float64(total) + 0.5)
+	}
+	return &lut
+}
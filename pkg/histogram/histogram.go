@@ -0,0 +1,162 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package histogram computes per-channel and luma histograms and statistics of an image region,
+// and implements automatic tone adjustments built on them: auto levels, auto contrast,
+// histogram equalization and contrast-limited adaptive histogram equalization (CLAHE).
+package histogram
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// bandRows is the number of rows counted by each parallel task before the partial
+// histograms are merged.
+const bandRows = 16
+
+// Histogram counts the pixels with each 8-bit channel value.
+type Histogram [256]uint64
+
+// Total returns the number of counted pixels.
+func (h *Histogram) Total() uint64 {
+	var n uint64
+	for _, c := range h {
+		n += c
+	}
+	return n
+}
+
+// Mean returns the average value, or 0 for an empty histogram.
+func (h *Histogram) Mean() float64 {
+	var sum, n float64
+	for v, c := range h {
+		sum += float64(v) * float64(c)
+		n += float64(c)
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / n
+}
+
+// StdDev returns the population standard deviation of the values.
+func (h *Histogram) StdDev() float64 {
+	mean := h.Mean()
+	var sum, n float64
+	for v, c := range h {
+		d := float64(v) - mean
+		sum += d * d * float64(c)
+		n += float64(c)
+	}
+	if n == 0 {
+		return 0
+	}
+	return math.Sqrt(sum / n)
+}
+
+// Min returns the smallest counted value, or 0 for an empty histogram.
+func (h *Histogram) Min() uint8 {
+	for v, c := range h {
+		if c > 0 {
+			return uint8(v)
+		}
+	}
+	return 0
+}
+
+// Max returns the largest counted value, or 0 for an empty histogram.
+func (h *Histogram) Max() uint8 {
+	for v := 255; v >= 0; v-- {
+		if h[v] > 0 {
+			return uint8(v)
+		}
+	}
+	return 0
+}
+
+// Percentile returns the smallest value such that at least the fraction p in [0, 1] of the
+// pixels are less than or equal to it. Percentile(0) is Min and Percentile(1) is Max.
+func (h *Histogram) Percentile(p float64) uint8 {
+	target := core.Clamp(p, 0, 1) * float64(h.Total())
+	var cum uint64
+	for v, c := range h {
+		cum += c
+		if cum > 0 && float64(cum) >= target {
+			return uint8(v)
+		}
+	}
+	return 0
+}
+
+// add accumulates the counts of o.
+func (h *Histogram) add(o *Histogram) {
+	for v, c := range o {
+		h[v] += c
+	}
+}
+
+// Histograms holds the histograms of an image region.
+//
+// Colors are counted non-premultiplied and only for pixels that are not fully transparent,
+// since the color of a transparent pixel is meaningless. A counts every pixel.
+type Histograms struct {
+	R, G, B, A Histogram
+	// Luma is the Rec. 601 luma of the colors, as used by JPEG's YCbCr.
+	Luma Histogram
+}
+
+func (h *Histograms) add(o *Histograms) {
+	h.R.add(&o.R)
+	h.G.add(&o.G)
+	h.B.add(&o.B)
+	h.A.add(&o.A)
+	h.Luma.add(&o.Luma)
+}
+
+// Compute returns the histograms of the region r of img. Bands of rows are counted in
+// parallel using the configuration's PixelIterator.
+func Compute(cfg core.Config, img image.Image, r image.Rectangle) *Histograms {
+	return compute(cfg.PixelIterator(), toNRGBA(img, r.Intersect(img.Bounds())))
+}
+
+func compute(pixIter core.PixelIterator, img *image.NRGBA) *Histograms {
+	h := img.Rect.Dy()
+	bands := make([]Histograms, (h+bandRows-1)/bandRows)
+	core.IterateRows(pixIter, len(bands), func(band int) {
+		hs := &bands[band]
+		for y := band * bandRows; y < min((band+1)*bandRows, h); y++ {
+			pix := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+			for i := 0; i < len(pix); i += 4 {
+				hs.A[pix[i+3]]++
+				if pix[i+3] == 0 {
+					continue
+				}
+				hs.R[pix[i]]++
+				hs.G[pix[i+1]]++
+				hs.B[pix[i+2]]++
+				hs.Luma[luma(pix[i], pix[i+1], pix[i+2])]++
+			}
+		}
+	})
+	out := &Histograms{}
+	for i := range bands {
+		out.add(&bands[i])
+	}
+	return out
+}
+
+// luma returns the Rec. 601 luma of a color, rounded as in color.RGBToYCbCr.
+func luma(r, g, b uint8) uint8 {
+	return uint8((19595*uint32(r) + 38470*uint32(g) + 7471*uint32(b) + 1<<15) >> 16)
+}
+
+// toNRGBA copies the region r of img to a new NRGBA image with bounds at the origin.
+func toNRGBA(img image.Image, r image.Rectangle) *image.NRGBA {
+	out := image.NewNRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(out, out.Rect, img, r.Min, draw.Src)
+	return out
+}
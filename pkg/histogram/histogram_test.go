@@ -0,0 +1,267 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package histogram
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+// ramp returns an opaque image whose channels span [lo, hi] horizontally, with G and B
+// offset to give it a color cast.
+func ramp(r image.Rectangle, lo, hi int) *image.NRGBA {
+	img := image.NewNRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			v := lo + (hi-lo)*(x-r.Min.X)/(r.Dx()-1)
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(v), G: uint8(v * 3 / 4), B: uint8(v / 2), A: 255})
+		}
+	}
+	return img
+}
+
+func TestHistogramStatistics(t *testing.T) {
+	var h Histogram
+	h[10], h[20], h[30] = 1, 2, 1
+	if got := h.Total(); got != 4 {
+		t.Errorf("Total = %d, want 4", got)
+	}
+	if got := h.Mean(); got != 20 {
+		t.Errorf("Mean = %v, want 20", got)
+	}
+	if got := h.StdDev(); math.Abs(got-math.Sqrt(50)) > 1e-9 {
+		t.Errorf("StdDev = %v, want %v", got, math.Sqrt(50))
+	}
+	if h.Min() != 10 || h.Max() != 30 {
+		t.Errorf("Min, Max = %d, %d, want 10, 30", h.Min(), h.Max())
+	}
+	for _, tt := range []struct {
+		p    float64
+		want uint8
+	}{{0, 10}, {0.25, 10}, {0.26, 20}, {0.75, 20}, {0.76, 30}, {1, 30}} {
+		if got := h.Percentile(tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+
+	var empty Histogram
+	if empty.Mean() != 0 || empty.StdDev() != 0 || empty.Min() != 0 || empty.Max() != 0 || empty.Percentile(0.5) != 0 {
+		t.Error("empty histogram statistics should be zero")
+	}
+}
+
+func TestCompute(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(5, 5, 105, 45))
+	for y := 5; y < 45; y++ {
+		for x := 5; x < 105; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 128})
+		}
+	}
+	img.SetNRGBA(6, 6, color.NRGBA{})
+
+	r := image.Rect(0, 0, 50, 50)
+	hs := Compute(internal.DefaultConfig, img, r)
+	n := uint64(45 * 40)
+	if hs.A.Total() != n || hs.A[0] != 1 || hs.A[128] != n-1 {
+		t.Errorf("alpha histogram: total %d, [0] %d, [128] %d", hs.A.Total(), hs.A[0], hs.A[128])
+	}
+	if hs.R.Total() != n-1 || hs.Luma.Total() != n-1 {
+		t.Errorf("color histograms count %d pixels, want %d", hs.R.Total(), n-1)
+	}
+	if hs.R.Min() != 5 || hs.R.Max() != 49 || hs.G.Min() != 5 || hs.G.Max() != 44 {
+		t.Errorf("R range [%d, %d], G range [%d, %d]", hs.R.Min(), hs.R.Max(), hs.G.Min(), hs.G.Max())
+	}
+	if hs.B.Min() != 200 || hs.B.Max() != 200 {
+		t.Errorf("B range [%d, %d], want 200", hs.B.Min(), hs.B.Max())
+	}
+
+	serial := compute(core.NewSerialPixelIterator(), toNRGBA(img, img.Rect))
+	parallel := compute(core.NewParallelPixelIterator(4), toNRGBA(img, img.Rect))
+	if *serial != *parallel {
+		t.Error("parallel histograms differ from serial histograms")
+	}
+}
+
+func TestAutoLevels(t *testing.T) {
+	img := ramp(image.Rect(10, 10, 110, 20), 50, 200)
+	out, err := Apply(internal.DefaultConfig, img, img.Rect, Op{Mode: AutoLevels}, core.ToNewImage())
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	hs := Compute(internal.DefaultConfig, out, out.Bounds())
+	for name, h := range map[string]*Histogram{"R": &hs.R, "G": &hs.G, "B": &hs.B} {
+		if h.Min() != 0 || h.Max() != 255 {
+			t.Errorf("%s range [%d, %d], want [0, 255]", name, h.Min(), h.Max())
+		}
+	}
+	// Each channel is stretched separately, which removes the cast.
+	if c := out.(*image.NRGBA).NRGBAAt(60, 15); absDiff(c.R, c.B) > 3 || c.A != 255 {
+		t.Errorf("mid pixel = %v, want neutral", c)
+	}
+
+	// Clipping saturates the extremes.
+	out, _ = Apply(internal.DefaultConfig, img, img.Rect, Op{Mode: AutoLevels, Clip: 0.1}, core.ToNewImage())
+	hs = Compute(internal.DefaultConfig, out, out.Bounds())
+	if hs.R[0] < 100 || hs.R[255] < 100 {
+		t.Errorf("clipped counts %d, %d, want at least 10%% each", hs.R[0], hs.R[255])
+	}
+}
+
+func TestAutoContrast(t *testing.T) {
+	img := ramp(image.Rect(0, 0, 100, 4), 50, 200)
+	rgba := image.NewRGBA(img.Rect)
+	for y := range 4 {
+		for x := range 100 {
+			rgba.Set(x, y, img.At(x, y))
+		}
+	}
+	out, err := Apply(internal.DefaultConfig, rgba, rgba.Rect, Op{Mode: AutoContrast}, core.ToDst())
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if out != image.Image(rgba) {
+		t.Fatal("expected the destination to be modified in place")
+	}
+	hs := Compute(internal.DefaultConfig, out, out.Bounds())
+	if hs.B.Min() != 0 || hs.R.Max() != 255 {
+		t.Errorf("B min %d, R max %d, want 0 and 255", hs.B.Min(), hs.R.Max())
+	}
+	// The cast is kept: red stays brighter than blue.
+	if c := rgba.RGBAAt(50, 2); c.R <= c.B {
+		t.Errorf("mid pixel = %v, want R > B", c)
+	}
+}
+
+func TestEqualize(t *testing.T) {
+	// Most pixels are crowded into a narrow dark band.
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for i := range 64 * 64 {
+		v := uint8(40 + i%20)
+		if i%10 == 0 {
+			v = 220
+		}
+		img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2], img.Pix[i*4+3] = v, v/2, v, 255
+	}
+	img.Pix[3] = 0 // transparent pixels are left alone
+	before := Compute(internal.DefaultConfig, img, img.Rect)
+	out, err := Apply(internal.DefaultConfig, img, img.Rect, Op{Mode: Equalize}, core.ToNewNRGBAImage())
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	res := out.(*image.NRGBA)
+	after := Compute(internal.DefaultConfig, res, res.Rect)
+	if after.Luma.StdDev() < 2*before.Luma.StdDev() {
+		t.Errorf("luma stddev %v -> %v, want a large increase", before.Luma.StdDev(), after.Luma.StdDev())
+	}
+	// Shifting saturated colors clips, so the extremes fall a little short.
+	if after.Luma.Min() > 10 || after.Luma.Max() < 220 {
+		t.Errorf("luma range [%d, %d], want nearly the full range", after.Luma.Min(), after.Luma.Max())
+	}
+	if c := res.NRGBAAt(0, 0); c != (color.NRGBA{220, 110, 220, 0}) {
+		t.Errorf("transparent pixel = %v, want unchanged", c)
+	}
+	// Channels move together, so the chroma of unclipped pixels is unchanged.
+	c := res.NRGBAAt(5, 0)
+	if int(c.R)-int(c.G) != 45-22 || c.R != c.B {
+		t.Errorf("pixel = %v, want R-G = 23 and R = B", c)
+	}
+}
+
+func TestCLAHE(t *testing.T) {
+	// Two regions with low local contrast at very different brightness.
+	img := image.NewNRGBA(image.Rect(0, 0, 128, 64))
+	for y := range 64 {
+		for x := range 128 {
+			v := uint8(20 + (x+y)%8)
+			if x >= 64 {
+				v += 180
+			}
+			img.SetNRGBA(x, y, color.NRGBA{v, v, v, 255})
+		}
+	}
+	cfg := internal.DefaultConfig
+	out, err := Apply(cfg, img, img.Rect, Op{Mode: CLAHE, Tiles: 4}, core.ToNewNRGBAImage())
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	for _, half := range []image.Rectangle{image.Rect(8, 8, 56, 56), image.Rect(72, 8, 120, 56)} {
+		before := Compute(cfg, img, half).Luma.StdDev()
+		after := Compute(cfg, out, half).Luma.StdDev()
+		if after < 2*before {
+			t.Errorf("local stddev in %v: %v -> %v, want a large increase", half, before, after)
+		}
+	}
+
+	// A lower clip limit gives a smaller contrast gain.
+	half := image.Rect(8, 8, 56, 56)
+	low, _ := Apply(cfg, img, img.Rect, Op{Mode: CLAHE, Tiles: 4, ClipLimit: 1.5}, core.ToNewNRGBAImage())
+	high, _ := Apply(cfg, img, img.Rect, Op{Mode: CLAHE, Tiles: 4, ClipLimit: 8}, core.ToNewNRGBAImage())
+	if l, h := Compute(cfg, low, half).Luma.StdDev(), Compute(cfg, high, half).Luma.StdDev(); l >= h {
+		t.Errorf("stddev with clip limit 1.5 = %v, with 8 = %v, want smaller", l, h)
+	}
+}
+
+func TestApplySubRegion(t *testing.T) {
+	img := ramp(image.Rect(0, 0, 40, 10), 60, 180)
+	orig := image.NewNRGBA(img.Rect)
+	copy(orig.Pix, img.Pix)
+	r := image.Rect(10, 2, 30, 8)
+	for mode := range _maxMode {
+		out, err := Apply(internal.DefaultConfig, img, r, Op{Mode: mode}, core.ToNewImage())
+		if err != nil {
+			t.Fatalf("Apply(%d) failed: %v", mode, err)
+		}
+		if out.Bounds() != r {
+			t.Errorf("Apply(%d) bounds = %v, want %v", mode, out.Bounds(), r)
+		}
+	}
+	for i, v := range img.Pix {
+		if v != orig.Pix[i] {
+			t.Fatal("source image was modified")
+		}
+	}
+}
+
+func TestApplyIdentityRGBA(t *testing.T) {
+	// A translucent premultiplied color that doesn't survive a round trip through NRGBA. Its
+	// ranges are degenerate, so AutoLevels and Equalize leave it alone.
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for i := 0; i < len(img.Pix); i += 4 {
+		copy(img.Pix[i:], []uint8{7, 131, 20, 138})
+	}
+	orig := bytes.Clone(img.Pix)
+	for _, mode := range []Mode{AutoLevels, Equalize} {
+		for name, output := range map[string]core.Output{"new image": core.ToNewImage(), "dst": core.ToImage(img, image.Point{})} {
+			out, err := Apply(internal.DefaultConfig, img, img.Rect, Op{Mode: mode}, output)
+			if err != nil {
+				t.Fatalf("Apply(%d) failed: %v", mode, err)
+			}
+			if !bytes.Equal(out.(*image.RGBA).Pix, orig) {
+				t.Errorf("Apply(%d) to %s changed the image", mode, name)
+			}
+		}
+	}
+}
+
+func TestInvalidOp(t *testing.T) {
+	img := ramp(image.Rect(0, 0, 4, 4), 0, 255)
+	for _, o := range []Op{{Mode: -1}, {Mode: _maxMode}, {Clip: 0.5}, {Clip: -0.1}, {Mode: CLAHE, Tiles: -1}, {Mode: CLAHE, ClipLimit: 0.5}} {
+		if _, err := Apply(internal.DefaultConfig, img, img.Rect, o, nil); err == nil {
+			t.Errorf("expected error for %+v", o)
+		}
+	}
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -13,6 +13,22 @@ func sqrt(a uint32) uint32 {
 	return internal.Sqrt(a)
 }
 
+func isqrt(x uint32) uint32 {
+	return internal.Isqrt(x)
+}
+
 func div255(x uint32) uint32 {
 	return internal.Div255(x)
 }
+
+func softLightD(x uint32) uint32 {
+	return internal.SoftLightD(x)
+}
+
+func softLightIllusions(s, d uint32) uint32 {
+	return internal.SoftLightIllusions(s, d)
+}
+
+func lum(r, g, b uint32) uint32 {
+	return internal.Lum(r, g, b)
+}
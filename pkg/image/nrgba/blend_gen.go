@@ -12,9 +12,9 @@ import (
 	"github.com/blazeroni/magpie/pkg/internal"
 )
 
-// BlendColorBurn performs a "ColorBurn" blend on NRGBA images.
-// Logic: Cr = 1 - (1 - Cd) / Cs
-func BlendColorBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendAdditiveSubtractive performs a "AdditiveSubtractive" blend on NRGBA images.
+// Logic: Cr = abs(sqrt(Cd) - sqrt(Cs))
+func BlendAdditiveSubtractive(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -44,20 +44,17 @@ func BlendColorBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.N
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			if sR+dR <= 255 {
-				oR = 0
-			} else {
-				oR = 255 - ((255-dR)*255+sR/2)/sR
+			oR = sqrt(dR) - sqrt(sR)
+			if oR > 255 {
+				oR = sqrt(sR) - sqrt(dR)
 			}
-			if sG+dG <= 255 {
-				oG = 0
-			} else {
-				oG = 255 - ((255-dG)*255+sG/2)/sG
+			oG = sqrt(dG) - sqrt(sG)
+			if oG > 255 {
+				oG = sqrt(sG) - sqrt(dG)
 			}
-			if sB+dB <= 255 {
-				oB = 0
-			} else {
-				oB = 255 - ((255-dB)*255+sB/2)/sB
+			oB = sqrt(dB) - sqrt(sB)
+			if oB > 255 {
+				oB = sqrt(sB) - sqrt(dB)
 			}
 			// endregion BLEND-SPECIFIC LOGIC
 
@@ -112,9 +109,9 @@ func BlendColorBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.N
 	})
 }
 
-// BlendColorDodge performs a "ColorDodge" blend on NRGBA images.
-// Logic: Cr = Cd / (1 - Cs)
-func BlendColorDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendAverage performs a "Average" blend on NRGBA images.
+// Logic: Cr = (Cs + Cd) / 2
+func BlendAverage(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -144,21 +141,9 @@ func BlendColorDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			if sR == 255 {
-				oR = 255
-			} else {
-				oR = min((dR*255)/(255-sR), 255)
-			}
-			if sG == 255 {
-				oG = 255
-			} else {
-				oG = min((dG*255)/(255-sG), 255)
-			}
-			if sB == 255 {
-				oB = 255
-			} else {
-				oB = min((dB*255)/(255-sB), 255)
-			}
+			oR = (sR + dR + 1) / 2
+			oG = (sG + dG + 1) / 2
+			oB = (sB + dB + 1) / 2
 			// endregion BLEND-SPECIFIC LOGIC
 
 			if (sA & dA) == 255 {
@@ -212,9 +197,9 @@ func BlendColorDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 	})
 }
 
-// BlendDarken performs a "Darken" blend on NRGBA images.
-// Logic: Cr = min(Cs, Cd)
-func BlendDarken(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendColorBurn performs a "ColorBurn" blend on NRGBA images.
+// Logic: Cr = 1 - (1 - Cd) / Cs
+func BlendColorBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -244,9 +229,21 @@ func BlendDarken(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGB
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			oR = min(sR, dR)
-			oG = min(sG, dG)
-			oB = min(sB, dB)
+			if sR+dR <= 255 {
+				oR = 0
+			} else {
+				oR = 255 - ((255-dR)*255+sR/2)/sR
+			}
+			if sG+dG <= 255 {
+				oG = 0
+			} else {
+				oG = 255 - ((255-dG)*255+sG/2)/sG
+			}
+			if sB+dB <= 255 {
+				oB = 0
+			} else {
+				oB = 255 - ((255-dB)*255+sB/2)/sB
+			}
 			// endregion BLEND-SPECIFIC LOGIC
 
 			if (sA & dA) == 255 {
@@ -300,9 +297,9 @@ func BlendDarken(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGB
 	})
 }
 
-// BlendDifference performs a "Difference" blend on NRGBA images.
-// Logic: Cr = abs(Cs - Cd)
-func BlendDifference(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendColorDodge performs a "ColorDodge" blend on NRGBA images.
+// Logic: Cr = Cd / (1 - Cs)
+func BlendColorDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -332,9 +329,21 @@ func BlendDifference(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			oR = min(sR-dR, dR-sR)
-			oG = min(sG-dG, dG-sG)
-			oB = min(sB-dB, dB-sB)
+			if sR == 255 {
+				oR = 255
+			} else {
+				oR = min((dR*255)/(255-sR), 255)
+			}
+			if sG == 255 {
+				oG = 255
+			} else {
+				oG = min((dG*255)/(255-sG), 255)
+			}
+			if sB == 255 {
+				oB = 255
+			} else {
+				oB = min((dB*255)/(255-sB), 255)
+			}
 			// endregion BLEND-SPECIFIC LOGIC
 
 			if (sA & dA) == 255 {
@@ -388,9 +397,9 @@ func BlendDifference(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 	})
 }
 
-// BlendDivide performs a "Divide" blend on NRGBA images.
-// Logic: Cr = Cd / Cs
-func BlendDivide(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendDarken performs a "Darken" blend on NRGBA images.
+// Logic: Cr = min(Cs, Cd)
+func BlendDarken(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -420,21 +429,9 @@ func BlendDivide(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGB
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			if sR == 0 {
-				oR = 255
-			} else {
-				oR = min((dR*255+sR/2)/sR, 255)
-			}
-			if sG == 0 {
-				oG = 255
-			} else {
-				oG = min((dG*255+sG/2)/sG, 255)
-			}
-			if sB == 0 {
-				oB = 255
-			} else {
-				oB = min((dB*255+sB/2)/sB, 255)
-			}
+			oR = min(sR, dR)
+			oG = min(sG, dG)
+			oB = min(sB, dB)
 			// endregion BLEND-SPECIFIC LOGIC
 
 			if (sA & dA) == 255 {
@@ -488,9 +485,9 @@ func BlendDivide(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGB
 	})
 }
 
-// BlendExclusion performs a "Exclusion" blend on NRGBA images.
-// Logic: Cr = Cs + Cd - 2 * Cs * Cd
-func BlendExclusion(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendDarkerColor performs a "DarkerColor" blend on NRGBA images.
+// Logic: if Lum(Cs) < Lum(Cd) { Cr = Cs } else { Cr = Cd }, where Lum(C) = 0.3*R + 0.59*G + 0.11*B
+func BlendDarkerColor(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -520,9 +517,12 @@ func BlendExclusion(pixIter core.PixelIterator, calc core.PixCalculator[*image.N
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			oR = sR + dR - 2*md255(sR, dR)
-			oG = sG + dG - 2*md255(sG, dG)
-			oB = sB + dB - 2*md255(sB, dB)
+			if lum(sR, sG, sB) < lum(dR, dG, dB) {
+				oR, oG, oB = sR, sG, sB
+			} else {
+				oR, oG, oB = dR, dG, dB
+			}
+
 			// endregion BLEND-SPECIFIC LOGIC
 
 			if (sA & dA) == 255 {
@@ -576,9 +576,9 @@ func BlendExclusion(pixIter core.PixelIterator, calc core.PixCalculator[*image.N
 	})
 }
 
-// BlendHardLight performs a "HardLight" blend on NRGBA images.
-// Logic: if Cs < 0.5 { Cr = 2 * Cs * Cd } else { Cr = 1 - 2 * (1 - Cs) * (1 - Cd) }
-func BlendHardLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendDifference performs a "Difference" blend on NRGBA images.
+// Logic: Cr = abs(Cs - Cd)
+func BlendDifference(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -608,21 +608,9 @@ func BlendHardLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.N
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			if sR < 128 {
-				oR = 2 * md255(sR, dR)
-			} else {
-				oR = 255 - 2*md255(255-sR, 255-dR)
-			}
-			if sG < 128 {
-				oG = 2 * md255(sG, dG)
-			} else {
-				oG = 255 - 2*md255(255-sG, 255-dG)
-			}
-			if sB < 128 {
-				oB = 2 * md255(sB, dB)
-			} else {
-				oB = 255 - 2*md255(255-sB, 255-dB)
-			}
+			oR = min(sR-dR, dR-sR)
+			oG = min(sG-dG, dG-sG)
+			oB = min(sB-dB, dB-sB)
 			// endregion BLEND-SPECIFIC LOGIC
 
 			if (sA & dA) == 255 {
@@ -676,9 +664,9 @@ func BlendHardLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.N
 	})
 }
 
-// BlendHardMix performs a "HardMix" blend on NRGBA images.
-// Logic: if Cs + Cd < 1 { Cr = 0 } else { Cr = 1 }
-func BlendHardMix(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendDivide performs a "Divide" blend on NRGBA images.
+// Logic: Cr = Cd / Cs
+func BlendDivide(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -708,20 +696,20 @@ func BlendHardMix(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRG
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			if sR+dR < 255 {
-				oR = 0
-			} else {
+			if sR == 0 {
 				oR = 255
-			}
-			if sG+dG < 255 {
-				oG = 0
 			} else {
-				oG = 255
+				oR = min((dR*255+sR/2)/sR, 255)
 			}
-			if sB+dB < 255 {
-				oB = 0
+			if sG == 0 {
+				oG = 255
 			} else {
+				oG = min((dG*255+sG/2)/sG, 255)
+			}
+			if sB == 0 {
 				oB = 255
+			} else {
+				oB = min((dB*255+sB/2)/sB, 255)
 			}
 			// endregion BLEND-SPECIFIC LOGIC
 
@@ -776,9 +764,9 @@ func BlendHardMix(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRG
 	})
 }
 
-// BlendLighten performs a "Lighten" blend on NRGBA images.
-// Logic: Cr = max(Cs, Cd)
-func BlendLighten(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendExclusion performs a "Exclusion" blend on NRGBA images.
+// Logic: Cr = Cs + Cd - 2 * Cs * Cd
+func BlendExclusion(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -808,9 +796,9 @@ func BlendLighten(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRG
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			oR = max(sR, dR)
-			oG = max(sG, dG)
-			oB = max(sB, dB)
+			oR = sR + dR - 2*md255(sR, dR)
+			oG = sG + dG - 2*md255(sG, dG)
+			oB = sB + dB - 2*md255(sB, dB)
 			// endregion BLEND-SPECIFIC LOGIC
 
 			if (sA & dA) == 255 {
@@ -864,9 +852,9 @@ func BlendLighten(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRG
 	})
 }
 
-// BlendLinearBurn performs a "LinearBurn" blend on NRGBA images.
-// Logic: Cr = Cs + Cd - 1
-func BlendLinearBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendFreeze performs a "Freeze" blend on NRGBA images.
+// Logic: Cr = 1 - (1 - Cd)^2 / Cs
+func BlendFreeze(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -896,17 +884,20 @@ func BlendLinearBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			oR = sR + dR - 255
-			if oR > 255 {
+			if sR == 0 {
 				oR = 0
+			} else {
+				oR = 255 - min(((255-dR)*(255-dR)+sR/2)/sR, 255)
 			}
-			oG = sG + dG - 255
-			if oG > 255 {
+			if sG == 0 {
 				oG = 0
+			} else {
+				oG = 255 - min(((255-dG)*(255-dG)+sG/2)/sG, 255)
 			}
-			oB = sB + dB - 255
-			if oB > 255 {
+			if sB == 0 {
 				oB = 0
+			} else {
+				oB = 255 - min(((255-dB)*(255-dB)+sB/2)/sB, 255)
 			}
 			// endregion BLEND-SPECIFIC LOGIC
 
@@ -961,9 +952,9 @@ func BlendLinearBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 	})
 }
 
-// BlendLinearDodge performs a "LinearDodge" blend on NRGBA images.
-// Logic: Cr = Cs + Cd
-func BlendLinearDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendGeometricMean performs a "GeometricMean" blend on NRGBA images.
+// Logic: Cr = sqrt(Cs * Cd)
+func BlendGeometricMean(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -993,9 +984,9 @@ func BlendLinearDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			oR = min(sR+dR, 255)
-			oG = min(sG+dG, 255)
-			oB = min(sB+dB, 255)
+			oR = isqrt(sR * dR)
+			oG = isqrt(sG * dG)
+			oB = isqrt(sB * dB)
 			// endregion BLEND-SPECIFIC LOGIC
 
 			if (sA & dA) == 255 {
@@ -1049,9 +1040,9 @@ func BlendLinearDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image
 	})
 }
 
-// BlendLinearLight performs a "LinearLight" blend on NRGBA images.
-// Logic: Cr = Cd + 2*Cs - 1
-func BlendLinearLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendGlow performs a "Glow" blend on NRGBA images.
+// Logic: Cr = Cs^2 / (1 - Cd)
+func BlendGlow(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1081,23 +1072,20 @@ func BlendLinearLight(pixIter core.PixelIterator, calc core.PixCalculator[*image
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			oR = dR + 2*sR - 255
-			if oR > 510 {
-				oR = 0
-			} else if oR > 255 {
+			if dR == 255 {
 				oR = 255
+			} else {
+				oR = min((sR*sR+(255-dR)/2)/(255-dR), 255)
 			}
-			oG = dG + 2*sG - 255
-			if oG > 510 {
-				oG = 0
-			} else if oG > 255 {
+			if dG == 255 {
 				oG = 255
+			} else {
+				oG = min((sG*sG+(255-dG)/2)/(255-dG), 255)
 			}
-			oB = dB + 2*sB - 255
-			if oB > 510 {
-				oB = 0
-			} else if oB > 255 {
+			if dB == 255 {
 				oB = 255
+			} else {
+				oB = min((sB*sB+(255-dB)/2)/(255-dB), 255)
 			}
 			// endregion BLEND-SPECIFIC LOGIC
 
@@ -1152,9 +1140,9 @@ func BlendLinearLight(pixIter core.PixelIterator, calc core.PixCalculator[*image
 	})
 }
 
-// BlendMultiply performs a "Multiply" blend on NRGBA images.
-// Logic: Cr = Cs * Cd
-func BlendMultiply(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendGrainExtract performs a "GrainExtract" blend on NRGBA images.
+// Logic: Cr = Cd - Cs + 0.5
+func BlendGrainExtract(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1184,9 +1172,9 @@ func BlendMultiply(pixIter core.PixelIterator, calc core.PixCalculator[*image.NR
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			oR = md255(sR, dR)
-			oG = md255(sG, dG)
-			oB = md255(sB, dB)
+			oR = uint32(max(min(int(dR)-int(sR)+128, 255), 0))
+			oG = uint32(max(min(int(dG)-int(sG)+128, 255), 0))
+			oB = uint32(max(min(int(dB)-int(sB)+128, 255), 0))
 			// endregion BLEND-SPECIFIC LOGIC
 
 			if (sA & dA) == 255 {
@@ -1240,9 +1228,9 @@ func BlendMultiply(pixIter core.PixelIterator, calc core.PixCalculator[*image.NR
 	})
 }
 
-// BlendOverlay performs a "Overlay" blend on NRGBA images.
-// Logic: if Cd < 0.5 { Cr = 2 * Cs * Cd } else { Cr = 1 - 2 * (1 - Cs) * (1 - Cd) }
-func BlendOverlay(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendGrainMerge performs a "GrainMerge" blend on NRGBA images.
+// Logic: Cr = Cd + Cs - 0.5
+func BlendGrainMerge(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1272,21 +1260,9 @@ func BlendOverlay(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRG
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			if dR < 128 {
-				oR = 2 * md255(sR, dR)
-			} else {
-				oR = 255 - 2*md255(255-sR, 255-dR)
-			}
-			if dG < 128 {
-				oG = 2 * md255(sG, dG)
-			} else {
-				oG = 255 - 2*md255(255-sG, 255-dG)
-			}
-			if dB < 128 {
-				oB = 2 * md255(sB, dB)
-			} else {
-				oB = 255 - 2*md255(255-sB, 255-dB)
-			}
+			oR = uint32(max(min(int(dR)+int(sR)-128, 255), 0))
+			oG = uint32(max(min(int(dG)+int(sG)-128, 255), 0))
+			oB = uint32(max(min(int(dB)+int(sB)-128, 255), 0))
 			// endregion BLEND-SPECIFIC LOGIC
 
 			if (sA & dA) == 255 {
@@ -1340,9 +1316,9 @@ func BlendOverlay(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRG
 	})
 }
 
-// BlendPinLight performs a "PinLight" blend on NRGBA images.
-// Logic: if Cs < 0.5 { Cr = min(Cd, 2 * Cs) } else { Cr = max(Cd, 2 * Cs - 1) }
-func BlendPinLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendHardLight performs a "HardLight" blend on NRGBA images.
+// Logic: if Cs < 0.5 { Cr = 2 * Cs * Cd } else { Cr = 1 - 2 * (1 - Cs) * (1 - Cd) }
+func BlendHardLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1373,17 +1349,1336 @@ func BlendPinLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.NR
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
 			if sR < 128 {
-				oR = min(dR, 2*sR)
+				oR = 2 * md255(sR, dR)
 			} else {
-				oR = max(dR, 2*sR-255)
+				oR = 255 - 2*md255(255-sR, 255-dR)
 			}
 			if sG < 128 {
-				oG = min(dG, 2*sG)
+				oG = 2 * md255(sG, dG)
 			} else {
-				oG = max(dG, 2*sG-255)
+				oG = 255 - 2*md255(255-sG, 255-dG)
 			}
 			if sB < 128 {
-				oB = min(dB, 2*sB)
+				oB = 2 * md255(sB, dB)
+			} else {
+				oB = 255 - 2*md255(255-sB, 255-dB)
+			}
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendHardMix performs a "HardMix" blend on NRGBA images.
+// Logic: if Cs + Cd < 1 { Cr = 0 } else { Cr = 1 }
+func BlendHardMix(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			if sR+dR < 255 {
+				oR = 0
+			} else {
+				oR = 255
+			}
+			if sG+dG < 255 {
+				oG = 0
+			} else {
+				oG = 255
+			}
+			if sB+dB < 255 {
+				oB = 0
+			} else {
+				oB = 255
+			}
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendHeat performs a "Heat" blend on NRGBA images.
+// Logic: Cr = 1 - (1 - Cs)^2 / Cd
+func BlendHeat(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			if dR == 0 {
+				oR = 0
+			} else {
+				oR = 255 - min(((255-sR)*(255-sR)+dR/2)/dR, 255)
+			}
+			if dG == 0 {
+				oG = 0
+			} else {
+				oG = 255 - min(((255-sG)*(255-sG)+dG/2)/dG, 255)
+			}
+			if dB == 0 {
+				oB = 0
+			} else {
+				oB = 255 - min(((255-sB)*(255-sB)+dB/2)/dB, 255)
+			}
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendLUT performs a "LUT" blend on NRGBA images.
+// Logic: Cr = lut[Cs][Cd], a blend function precomputed for every pair of 8-bit values
+func BlendLUT(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing, lut *[256][256]uint8) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			oR = uint32(lut[sR][dR])
+			oG = uint32(lut[sG][dG])
+			oB = uint32(lut[sB][dB])
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendLighten performs a "Lighten" blend on NRGBA images.
+// Logic: Cr = max(Cs, Cd)
+func BlendLighten(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			oR = max(sR, dR)
+			oG = max(sG, dG)
+			oB = max(sB, dB)
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendLighterColor performs a "LighterColor" blend on NRGBA images.
+// Logic: if Lum(Cs) > Lum(Cd) { Cr = Cs } else { Cr = Cd }, where Lum(C) = 0.3*R + 0.59*G + 0.11*B
+func BlendLighterColor(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			if lum(sR, sG, sB) > lum(dR, dG, dB) {
+				oR, oG, oB = sR, sG, sB
+			} else {
+				oR, oG, oB = dR, dG, dB
+			}
+
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendLinearBurn performs a "LinearBurn" blend on NRGBA images.
+// Logic: Cr = Cs + Cd - 1
+func BlendLinearBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			oR = sR + dR - 255
+			if oR > 255 {
+				oR = 0
+			}
+			oG = sG + dG - 255
+			if oG > 255 {
+				oG = 0
+			}
+			oB = sB + dB - 255
+			if oB > 255 {
+				oB = 0
+			}
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendLinearDodge performs a "LinearDodge" blend on NRGBA images.
+// Logic: Cr = Cs + Cd
+func BlendLinearDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			oR = min(sR+dR, 255)
+			oG = min(sG+dG, 255)
+			oB = min(sB+dB, 255)
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendLinearLight performs a "LinearLight" blend on NRGBA images.
+// Logic: Cr = Cd + 2*Cs - 1
+func BlendLinearLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			oR = dR + 2*sR - 255
+			if oR > 510 {
+				oR = 0
+			} else if oR > 255 {
+				oR = 255
+			}
+			oG = dG + 2*sG - 255
+			if oG > 510 {
+				oG = 0
+			} else if oG > 255 {
+				oG = 255
+			}
+			oB = dB + 2*sB - 255
+			if oB > 510 {
+				oB = 0
+			} else if oB > 255 {
+				oB = 255
+			}
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendMultiply performs a "Multiply" blend on NRGBA images.
+// Logic: Cr = Cs * Cd
+func BlendMultiply(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			oR = md255(sR, dR)
+			oG = md255(sG, dG)
+			oB = md255(sB, dB)
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendNegation performs a "Negation" blend on NRGBA images.
+// Logic: Cr = 1 - abs(1 - Cs - Cd)
+func BlendNegation(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			if sR+dR > 255 {
+				oR = 510 - sR - dR
+			} else {
+				oR = sR + dR
+			}
+			if sG+dG > 255 {
+				oG = 510 - sG - dG
+			} else {
+				oG = sG + dG
+			}
+			if sB+dB > 255 {
+				oB = 510 - sB - dB
+			} else {
+				oB = sB + dB
+			}
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendNormal performs a "Normal" blend on NRGBA images.
+// Logic: Cr = Cs
+func BlendNormal(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			oR = sR
+			oG = sG
+			oB = sB
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendOverlay performs a "Overlay" blend on NRGBA images.
+// Logic: if Cd < 0.5 { Cr = 2 * Cs * Cd } else { Cr = 1 - 2 * (1 - Cs) * (1 - Cd) }
+func BlendOverlay(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			if dR < 128 {
+				oR = 2 * md255(sR, dR)
+			} else {
+				oR = 255 - 2*md255(255-sR, 255-dR)
+			}
+			if dG < 128 {
+				oG = 2 * md255(sG, dG)
+			} else {
+				oG = 255 - 2*md255(255-sG, 255-dG)
+			}
+			if dB < 128 {
+				oB = 2 * md255(sB, dB)
+			} else {
+				oB = 255 - 2*md255(255-sB, 255-dB)
+			}
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendPhoenix performs a "Phoenix" blend on NRGBA images.
+// Logic: Cr = min(Cs, Cd) - max(Cs, Cd) + 1
+func BlendPhoenix(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			oR = min(sR, dR) + 255 - max(sR, dR)
+			oG = min(sG, dG) + 255 - max(sG, dG)
+			oB = min(sB, dB) + 255 - max(sB, dB)
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendPinLight performs a "PinLight" blend on NRGBA images.
+// Logic: if Cs < 0.5 { Cr = min(Cd, 2 * Cs) } else { Cr = max(Cd, 2 * Cs - 1) }
+func BlendPinLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			if sR < 128 {
+				oR = min(dR, 2*sR)
+			} else {
+				oR = max(dR, 2*sR-255)
+			}
+			if sG < 128 {
+				oG = min(dG, 2*sG)
+			} else {
+				oG = max(dG, 2*sG-255)
+			}
+			if sB < 128 {
+				oB = min(dB, 2*sB)
 			} else {
 				oB = max(dB, 2*sB-255)
 			}
@@ -1440,9 +2735,297 @@ func BlendPinLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.NR
 	})
 }
 
-// BlendScreen performs a "Screen" blend on NRGBA images.
-// Logic: Cr = 1 - (1 - Cs) * (1 - Cd)
-func BlendScreen(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendReflect performs a "Reflect" blend on NRGBA images.
+// Logic: Cr = Cd^2 / (1 - Cs)
+func BlendReflect(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			if sR == 255 {
+				oR = 255
+			} else {
+				oR = min((dR*dR+(255-sR)/2)/(255-sR), 255)
+			}
+			if sG == 255 {
+				oG = 255
+			} else {
+				oG = min((dG*dG+(255-sG)/2)/(255-sG), 255)
+			}
+			if sB == 255 {
+				oB = 255
+			} else {
+				oB = min((dB*dB+(255-sB)/2)/(255-sB), 255)
+			}
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendScreen performs a "Screen" blend on NRGBA images.
+// Logic: Cr = 1 - (1 - Cs) * (1 - Cd)
+func BlendScreen(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			oR = 255 - md255(255-sR, 255-dR)
+			oG = 255 - md255(255-sG, 255-dG)
+			oB = 255 - md255(255-sB, 255-dB)
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendSoftLight performs a "SoftLight" blend on NRGBA images.
+// Logic: if Cs < 0.5 { Cr = Cd - (1 - 2*Cs) * Cd * (1 - Cd) } else { Cr = Cd + (2*Cs - 1) * (sqrt(Cd) - Cd) }
+func BlendSoftLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			if sR < 128 {
+				oR = dR - div255(div255((255-2*sR)*dR*(255-dR)))
+			} else {
+				oR = dR + md255(2*sR-255, sqrt(dR)-dR)
+			}
+			if sG < 128 {
+				oG = dG - div255(div255((255-2*sG)*dG*(255-dG)))
+			} else {
+				oG = dG + md255(2*sG-255, sqrt(dG)-dG)
+			}
+			if sB < 128 {
+				oB = dB - div255(div255((255-2*sB)*dB*(255-dB)))
+			} else {
+				oB = dB + md255(2*sB-255, sqrt(dB)-dB)
+			}
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendSoftLightIllusions performs a "SoftLightIllusions" blend on NRGBA images.
+// Logic: Cr = Cd^(2^(2 * (0.5 - Cs)))
+func BlendSoftLightIllusions(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1472,9 +3055,9 @@ func BlendScreen(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGB
 
 			// Calculate the pure blend color
 			// region BLEND-SPECIFIC LOGIC
-			oR = 255 - md255(255-sR, 255-dR)
-			oG = 255 - md255(255-sG, 255-dG)
-			oB = 255 - md255(255-sB, 255-dB)
+			oR = softLightIllusions(sR, dR)
+			oG = softLightIllusions(sG, dG)
+			oB = softLightIllusions(sB, dB)
 			// endregion BLEND-SPECIFIC LOGIC
 
 			if (sA & dA) == 255 {
@@ -1528,9 +3111,97 @@ func BlendScreen(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGB
 	})
 }
 
-// BlendSoftLight performs a "SoftLight" blend on NRGBA images.
-// Logic: if Cs < 0.5 { Cr = Cd - (1 - 2*Cs) * Cd * (1 - Cd) } else { Cr = Cd + (2*Cs - 1) * (sqrt(Cd) - Cd) }
-func BlendSoftLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+// BlendSoftLightPegtop performs a "SoftLightPegtop" blend on NRGBA images.
+// Logic: Cr = (1 - 2*Cs) * Cd^2 + 2 * Cs * Cd
+func BlendSoftLightPegtop(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			oR = (dR*dR*(255-2*sR) + 2*sR*dR*255 + 32512) / 65025
+			oG = (dG*dG*(255-2*sG) + 2*sG*dG*255 + 32512) / 65025
+			oB = (dB*dB*(255-2*sB) + 2*sB*dB*255 + 32512) / 65025
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
+
+// BlendSoftLightW3C performs a "SoftLightW3C" blend on NRGBA images.
+// Logic: if Cs <= 0.5 { Cr = Cd - (1 - 2*Cs) * Cd * (1 - Cd) } else { Cr = Cd + (2*Cs - 1) * (D(Cd) - Cd) }, where D(x) = ((16*x - 12)*x + 4)*x if x <= 0.25 else sqrt(x)
+func BlendSoftLightW3C(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1563,17 +3234,17 @@ func BlendSoftLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.N
 			if sR < 128 {
 				oR = dR - div255(div255((255-2*sR)*dR*(255-dR)))
 			} else {
-				oR = dR + md255(2*sR-255, sqrt(dR)-dR)
+				oR = dR + md255(2*sR-255, softLightD(dR)-dR)
 			}
 			if sG < 128 {
 				oG = dG - div255(div255((255-2*sG)*dG*(255-dG)))
 			} else {
-				oG = dG + md255(2*sG-255, sqrt(dG)-dG)
+				oG = dG + md255(2*sG-255, softLightD(dG)-dG)
 			}
 			if sB < 128 {
 				oB = dB - div255(div255((255-2*sB)*dB*(255-dB)))
 			} else {
-				oB = dB + md255(2*sB-255, sqrt(dB)-dB)
+				oB = dB + md255(2*sB-255, softLightD(dB)-dB)
 			}
 			// endregion BLEND-SPECIFIC LOGIC
 
@@ -1824,3 +3495,118 @@ func BlendVividLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 		}
 	})
 }
+
+// BlendVividLightGIMP performs a "VividLightGIMP" blend on NRGBA images.
+// Logic: if Cs <= 0.5 { Cr = 1 - (1 - Cd) / (2 * Cs) } else { Cr = Cd / (2 * (1 - Cs)) }, where x / 0 = 0
+func BlendVividLightGIMP(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA], compositing internal.BlendCompositing) *image.NRGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 { // Source is transparent
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 { // Destination is transparent
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// Both src and dst have some opacity.
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+			var oR, oG, oB uint32
+
+			// Calculate the pure blend color
+			// region BLEND-SPECIFIC LOGIC
+			switch {
+			case sR == 0:
+				oR = 255
+			case sR == 255:
+				oR = 0
+			case sR < 128:
+				oR = 255 - min(((255-dR)*255+sR)/(2*sR), 255)
+			default:
+				oR = min(((dR*255)+(255-sR))/(510-2*sR), 255)
+			}
+			switch {
+			case sG == 0:
+				oG = 255
+			case sG == 255:
+				oG = 0
+			case sG < 128:
+				oG = 255 - min(((255-dG)*255+sG)/(2*sG), 255)
+			default:
+				oG = min(((dG*255)+(255-sG))/(510-2*sG), 255)
+			}
+			switch {
+			case sB == 0:
+				oB = 255
+			case sB == 255:
+				oB = 0
+			case sB < 128:
+				oB = 255 - min(((255-dB)*255+sB)/(2*sB), 255)
+			default:
+				oB = min(((dB*255)+(255-sB))/(510-2*sB), 255)
+			}
+			// endregion BLEND-SPECIFIC LOGIC
+
+			if (sA & dA) == 255 {
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), 255
+				continue
+			}
+
+			var compR, compG, compB, compA, outA uint32
+			switch compositing {
+			case internal.CompositeAll:
+				// Case 1: Show both src and dst
+				invSA, invDA := 255-sA, 255-dA
+				term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+				compR = md255(term1, sR) + md255(term2, dR) + md255(term3, oR)
+				compG = md255(term1, sG) + md255(term2, dG) + md255(term3, oG)
+				compB = md255(term1, sB) + md255(term2, dB) + md255(term3, oB)
+				compA = sA + term2
+				outA = compA
+			case internal.CompositeBlendAndSrc:
+				// Case 2: Show src only
+				invDA := 255 - dA
+				term1 := md255(sA, invDA)
+				compR = md255(term1, sR) + md255(dA, oR)
+				compG = md255(term1, sG) + md255(dA, oG)
+				compB = md255(term1, sB) + md255(dA, oB)
+				compA = dA + term1
+				outA = sA
+			case internal.CompositeBlendAndDst:
+				// Case 3: Show dst only
+				invSA := 255 - sA
+				term2 := md255(dA, invSA)
+				compR = md255(term2, dR) + md255(sA, oR)
+				compG = md255(term2, dG) + md255(sA, oG)
+				compB = md255(term2, dB) + md255(sA, oB)
+				compA = sA + term2
+				outA = dA
+			case internal.CompositeBlendOnly:
+				// Case 4: Show neither (intersection only)
+				oA := md255(sA, dA)
+				out[i], out[i+1], out[i+2], out[i+3] = uint8(oR), uint8(oG), uint8(oB), uint8(oA)
+				continue
+			}
+
+			round := compA / 2
+			out[i] = uint8((compR*255 + round) / compA)
+			out[i+1] = uint8((compG*255 + round) / compA)
+			out[i+2] = uint8((compB*255 + round) / compA)
+			out[i+3] = uint8(outA)
+		}
+	})
+}
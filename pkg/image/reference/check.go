@@ -0,0 +1,289 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package reference
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+// Samples selects the inputs of a differential check. Every pair of Colors is combined with
+// every pair of Alphas, so the check covers len(Colors)² × len(Alphas)² channel values per
+// color model. Using all 256 values for both is exhaustive.
+type Samples struct {
+	Colors []uint8
+	Alphas []uint8
+}
+
+// DefaultSamples covers every fifth channel value and the values around the kernels' branch
+// points, combined with the extreme and midpoint alphas.
+var DefaultSamples = Samples{
+	Colors: func() []uint8 {
+		colors := []uint8{1, 2, 127, 128, 253, 254}
+		for v := 0; v <= 255; v += 5 {
+			colors = append(colors, uint8(v))
+		}
+		return colors
+	}(),
+	Alphas: []uint8{0, 1, 2, 16, 64, 127, 128, 192, 253, 254, 255},
+}
+
+// Case is a single compared pixel.
+type Case struct {
+	Dst, Src, Got color.Color
+	Want          Color
+}
+
+// Report summarizes the error of a kernel against the reference, in 8-bit units.
+type Report struct {
+	// Samples is the number of compared channel values.
+	Samples int
+	// Max and Mean are the largest and average absolute error.
+	Max, Mean float64
+	// Exceeding is the number of channel values that are off by more than 1.
+	Exceeding int
+	// Divergent is the number of channel values left out of the other fields because their
+	// inputs are ones where the kernel knowingly departs from the specification; see Divergent.
+	Divergent int
+	// Worst is the pixel with the largest error.
+	Worst Case
+}
+
+// Accuracy holds the reports of a mode for both color models.
+type Accuracy struct {
+	NRGBA, RGBA Report
+}
+
+// CheckBlend compares the NRGBA and RGBA kernels of o against Blend.
+//
+// Errors are measured on premultiplied channels for both color models, so they reflect the
+// visible difference of a pixel; the color of a nearly transparent NRGBA result may be off by
+// more. RGBA kernels un-premultiply their inputs to 8-bit colors, which is off by up to
+// UnpremultiplyError, so their results are compared with the closest of the reference results
+// for inputs within that error. Steep kernels, such as divisions, would otherwise be charged
+// for the rounding of their inputs rather than for their own.
+func CheckBlend(cfg core.Config, o op.BlendOp, s Samples) (Accuracy, error) {
+	if !o.IsValid() {
+		return Accuracy{}, errors.New("invalid blend operation")
+	}
+	return check(cfg, o, s, func(dst, src Color) Color {
+		return Blend(o.Mode, o.Compositing, dst, src)
+	}, func(dst, src float64) bool {
+		return Divergent(o.Mode, dst, src)
+	}, UnpremultiplyError), nil
+}
+
+// Divergent reports whether the kernels of mode knowingly depart from the specification for the
+// non-premultiplied destination and source channel values dst and src:
+//
+//   - ColorBurn returns 0 rather than 1 for Cs = 0 on a white backdrop, and ColorDodge 1 rather
+//     than 0 for Cs = 1 on a black backdrop. VividLight inherits both at the extremes of Cs.
+//   - HardMix flips at Cs + Cb = 1, so the rounding of un-premultiplied colors close to it can
+//     pick the other result.
+func Divergent(mode op.BlendMode, dst, src float64) bool {
+	switch mode {
+	case op.ColorBurn:
+		return src == 0 && dst == 1
+	case op.ColorDodge:
+		return src == 1 && dst == 0
+	case op.VividLight:
+		return src == 0 && dst == 1 || src == 1 && dst == 0
+	case op.HardMix:
+		return math.Abs(src+dst-1) < 1.0/255
+	}
+	return false
+}
+
+// CheckComposite compares the NRGBA and RGBA kernels of o against Composite. Errors are
+// measured as in CheckBlend, except that composite operators work on premultiplied colors, so
+// RGBA results are compared with the reference for the exact inputs.
+func CheckComposite(cfg core.Config, o op.CompositeOp, s Samples) (Accuracy, error) {
+	if !o.IsValid() {
+		return Accuracy{}, errors.New("invalid composite operation")
+	}
+	return check(cfg, o, s, func(dst, src Color) Color {
+		return Composite(o.Mode, dst, src)
+	}, func(dst, src float64) bool { return false }, 0), nil
+}
+
+// UnpremultiplyError is the largest error of a color channel that an RGBA kernel un-premultiplies,
+// as a fraction of the channel range: the result is rounded to 8 bits.
+const UnpremultiplyError = 0.5 / 255
+
+// check compares the kernels of o against ref. RGBA results may match the reference for inputs
+// whose colors are off by up to inputError.
+func check(cfg core.Config, o core.Op, s Samples, ref func(dst, src Color) Color, divergent func(dst, src float64) bool, inputError float64) Accuracy {
+	dst, src := samples(s)
+	pixIter := cfg.PixelIterator()
+
+	// skip marks the color channels whose inputs are divergent.
+	skip := func(d, s Color) [4]bool {
+		return [4]bool{divergent(d.R, s.R), divergent(d.G, s.G), divergent(d.B, s.B)}
+	}
+
+	out := image.NewNRGBA(dst.Rect)
+	o.ApplyNRGBA(pixIter, core.NewPixCalculatorNRGBA(dst, dst.Rect, src, src.Rect.Min, out, out.Rect.Min))
+	nrgba := compare(pixIter, out.Rect, func(x, y int) sample {
+		d, s, got := dst.NRGBAAt(x, y), src.NRGBAAt(x, y), out.NRGBAAt(x, y)
+		fd, fs := FromNRGBA(d), FromNRGBA(s)
+		want := ref(fd, fs)
+		a := float64(got.A) / 255
+		return sample{
+			Case:      Case{d, s, got, want},
+			got:       [4]float64{float64(got.R) * a, float64(got.G) * a, float64(got.B) * a, float64(got.A)},
+			lo:        premultiplied(want),
+			hi:        premultiplied(want),
+			divergent: skip(fd, fs),
+		}
+	})
+
+	pdst, psrc := premultiply(dst), premultiply(src)
+	pout := image.NewRGBA(dst.Rect)
+	o.ApplyRGBA(pixIter, core.NewPixCalculatorRGBA(pdst, pdst.Rect, psrc, psrc.Rect.Min, pout, pout.Rect.Min))
+	rgba := compare(pixIter, pout.Rect, func(x, y int) sample {
+		d, s, got := pdst.RGBAAt(x, y), psrc.RGBAAt(x, y), pout.RGBAAt(x, y)
+		fd, fs := FromRGBA(d), FromRGBA(s)
+		want := ref(fd, fs)
+		lo, hi := premultiplied(want), premultiplied(want)
+		// Opaque colors are un-premultiplied exactly. Moving all channels of a color the same way
+		// reaches the extremes of the monotonic kernels and of the luminosity of a color.
+		for _, ed := range inputErrors(fd, inputError) {
+			for _, es := range inputErrors(fs, inputError) {
+				w := premultiplied(ref(offset(fd, ed), offset(fs, es)))
+				for i := range w {
+					lo[i], hi[i] = min(lo[i], w[i]), max(hi[i], w[i])
+				}
+			}
+		}
+		return sample{
+			Case:      Case{d, s, got, want},
+			got:       [4]float64{float64(got.R), float64(got.G), float64(got.B), float64(got.A)},
+			lo:        lo,
+			hi:        hi,
+			divergent: skip(fd, fs),
+		}
+	})
+
+	return Accuracy{NRGBA: nrgba, RGBA: rgba}
+}
+
+// samples lays out every combination of s as destination and source images. Each row holds
+// one pair of alphas and each pixel three pairs of colors, one per channel.
+func samples(s Samples) (dst, src *image.NRGBA) {
+	type pair struct{ d, s uint8 }
+	var pairs []pair
+	for _, d := range s.Colors {
+		for _, c := range s.Colors {
+			pairs = append(pairs, pair{d, c})
+		}
+	}
+	w := (len(pairs) + 2) / 3
+	h := len(s.Alphas) * len(s.Alphas)
+	dst = image.NewNRGBA(image.Rect(0, 0, w, h))
+	src = image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		dA, sA := s.Alphas[y/len(s.Alphas)], s.Alphas[y%len(s.Alphas)]
+		for x := range w {
+			i := y*dst.Stride + x*4
+			for c := range 3 {
+				if p := x*3 + c; p < len(pairs) {
+					dst.Pix[i+c], src.Pix[i+c] = pairs[p].d, pairs[p].s
+				}
+			}
+			dst.Pix[i+3], src.Pix[i+3] = dA, sA
+		}
+	}
+	return dst, src
+}
+
+func premultiply(img *image.NRGBA) *image.RGBA {
+	out := image.NewRGBA(img.Rect)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			out.Set(x, y, img.NRGBAAt(x, y))
+		}
+	}
+	return out
+}
+
+// inputErrors returns the extreme errors, up to e, that un-premultiplying c may add to it.
+func inputErrors(c Color, e float64) []float64 {
+	if e == 0 || c.A == 0 || c.A == 1 {
+		return []float64{0}
+	}
+	return []float64{-e, e}
+}
+
+// offset adds e to the color channels of c, within [0, 1].
+func offset(c Color, e float64) Color {
+	return Color{clamp(c.R + e), clamp(c.G + e), clamp(c.B + e), c.A}
+}
+
+// premultiplied returns the premultiplied channels of c in 8-bit units.
+func premultiplied(c Color) [4]float64 {
+	a := c.A * 255
+	return [4]float64{c.R * a, c.G * a, c.B * a, a}
+}
+
+// sample is a compared pixel with the kernel's premultiplied result, the range of premultiplied
+// results that are exact and the channels to count as divergent instead.
+type sample struct {
+	Case
+	got, lo, hi [4]float64
+	divergent   [4]bool
+}
+
+// compare measures the error of every pixel of r, as the distance of the kernel's result to the
+// exact range of the sample that at returns.
+func compare(pixIter core.PixelIterator, r image.Rectangle, at func(x, y int) sample) Report {
+	type rowReport struct {
+		Report
+		sum   float64
+		worst float64
+	}
+	rows := make([]rowReport, r.Dy())
+	core.IterateRows(pixIter, r.Dy(), func(y int) {
+		res := &rows[y]
+		res.worst = -1
+		for x := r.Min.X; x < r.Max.X; x++ {
+			c := at(x, r.Min.Y+y)
+			for i := range c.got {
+				if c.divergent[i] {
+					res.Divergent++
+					continue
+				}
+				e := max(c.lo[i]-c.got[i], c.got[i]-c.hi[i], 0)
+				res.Samples++
+				res.sum += e
+				if e > 1 {
+					res.Exceeding++
+				}
+				if e > res.worst {
+					res.worst, res.Worst = e, c.Case
+				}
+			}
+		}
+	})
+
+	var out Report
+	var sum float64
+	for _, res := range rows {
+		out.Samples += res.Samples
+		out.Exceeding += res.Exceeding
+		out.Divergent += res.Divergent
+		sum += res.sum
+		if res.worst > out.Max {
+			out.Max, out.Worst = res.worst, res.Worst
+		}
+	}
+	if out.Samples > 0 {
+		out.Mean = sum / float64(out.Samples)
+	}
+	return out
+}
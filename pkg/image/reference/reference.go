@@ -0,0 +1,303 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package reference contains float64 implementations of every blend and composite mode,
+// following the W3C Compositing and Blending specification. They are slow and meant as the
+// ground truth for the fixed-point kernels in the nrgba and rgba packages; see CheckBlend and
+// CheckComposite.
+//
+// Modes that are not part of the specification use the formula documented on their kernel,
+// with results clamped to [0, 1]. Dissolve is random and is treated as Normal.
+package reference
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+// Color is a non-premultiplied color with channels in [0, 1].
+type Color struct {
+	R, G, B, A float64
+}
+
+// FromNRGBA converts an 8-bit non-premultiplied color.
+func FromNRGBA(c color.NRGBA) Color {
+	return Color{float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255, float64(c.A) / 255}
+}
+
+// FromRGBA converts an 8-bit premultiplied color, un-premultiplying it without rounding.
+func FromRGBA(c color.RGBA) Color {
+	if c.A == 0 {
+		return Color{}
+	}
+	a := float64(c.A)
+	return Color{float64(c.R) / a, float64(c.G) / a, float64(c.B) / a, a / 255}
+}
+
+// Blend blends src onto dst with the given mode and compositing.
+//
+// With CompositeAll the result is the source-over composite of the blended color, as in the
+// specification. The other compositings keep the blended intersection and optionally the part
+// of one layer outside of it, and take their alpha from that layer:
+//
+//	CompositeBlendAndSrc: Co = (as*(1-ab)*Cs + ab*B) / (as*(1-ab) + ab), ao = as
+//	CompositeBlendAndDst: Co = (ab*(1-as)*Cb + as*B) / (ab*(1-as) + as), ao = ab
+//	CompositeBlendOnly:   Co = B, ao = as*ab
+func Blend(mode op.BlendMode, compositing op.BlendCompositing, dst, src Color) Color {
+	as, ab := src.A, dst.A
+	blended := blendColor(mode, src, dst)
+	channel := func(cs, cb, b float64) float64 {
+		var co, w float64
+		switch compositing {
+		case op.CompositeAll:
+			co = as*(1-ab)*cs + ab*(1-as)*cb + as*ab*b
+			w = as + ab - as*ab
+		case op.CompositeBlendAndSrc:
+			co = as*(1-ab)*cs + ab*b
+			w = as*(1-ab) + ab
+		case op.CompositeBlendAndDst:
+			co = ab*(1-as)*cb + as*b
+			w = ab*(1-as) + as
+		case op.CompositeBlendOnly:
+			return b
+		}
+		if w == 0 {
+			return 0
+		}
+		return co / w
+	}
+
+	var a float64
+	switch compositing {
+	case op.CompositeAll:
+		a = as + ab - as*ab
+	case op.CompositeBlendAndSrc:
+		a = as
+	case op.CompositeBlendAndDst:
+		a = ab
+	case op.CompositeBlendOnly:
+		a = as * ab
+	}
+	if a == 0 {
+		return Color{}
+	}
+	return Color{channel(src.R, dst.R, blended.R), channel(src.G, dst.G, blended.G), channel(src.B, dst.B, blended.B), a}
+}
+
+// blendColor returns the blended color B(Cb, Cs) of mode; its alpha is unused.
+func blendColor(mode op.BlendMode, src, dst Color) Color {
+	switch mode {
+	case op.DarkerColor:
+		if lum(src) < lum(dst) {
+			return src
+		}
+		return dst
+	case op.LighterColor:
+		if lum(src) > lum(dst) {
+			return src
+		}
+		return dst
+	default:
+		return Color{BlendChannel(mode, src.R, dst.R), BlendChannel(mode, src.G, dst.G), BlendChannel(mode, src.B, dst.B), 0}
+	}
+}
+
+// BlendChannel returns the blend function B(Cb, Cs) of mode for a single channel. Modes that
+// are not separable, such as DarkerColor and LighterColor, return Cs.
+func BlendChannel(mode op.BlendMode, cs, cb float64) float64 {
+	switch mode {
+	case op.AdditiveSubtractive:
+		return math.Abs(math.Sqrt(cb) - math.Sqrt(cs))
+	case op.Average:
+		return (cs + cb) / 2
+	case op.ColorBurn:
+		return colorBurn(cs, cb)
+	case op.ColorDodge:
+		return colorDodge(cs, cb)
+	case op.Darken:
+		return math.Min(cs, cb)
+	case op.Difference:
+		return math.Abs(cs - cb)
+	case op.Divide:
+		if cs == 0 {
+			return 1
+		}
+		return math.Min(cb/cs, 1)
+	case op.Exclusion:
+		return cs + cb - 2*cs*cb
+	case op.Freeze:
+		if cs == 0 {
+			return 0
+		}
+		return clamp(1 - (1-cb)*(1-cb)/cs)
+	case op.GeometricMean:
+		return math.Sqrt(cs * cb)
+	case op.Glow:
+		return reflect(cb, cs)
+	case op.GrainExtract:
+		return clamp(cb - cs + 0.5)
+	case op.GrainMerge:
+		return clamp(cb + cs - 0.5)
+	case op.HardLight:
+		return hardLight(cs, cb)
+	case op.HardMix:
+		if cs+cb < 1 {
+			return 0
+		}
+		return 1
+	case op.Heat:
+		if cb == 0 {
+			return 0
+		}
+		return clamp(1 - (1-cs)*(1-cs)/cb)
+	case op.Lighten:
+		return math.Max(cs, cb)
+	case op.LinearBurn:
+		return math.Max(cs+cb-1, 0)
+	case op.LinearDodge:
+		return math.Min(cs+cb, 1)
+	case op.LinearLight:
+		return clamp(cb + 2*cs - 1)
+	case op.Multiply:
+		return cs * cb
+	case op.Negation:
+		return 1 - math.Abs(1-cs-cb)
+	case op.Normal:
+		return cs
+	case op.Overlay:
+		return hardLight(cb, cs)
+	case op.Phoenix:
+		return math.Min(cs, cb) - math.Max(cs, cb) + 1
+	case op.PinLight:
+		if cs <= 0.5 {
+			return math.Min(cb, 2*cs)
+		}
+		return math.Max(cb, 2*cs-1)
+	case op.Reflect:
+		return reflect(cs, cb)
+	case op.Screen:
+		return cs + cb - cs*cb
+	case op.SoftLight, op.SoftLightW3C:
+		if cs <= 0.5 {
+			return cb - (1-2*cs)*cb*(1-cb)
+		}
+		d := math.Sqrt(cb)
+		if cb <= 0.25 {
+			d = ((16*cb-12)*cb + 4) * cb
+		}
+		return cb + (2*cs-1)*(d-cb)
+	case op.SoftLightIllusions:
+		return math.Pow(cb, math.Exp2(2*(0.5-cs)))
+	case op.SoftLightPegtop:
+		return (1-2*cs)*cb*cb + 2*cs*cb
+	case op.Subtract:
+		return math.Max(cb-cs, 0)
+	case op.VividLight:
+		if cs <= 0.5 {
+			return colorBurn(2*cs, cb)
+		}
+		return colorDodge(2*cs-1, cb)
+	case op.VividLightGIMP:
+		switch {
+		case cs == 0:
+			return 1
+		case cs == 1:
+			return 0
+		case cs <= 0.5:
+			return 1 - math.Min(1, (1-cb)/(2*cs))
+		default:
+			return math.Min(1, cb/(2*(1-cs)))
+		}
+	default:
+		return cs
+	}
+}
+
+// Composite composites src onto dst with a Porter-Duff operator.
+func Composite(mode op.CompositeMode, dst, src Color) Color {
+	as, ab := src.A, dst.A
+	var fa, fb float64
+	switch mode {
+	case op.Clear:
+	case op.Source:
+		fa = 1
+	case op.SourceOver:
+		fa, fb = 1, 1-as
+	case op.SourceIn:
+		fa = ab
+	case op.SourceOut:
+		fa = 1 - ab
+	case op.SourceAtop:
+		fa, fb = ab, 1-as
+	case op.Destination:
+		fb = 1
+	case op.DestinationOver:
+		fa, fb = 1-ab, 1
+	case op.DestinationIn:
+		fb = as
+	case op.DestinationOut:
+		fb = 1 - as
+	case op.DestinationAtop:
+		fa, fb = 1-ab, as
+	case op.Xor:
+		fa, fb = 1-ab, 1-as
+	}
+
+	a := as*fa + ab*fb
+	if a == 0 {
+		return Color{}
+	}
+	channel := func(cs, cb float64) float64 {
+		return (as*fa*cs + ab*fb*cb) / a
+	}
+	return Color{channel(src.R, dst.R), channel(src.G, dst.G), channel(src.B, dst.B), a}
+}
+
+func colorBurn(cs, cb float64) float64 {
+	switch {
+	case cb == 1:
+		return 1
+	case cs == 0:
+		return 0
+	default:
+		return 1 - math.Min(1, (1-cb)/cs)
+	}
+}
+
+func colorDodge(cs, cb float64) float64 {
+	switch {
+	case cb == 0:
+		return 0
+	case cs == 1:
+		return 1
+	default:
+		return math.Min(1, cb/(1-cs))
+	}
+}
+
+func hardLight(cs, cb float64) float64 {
+	if cs <= 0.5 {
+		return cb * 2 * cs
+	}
+	s := 2*cs - 1
+	return cb + s - cb*s
+}
+
+// reflect returns Cb² / (1 - Cs), or 1 if Cs is 1.
+func reflect(cs, cb float64) float64 {
+	if cs == 1 {
+		return 1
+	}
+	return math.Min(1, cb*cb/(1-cs))
+}
+
+// lum returns the luminosity of c as defined by the specification.
+func lum(c Color) float64 {
+	return 0.3*c.R + 0.59*c.G + 0.11*c.B
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,298 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package reference
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/internal"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+var compositings = []struct {
+	name string
+	c    op.BlendCompositing
+}{
+	{"CompositeAll", op.CompositeAll},
+	{"CompositeBlendAndSrc", op.CompositeBlendAndSrc},
+	{"CompositeBlendAndDst", op.CompositeBlendAndDst},
+	{"CompositeBlendOnly", op.CompositeBlendOnly},
+}
+
+var blendNames = []string{
+	op.AdditiveSubtractive: "AdditiveSubtractive",
+	op.Average:             "Average",
+	op.ColorBurn:           "ColorBurn",
+	op.ColorDodge:          "ColorDodge",
+	op.Darken:              "Darken",
+	op.DarkerColor:         "DarkerColor",
+	op.Difference:          "Difference",
+	op.Divide:              "Divide",
+	op.Exclusion:           "Exclusion",
+	op.Freeze:              "Freeze",
+	op.GeometricMean:       "GeometricMean",
+	op.Glow:                "Glow",
+	op.GrainExtract:        "GrainExtract",
+	op.GrainMerge:          "GrainMerge",
+	op.HardLight:           "HardLight",
+	op.HardMix:             "HardMix",
+	op.Heat:                "Heat",
+	op.Lighten:             "Lighten",
+	op.LighterColor:        "LighterColor",
+	op.LinearBurn:          "LinearBurn",
+	op.LinearDodge:         "LinearDodge",
+	op.LinearLight:         "LinearLight",
+	op.Multiply:            "Multiply",
+	op.Negation:            "Negation",
+	op.Normal:              "Normal",
+	op.Overlay:             "Overlay",
+	op.Phoenix:             "Phoenix",
+	op.PinLight:            "PinLight",
+	op.Reflect:             "Reflect",
+	op.Screen:              "Screen",
+	op.SoftLight:           "SoftLight",
+	op.SoftLightIllusions:  "SoftLightIllusions",
+	op.SoftLightPegtop:     "SoftLightPegtop",
+	op.SoftLightW3C:        "SoftLightW3C",
+	op.Subtract:            "Subtract",
+	op.VividLight:          "VividLight",
+	op.VividLightGIMP:      "VividLightGIMP",
+}
+
+var compositeNames = []string{
+	op.Clear:           "Clear",
+	op.Source:          "Source",
+	op.SourceOver:      "SourceOver",
+	op.SourceIn:        "SourceIn",
+	op.SourceOut:       "SourceOut",
+	op.SourceAtop:      "SourceAtop",
+	op.Destination:     "Destination",
+	op.DestinationOver: "DestinationOver",
+	op.DestinationIn:   "DestinationIn",
+	op.DestinationOut:  "DestinationOut",
+	op.DestinationAtop: "DestinationAtop",
+	op.Xor:             "Xor",
+}
+
+// Every rounding of an 8-bit intermediate is off by up to half a unit. Blend kernels round the
+// blended color, which integer divisions may truncate instead, and compositing rounds the
+// products of colors with alphas and their sum: at most six roundings, or 3 units. Composite
+// operators only round the products and their sum. Rounding to the nearest value errs by a
+// quarter unit on average, so the mean error of any mode stays below half a unit.
+//
+// Errors in RGBA are measured against the reference for the colors that the kernels see after
+// un-premultiplying, so that the amplification of the rounding of low alpha colors by steep
+// kernels is not counted; see CheckBlend. The edge inputs where ColorBurn, ColorDodge, HardMix
+// and VividLight depart from the specification are counted apart; see Divergent.
+const (
+	blendBudget     = 3
+	compositeBudget = 1.5
+	meanBudget      = 0.5
+)
+
+// softLightBudget bounds the error of SoftLight, whose kernel uses sqrt(Cb) for dark backdrops
+// where the specification uses a polynomial D(Cb), by the largest difference between both.
+var softLightBudget = func() float64 {
+	var d float64
+	for x := 0.0; x <= 0.25; x += 1e-5 {
+		d = max(d, math.Sqrt(x)-((16*x-12)*x+4)*x)
+	}
+	return blendBudget + d*255
+}()
+
+func TestBlend(t *testing.T) {
+	// Expected values from the blend package tests for the Translucent colors.
+	dst := FromNRGBA(color.NRGBA{0x40, 0x80, 0xc0, 0x80})
+	src := FromNRGBA(color.NRGBA{0xc0, 0x40, 0x80, 0x80})
+	tests := []struct {
+		mode        op.BlendMode
+		compositing op.BlendCompositing
+		want        color.NRGBA
+	}{
+		{op.Multiply, op.CompositeAll, color.NRGBA{0x65, 0x4a, 0x8a, 0xc0}},
+		{op.Multiply, op.CompositeBlendAndSrc, color.NRGBA{0x60, 0x2b, 0x6a, 0x80}},
+		{op.Multiply, op.CompositeBlendAndDst, color.NRGBA{0x35, 0x40, 0x80, 0x80}},
+		{op.Multiply, op.CompositeBlendOnly, color.NRGBA{0x30, 0x20, 0x60, 0x40}},
+		{op.Normal, op.CompositeAll, color.NRGBA{0x95, 0x55, 0x95, 0xc0}},
+		{op.DarkerColor, op.CompositeBlendOnly, color.NRGBA{0xc0, 0x40, 0x80, 0x40}},
+		{op.LighterColor, op.CompositeBlendOnly, color.NRGBA{0x40, 0x80, 0xc0, 0x40}},
+	}
+	for _, tt := range tests {
+		got := Blend(tt.mode, tt.compositing, dst, src)
+		if !near(got, tt.want, 1) {
+			t.Errorf("Blend(%d, %d) = %v, want %v", tt.mode, tt.compositing, got, tt.want)
+		}
+	}
+
+	if got := Blend(op.Multiply, op.CompositeAll, dst, Color{}); got != dst {
+		t.Errorf("blending a transparent source = %v, want %v", got, dst)
+	}
+	if got := Blend(op.Multiply, op.CompositeBlendOnly, Color{}, src); got != (Color{}) {
+		t.Errorf("blending onto a transparent backdrop = %v, want transparent", got)
+	}
+}
+
+func TestBlendChannel(t *testing.T) {
+	tests := []struct {
+		mode           op.BlendMode
+		cs, cb, result float64
+	}{
+		{op.ColorBurn, 0, 1, 1},
+		{op.ColorBurn, 0.5, 0.75, 0.5},
+		{op.ColorDodge, 1, 0, 0},
+		{op.ColorDodge, 0.5, 0.25, 0.5},
+		{op.Overlay, 1, 0.25, 0.5},
+		{op.HardLight, 0.25, 1, 0.5},
+		{op.SoftLight, 1, 0.25, 0.5},
+		{op.SoftLight, 0, 0.5, 0.25},
+		{op.VividLight, 0.25, 0.75, 0.5},
+		{op.PinLight, 0.25, 0.75, 0.5},
+		{op.LinearLight, 0.75, 0.25, 0.75},
+		{op.Subtract, 0.75, 0.25, 0},
+		{op.AdditiveSubtractive, 0.25, 1, 0.5},
+		{op.Freeze, 0, 0.5, 0},
+		{op.Freeze, 0.5, 0.5, 0.5},
+		{op.Glow, 0.5, 0.5, 0.5},
+		{op.GrainExtract, 0.25, 0.25, 0.5},
+		{op.Heat, 0.5, 0.5, 0.5},
+		{op.Negation, 0.75, 0.75, 0.5},
+		{op.Phoenix, 0.25, 0.75, 0.5},
+		{op.Reflect, 1, 0, 1},
+		{op.SoftLightIllusions, 0, 0.5, 0.25},
+		{op.SoftLightPegtop, 1, 0.25, 0.4375},
+		{op.SoftLightW3C, 1, 0.25, 0.5},
+		{op.VividLightGIMP, 0, 0.5, 1},
+		{op.VividLightGIMP, 1, 0.5, 0},
+	}
+	for _, tt := range tests {
+		if got := BlendChannel(tt.mode, tt.cs, tt.cb); math.Abs(got-tt.result) > 1e-9 {
+			t.Errorf("BlendChannel(%d, %v, %v) = %v, want %v", tt.mode, tt.cs, tt.cb, got, tt.result)
+		}
+	}
+}
+
+func TestComposite(t *testing.T) {
+	dst := FromNRGBA(color.NRGBA{0x40, 0x80, 0xc0, 0x80})
+	src := FromNRGBA(color.NRGBA{0xc0, 0x40, 0x80, 0x80})
+	tests := []struct {
+		mode op.CompositeMode
+		want color.NRGBA
+	}{
+		{op.Clear, color.NRGBA{}},
+		{op.Source, color.NRGBA{0xc0, 0x40, 0x80, 0x80}},
+		{op.Destination, color.NRGBA{0x40, 0x80, 0xc0, 0x80}},
+		{op.SourceOver, color.NRGBA{0x95, 0x55, 0x95, 0xc0}},
+		{op.SourceIn, color.NRGBA{0xc0, 0x40, 0x80, 0x40}},
+		{op.SourceAtop, color.NRGBA{0x80, 0x60, 0xa0, 0x80}},
+		{op.Xor, color.NRGBA{0x80, 0x60, 0xa0, 0x80}},
+	}
+	for _, tt := range tests {
+		if got := Composite(tt.mode, dst, src); !near(got, tt.want, 1) {
+			t.Errorf("Composite(%d) = %v, want %v", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestAccuracy(t *testing.T) {
+	cfg := internal.DefaultConfig
+	for mode, name := range blendNames {
+		if name == "" {
+			continue // Dissolve is random and has no reference
+		}
+		budget := float64(blendBudget)
+		if op.BlendMode(mode) == op.SoftLight {
+			budget = softLightBudget
+		}
+		for _, c := range compositings {
+			t.Run(name+"/"+c.name, func(t *testing.T) {
+				acc, err := CheckBlend(cfg, op.BlendOp{Mode: op.BlendMode(mode), Compositing: c.c}, DefaultSamples)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkReport(t, "NRGBA", acc.NRGBA, budget)
+				checkReport(t, "RGBA", acc.RGBA, budget)
+			})
+		}
+	}
+	for mode, name := range compositeNames {
+		t.Run(name, func(t *testing.T) {
+			acc, err := CheckComposite(cfg, op.CompositeOp{Mode: op.CompositeMode(mode)}, DefaultSamples)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkReport(t, "NRGBA", acc.NRGBA, compositeBudget)
+			checkReport(t, "RGBA", acc.RGBA, compositeBudget)
+		})
+	}
+
+	if _, err := CheckBlend(cfg, op.BlendOp{Mode: -1, Compositing: op.CompositeAll}, DefaultSamples); err == nil {
+		t.Error("expected error for invalid blend operation")
+	}
+	if _, err := CheckComposite(cfg, op.CompositeOp{Mode: -1}, DefaultSamples); err == nil {
+		t.Error("expected error for invalid composite operation")
+	}
+}
+
+func TestDivergent(t *testing.T) {
+	tests := []struct {
+		mode     op.BlendMode
+		dst, src float64
+		want     bool
+	}{
+		{op.ColorBurn, 1, 0, true},
+		{op.ColorBurn, 1, 1.0 / 255, false},
+		{op.ColorBurn, 0, 1, false},
+		{op.ColorDodge, 0, 1, true},
+		{op.ColorDodge, 0, 254.0 / 255, false},
+		{op.VividLight, 1, 0, true},
+		{op.VividLight, 0, 1, true},
+		{op.VividLight, 0.5, 0, false},
+		{op.HardMix, 127.0 / 255, 128.0 / 255, true},
+		{op.HardMix, 128.0 / 255, 129.0 / 255, false},
+		{op.Multiply, 1, 0, false},
+	}
+	for _, tt := range tests {
+		if got := Divergent(tt.mode, tt.dst, tt.src); got != tt.want {
+			t.Errorf("Divergent(%v, %v, %v) = %v, want %v", tt.mode, tt.dst, tt.src, got, tt.want)
+		}
+	}
+	acc, err := CheckBlend(internal.DefaultConfig, op.BlendOp{Mode: op.ColorBurn, Compositing: op.CompositeAll}, DefaultSamples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the channels with Cs = 0 and Cb = 1, at each pair of alphas.
+	alphas := len(DefaultSamples.Alphas) * len(DefaultSamples.Alphas)
+	if acc.NRGBA.Divergent != alphas {
+		t.Errorf("%d divergent NRGBA channel values, want %d", acc.NRGBA.Divergent, alphas)
+	}
+}
+
+// TestGeometricMeanKernel checks the kernel alone: without compositing, NRGBA results are the
+// blend of the input colors rounded once.
+func TestGeometricMeanKernel(t *testing.T) {
+	acc, err := CheckBlend(internal.DefaultConfig, op.BlendOp{Mode: op.GeometricMean, Compositing: op.CompositeBlendOnly}, DefaultSamples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReport(t, "NRGBA", acc.NRGBA, 1)
+}
+
+func checkReport(t *testing.T, model string, r Report, budget float64) {
+	t.Helper()
+	t.Logf("%-5s max %6.2f  mean %.3f  >1: %6d / %d  divergent: %d", model, r.Max, r.Mean, r.Exceeding, r.Samples, r.Divergent)
+	if r.Max > budget {
+		t.Errorf("%s max error %.2f exceeds %.2f; worst case %+v", model, r.Max, budget, r.Worst)
+	}
+	if r.Mean > meanBudget {
+		t.Errorf("%s mean error %.3f exceeds %.2f", model, r.Mean, meanBudget)
+	}
+}
+
+func near(c Color, want color.NRGBA, tolerance float64) bool {
+	w := FromNRGBA(want)
+	return math.Abs(c.R-w.R)*255 <= tolerance && math.Abs(c.G-w.G)*255 <= tolerance &&
+		math.Abs(c.B-w.B)*255 <= tolerance && math.Abs(c.A-w.A)*255 <= tolerance
+}
@@ -12,9 +12,9 @@ import (
 	"github.com/blazeroni/magpie/pkg/internal"
 )
 
-// BlendColorBurn performs a 'ColorBurn' blend on RGBA images.
-// Logic: Cr = 1 - (1 - Cd) / Cs
-func BlendColorBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendAdditiveSubtractive performs a 'AdditiveSubtractive' blend on RGBA images.
+// Logic: Cr = abs(sqrt(Cd) - sqrt(Cs))
+func BlendAdditiveSubtractive(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -46,20 +46,17 @@ func BlendColorBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR+dR <= 255 {
-					kR = 0
-				} else {
-					kR = 255 - ((255-dR)*255+sR/2)/sR
+				kR = sqrt(dR) - sqrt(sR)
+				if kR > 255 {
+					kR = sqrt(sR) - sqrt(dR)
 				}
-				if sG+dG <= 255 {
-					kG = 0
-				} else {
-					kG = 255 - ((255-dG)*255+sG/2)/sG
+				kG = sqrt(dG) - sqrt(sG)
+				if kG > 255 {
+					kG = sqrt(sG) - sqrt(dG)
 				}
-				if sB+dB <= 255 {
-					kB = 0
-				} else {
-					kB = 255 - ((255-dB)*255+sB/2)/sB
+				kB = sqrt(dB) - sqrt(sB)
+				if kB > 255 {
+					kB = sqrt(sB) - sqrt(dB)
 				}
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
@@ -86,20 +83,17 @@ func BlendColorBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR+dR <= 255 {
-					kR = 0
-				} else {
-					kR = 255 - ((255-dR)*255+sR/2)/sR
+				kR = sqrt(dR) - sqrt(sR)
+				if kR > 255 {
+					kR = sqrt(sR) - sqrt(dR)
 				}
-				if sG+dG <= 255 {
-					kG = 0
-				} else {
-					kG = 255 - ((255-dG)*255+sG/2)/sG
+				kG = sqrt(dG) - sqrt(sG)
+				if kG > 255 {
+					kG = sqrt(sG) - sqrt(dG)
 				}
-				if sB+dB <= 255 {
-					kB = 0
-				} else {
-					kB = 255 - ((255-dB)*255+sB/2)/sB
+				kB = sqrt(dB) - sqrt(sB)
+				if kB > 255 {
+					kB = sqrt(sB) - sqrt(dB)
 				}
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
@@ -161,9 +155,9 @@ func BlendColorBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 	})
 }
 
-// BlendColorDodge performs a 'ColorDodge' blend on RGBA images.
-// Logic: Cr = Cd / (1 - Cs)
-func BlendColorDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendAverage performs a 'Average' blend on RGBA images.
+// Logic: Cr = (Cs + Cd) / 2
+func BlendAverage(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -195,21 +189,9 @@ func BlendColorDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR == 255 {
-					kR = 255
-				} else {
-					kR = min((dR*255)/(255-sR), 255)
-				}
-				if sG == 255 {
-					kG = 255
-				} else {
-					kG = min((dG*255)/(255-sG), 255)
-				}
-				if sB == 255 {
-					kB = 255
-				} else {
-					kB = min((dB*255)/(255-sB), 255)
-				}
+				kR = (sR + dR + 1) / 2
+				kG = (sG + dG + 1) / 2
+				kB = (sB + dB + 1) / 2
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -235,21 +217,9 @@ func BlendColorDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR == 255 {
-					kR = 255
-				} else {
-					kR = min((dR*255)/(255-sR), 255)
-				}
-				if sG == 255 {
-					kG = 255
-				} else {
-					kG = min((dG*255)/(255-sG), 255)
-				}
-				if sB == 255 {
-					kB = 255
-				} else {
-					kB = min((dB*255)/(255-sB), 255)
-				}
+				kR = (sR + dR + 1) / 2
+				kG = (sG + dG + 1) / 2
+				kB = (sB + dB + 1) / 2
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
 				// premultiplied compositing color results
@@ -310,9 +280,9 @@ func BlendColorDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 	})
 }
 
-// BlendDarken performs a 'Darken' blend on RGBA images.
-// Logic: Cr = min(Cs, Cd)
-func BlendDarken(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendColorBurn performs a 'ColorBurn' blend on RGBA images.
+// Logic: Cr = 1 - (1 - Cd) / Cs
+func BlendColorBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -344,9 +314,21 @@ func BlendDarken(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = min(sR, dR)
-				kG = min(sG, dG)
-				kB = min(sB, dB)
+				if sR+dR <= 255 {
+					kR = 0
+				} else {
+					kR = 255 - ((255-dR)*255+sR/2)/sR
+				}
+				if sG+dG <= 255 {
+					kG = 0
+				} else {
+					kG = 255 - ((255-dG)*255+sG/2)/sG
+				}
+				if sB+dB <= 255 {
+					kB = 0
+				} else {
+					kB = 255 - ((255-dB)*255+sB/2)/sB
+				}
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -372,9 +354,21 @@ func BlendDarken(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = min(sR, dR)
-				kG = min(sG, dG)
-				kB = min(sB, dB)
+				if sR+dR <= 255 {
+					kR = 0
+				} else {
+					kR = 255 - ((255-dR)*255+sR/2)/sR
+				}
+				if sG+dG <= 255 {
+					kG = 0
+				} else {
+					kG = 255 - ((255-dG)*255+sG/2)/sG
+				}
+				if sB+dB <= 255 {
+					kB = 0
+				} else {
+					kB = 255 - ((255-dB)*255+sB/2)/sB
+				}
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
 				// premultiplied compositing color results
@@ -435,9 +429,9 @@ func BlendDarken(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA
 	})
 }
 
-// BlendDifference performs a 'Difference' blend on RGBA images.
-// Logic: Cr = abs(Cs - Cd)
-func BlendDifference(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendColorDodge performs a 'ColorDodge' blend on RGBA images.
+// Logic: Cr = Cd / (1 - Cs)
+func BlendColorDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -469,9 +463,21 @@ func BlendDifference(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = min(sR-dR, dR-sR)
-				kG = min(sG-dG, dG-sG)
-				kB = min(sB-dB, dB-sB)
+				if sR == 255 {
+					kR = 255
+				} else {
+					kR = min((dR*255)/(255-sR), 255)
+				}
+				if sG == 255 {
+					kG = 255
+				} else {
+					kG = min((dG*255)/(255-sG), 255)
+				}
+				if sB == 255 {
+					kB = 255
+				} else {
+					kB = min((dB*255)/(255-sB), 255)
+				}
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -482,15 +488,7 @@ func BlendDifference(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 
 			// final output colors & alpha
 			var oRp, oGp, oBp, oA uint32
-			if compositing == internal.CompositeAll {
-				// Fast path for Source Over using direct premultiplied equation
-				oA = sA + md255(dA, 255-sA)
-				// region BLEND-SPECIFIC EQUATION LOGIC
-				oRp = sR + dR - div255(2*min(sR*dA, dR*sA))
-				oGp = sG + dG - div255(2*min(sG*dA, dG*sA))
-				oBp = sB + dB - div255(2*min(sB*dA, dB*sA))
-				// endregion BLEND-SPECIFIC EQUATION LOGIC
-			} else {
+			{
 				// Fallback path for other compositions, or for blend modes without a direct equation.
 				// This path is accurate but slower as it must un-premultiply.
 				sR = unpremultiply(sR, sA)
@@ -505,9 +503,21 @@ func BlendDifference(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = min(sR-dR, dR-sR)
-				kG = min(sG-dG, dG-sG)
-				kB = min(sB-dB, dB-sB)
+				if sR == 255 {
+					kR = 255
+				} else {
+					kR = min((dR*255)/(255-sR), 255)
+				}
+				if sG == 255 {
+					kG = 255
+				} else {
+					kG = min((dG*255)/(255-sG), 255)
+				}
+				if sB == 255 {
+					kB = 255
+				} else {
+					kB = min((dB*255)/(255-sB), 255)
+				}
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
 				// premultiplied compositing color results
@@ -517,6 +527,19 @@ func BlendDifference(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 				var oRu, oGu, oBu uint32
 
 				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
 				case internal.CompositeBlendAndSrc:
 					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
 					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
@@ -555,9 +578,9 @@ func BlendDifference(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 	})
 }
 
-// BlendDivide performs a 'Divide' blend on RGBA images.
-// Logic: Cr = Cd / Cs
-func BlendDivide(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendDarken performs a 'Darken' blend on RGBA images.
+// Logic: Cr = min(Cs, Cd)
+func BlendDarken(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -589,21 +612,9 @@ func BlendDivide(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR == 0 {
-					kR = 255
-				} else {
-					kR = min((dR*255+sR/2)/sR, 255)
-				}
-				if sG == 0 {
-					kG = 255
-				} else {
-					kG = min((dG*255+sG/2)/sG, 255)
-				}
-				if sB == 0 {
-					kB = 255
-				} else {
-					kB = min((dB*255+sB/2)/sB, 255)
-				}
+				kR = min(sR, dR)
+				kG = min(sG, dG)
+				kB = min(sB, dB)
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -629,21 +640,9 @@ func BlendDivide(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR == 0 {
-					kR = 255
-				} else {
-					kR = min((dR*255+sR/2)/sR, 255)
-				}
-				if sG == 0 {
-					kG = 255
-				} else {
-					kG = min((dG*255+sG/2)/sG, 255)
-				}
-				if sB == 0 {
-					kB = 255
-				} else {
-					kB = min((dB*255+sB/2)/sB, 255)
-				}
+				kR = min(sR, dR)
+				kG = min(sG, dG)
+				kB = min(sB, dB)
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
 				// premultiplied compositing color results
@@ -704,9 +703,9 @@ func BlendDivide(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA
 	})
 }
 
-// BlendExclusion performs a 'Exclusion' blend on RGBA images.
-// Logic: Cr = Cs + Cd - 2 * Cs * Cd
-func BlendExclusion(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendDarkerColor performs a 'DarkerColor' blend on RGBA images.
+// Logic: if Lum(Cs) < Lum(Cd) { Cr = Cs } else { Cr = Cd }, where Lum(C) = 0.3*R + 0.59*G + 0.11*B
+func BlendDarkerColor(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -738,9 +737,12 @@ func BlendExclusion(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = sR + dR - 2*md255(sR, dR)
-				kG = sG + dG - 2*md255(sG, dG)
-				kB = sB + dB - 2*md255(sB, dB)
+				if lum(sR, sG, sB) < lum(dR, dG, dB) {
+					kR, kG, kB = sR, sG, sB
+				} else {
+					kR, kG, kB = dR, dG, dB
+				}
+
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -755,9 +757,11 @@ func BlendExclusion(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 				// Fast path for Source Over using direct premultiplied equation
 				oA = sA + md255(dA, 255-sA)
 				// region BLEND-SPECIFIC EQUATION LOGIC
-				oRp = sR + dR - 2*md255(sR, dR)
-				oGp = sG + dG - 2*md255(sG, dG)
-				oBp = sB + dB - 2*md255(sB, dB)
+				if lum(sR, sG, sB)*dA < lum(dR, dG, dB)*sA {
+					oRp, oGp, oBp = sR+md255(dR, 255-sA), sG+md255(dG, 255-sA), sB+md255(dB, 255-sA)
+				} else {
+					oRp, oGp, oBp = md255(sR, 255-dA)+dR, md255(sG, 255-dA)+dG, md255(sB, 255-dA)+dB
+				}
 				// endregion BLEND-SPECIFIC EQUATION LOGIC
 			} else {
 				// Fallback path for other compositions, or for blend modes without a direct equation.
@@ -774,9 +778,12 @@ func BlendExclusion(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = sR + dR - 2*md255(sR, dR)
-				kG = sG + dG - 2*md255(sG, dG)
-				kB = sB + dB - 2*md255(sB, dB)
+				if lum(sR, sG, sB) < lum(dR, dG, dB) {
+					kR, kG, kB = sR, sG, sB
+				} else {
+					kR, kG, kB = dR, dG, dB
+				}
+
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
 				// premultiplied compositing color results
@@ -824,9 +831,9 @@ func BlendExclusion(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 	})
 }
 
-// BlendHardLight performs a 'HardLight' blend on RGBA images.
-// Logic: if Cs < 0.5 { Cr = 2 * Cs * Cd } else { Cr = 1 - 2 * (1 - Cs) * (1 - Cd) }
-func BlendHardLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendDifference performs a 'Difference' blend on RGBA images.
+// Logic: Cr = abs(Cs - Cd)
+func BlendDifference(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -858,21 +865,9 @@ func BlendHardLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR < 128 {
-					kR = 2 * md255(sR, dR)
-				} else {
-					kR = 255 - 2*md255(255-sR, 255-dR)
-				}
-				if sG < 128 {
-					kG = 2 * md255(sG, dG)
-				} else {
-					kG = 255 - 2*md255(255-sG, 255-dG)
-				}
-				if sB < 128 {
-					kB = 2 * md255(sB, dB)
-				} else {
-					kB = 255 - 2*md255(255-sB, 255-dB)
-				}
+				kR = min(sR-dR, dR-sR)
+				kG = min(sG-dG, dG-sG)
+				kB = min(sB-dB, dB-sB)
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -883,7 +878,15 @@ func BlendHardLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 
 			// final output colors & alpha
 			var oRp, oGp, oBp, oA uint32
-			{
+			if compositing == internal.CompositeAll {
+				// Fast path for Source Over using direct premultiplied equation
+				oA = sA + md255(dA, 255-sA)
+				// region BLEND-SPECIFIC EQUATION LOGIC
+				oRp = sR + dR - div255(2*min(sR*dA, dR*sA))
+				oGp = sG + dG - div255(2*min(sG*dA, dG*sA))
+				oBp = sB + dB - div255(2*min(sB*dA, dB*sA))
+				// endregion BLEND-SPECIFIC EQUATION LOGIC
+			} else {
 				// Fallback path for other compositions, or for blend modes without a direct equation.
 				// This path is accurate but slower as it must un-premultiply.
 				sR = unpremultiply(sR, sA)
@@ -898,21 +901,9 @@ func BlendHardLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR < 128 {
-					kR = 2 * md255(sR, dR)
-				} else {
-					kR = 255 - 2*md255(255-sR, 255-dR)
-				}
-				if sG < 128 {
-					kG = 2 * md255(sG, dG)
-				} else {
-					kG = 255 - 2*md255(255-sG, 255-dG)
-				}
-				if sB < 128 {
-					kB = 2 * md255(sB, dB)
-				} else {
-					kB = 255 - 2*md255(255-sB, 255-dB)
-				}
+				kR = min(sR-dR, dR-sR)
+				kG = min(sG-dG, dG-sG)
+				kB = min(sB-dB, dB-sB)
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
 				// premultiplied compositing color results
@@ -922,19 +913,6 @@ func BlendHardLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 				var oRu, oGu, oBu uint32
 
 				switch compositing {
-				case internal.CompositeAll:
-					invSA, invDA := 255-sA, 255-dA
-					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
-
-					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
-					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
-					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
-					cA = sA + term2
-
-					oRu = (cRp*255 + cA/2) / cA
-					oGu = (cGp*255 + cA/2) / cA
-					oBu = (cBp*255 + cA/2) / cA
-					oA = cA
 				case internal.CompositeBlendAndSrc:
 					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
 					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
@@ -973,9 +951,9 @@ func BlendHardLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 	})
 }
 
-// BlendHardMix performs a 'HardMix' blend on RGBA images.
-// Logic: if Cs + Cd < 1 { Cr = 0 } else { Cr = 1 }
-func BlendHardMix(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendDivide performs a 'Divide' blend on RGBA images.
+// Logic: Cr = Cd / Cs
+func BlendDivide(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1007,20 +985,20 @@ func BlendHardMix(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGB
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR+dR < 255 {
-					kR = 0
-				} else {
+				if sR == 0 {
 					kR = 255
-				}
-				if sG+dG < 255 {
-					kG = 0
 				} else {
-					kG = 255
+					kR = min((dR*255+sR/2)/sR, 255)
 				}
-				if sB+dB < 255 {
-					kB = 0
+				if sG == 0 {
+					kG = 255
 				} else {
+					kG = min((dG*255+sG/2)/sG, 255)
+				}
+				if sB == 0 {
 					kB = 255
+				} else {
+					kB = min((dB*255+sB/2)/sB, 255)
 				}
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
@@ -1047,20 +1025,20 @@ func BlendHardMix(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGB
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR+dR < 255 {
-					kR = 0
-				} else {
+				if sR == 0 {
 					kR = 255
-				}
-				if sG+dG < 255 {
-					kG = 0
 				} else {
-					kG = 255
+					kR = min((dR*255+sR/2)/sR, 255)
 				}
-				if sB+dB < 255 {
-					kB = 0
+				if sG == 0 {
+					kG = 255
 				} else {
+					kG = min((dG*255+sG/2)/sG, 255)
+				}
+				if sB == 0 {
 					kB = 255
+				} else {
+					kB = min((dB*255+sB/2)/sB, 255)
 				}
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
@@ -1122,9 +1100,9 @@ func BlendHardMix(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGB
 	})
 }
 
-// BlendLighten performs a 'Lighten' blend on RGBA images.
-// Logic: Cr = max(Cs, Cd)
-func BlendLighten(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendExclusion performs a 'Exclusion' blend on RGBA images.
+// Logic: Cr = Cs + Cd - 2 * Cs * Cd
+func BlendExclusion(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1156,9 +1134,9 @@ func BlendLighten(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGB
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = max(sR, dR)
-				kG = max(sG, dG)
-				kB = max(sB, dB)
+				kR = sR + dR - 2*md255(sR, dR)
+				kG = sG + dG - 2*md255(sG, dG)
+				kB = sB + dB - 2*md255(sB, dB)
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -1169,7 +1147,15 @@ func BlendLighten(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGB
 
 			// final output colors & alpha
 			var oRp, oGp, oBp, oA uint32
-			{
+			if compositing == internal.CompositeAll {
+				// Fast path for Source Over using direct premultiplied equation
+				oA = sA + md255(dA, 255-sA)
+				// region BLEND-SPECIFIC EQUATION LOGIC
+				oRp = sR + dR - 2*md255(sR, dR)
+				oGp = sG + dG - 2*md255(sG, dG)
+				oBp = sB + dB - 2*md255(sB, dB)
+				// endregion BLEND-SPECIFIC EQUATION LOGIC
+			} else {
 				// Fallback path for other compositions, or for blend modes without a direct equation.
 				// This path is accurate but slower as it must un-premultiply.
 				sR = unpremultiply(sR, sA)
@@ -1184,9 +1170,9 @@ func BlendLighten(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGB
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = max(sR, dR)
-				kG = max(sG, dG)
-				kB = max(sB, dB)
+				kR = sR + dR - 2*md255(sR, dR)
+				kG = sG + dG - 2*md255(sG, dG)
+				kB = sB + dB - 2*md255(sB, dB)
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
 				// premultiplied compositing color results
@@ -1196,19 +1182,6 @@ func BlendLighten(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGB
 				var oRu, oGu, oBu uint32
 
 				switch compositing {
-				case internal.CompositeAll:
-					invSA, invDA := 255-sA, 255-dA
-					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
-
-					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
-					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
-					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
-					cA = sA + term2
-
-					oRu = (cRp*255 + cA/2) / cA
-					oGu = (cGp*255 + cA/2) / cA
-					oBu = (cBp*255 + cA/2) / cA
-					oA = cA
 				case internal.CompositeBlendAndSrc:
 					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
 					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
@@ -1247,9 +1220,9 @@ func BlendLighten(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGB
 	})
 }
 
-// BlendLinearBurn performs a 'LinearBurn' blend on RGBA images.
-// Logic: Cr = Cs + Cd - 1
-func BlendLinearBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendFreeze performs a 'Freeze' blend on RGBA images.
+// Logic: Cr = 1 - (1 - Cd)^2 / Cs
+func BlendFreeze(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1281,17 +1254,20 @@ func BlendLinearBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = sR + dR - 255
-				if kR > 255 {
+				if sR == 0 {
 					kR = 0
+				} else {
+					kR = 255 - min(((255-dR)*(255-dR)+sR/2)/sR, 255)
 				}
-				kG = sG + dG - 255
-				if kG > 255 {
+				if sG == 0 {
 					kG = 0
+				} else {
+					kG = 255 - min(((255-dG)*(255-dG)+sG/2)/sG, 255)
 				}
-				kB = sB + dB - 255
-				if kB > 255 {
+				if sB == 0 {
 					kB = 0
+				} else {
+					kB = 255 - min(((255-dB)*(255-dB)+sB/2)/sB, 255)
 				}
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
@@ -1318,17 +1294,20 @@ func BlendLinearBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = sR + dR - 255
-				if kR > 255 {
+				if sR == 0 {
 					kR = 0
+				} else {
+					kR = 255 - min(((255-dR)*(255-dR)+sR/2)/sR, 255)
 				}
-				kG = sG + dG - 255
-				if kG > 255 {
+				if sG == 0 {
 					kG = 0
+				} else {
+					kG = 255 - min(((255-dG)*(255-dG)+sG/2)/sG, 255)
 				}
-				kB = sB + dB - 255
-				if kB > 255 {
+				if sB == 0 {
 					kB = 0
+				} else {
+					kB = 255 - min(((255-dB)*(255-dB)+sB/2)/sB, 255)
 				}
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
@@ -1390,9 +1369,9 @@ func BlendLinearBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 	})
 }
 
-// BlendLinearDodge performs a 'LinearDodge' blend on RGBA images.
-// Logic: Cr = Cs + Cd
-func BlendLinearDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendGeometricMean performs a 'GeometricMean' blend on RGBA images.
+// Logic: Cr = sqrt(Cs * Cd)
+func BlendGeometricMean(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1424,9 +1403,9 @@ func BlendLinearDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = min(sR+dR, 255)
-				kG = min(sG+dG, 255)
-				kB = min(sB+dB, 255)
+				kR = isqrt(sR * dR)
+				kG = isqrt(sG * dG)
+				kB = isqrt(sB * dB)
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -1452,9 +1431,9 @@ func BlendLinearDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = min(sR+dR, 255)
-				kG = min(sG+dG, 255)
-				kB = min(sB+dB, 255)
+				kR = isqrt(sR * dR)
+				kG = isqrt(sG * dG)
+				kB = isqrt(sB * dB)
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
 				// premultiplied compositing color results
@@ -1515,9 +1494,9 @@ func BlendLinearDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image
 	})
 }
 
-// BlendLinearLight performs a 'LinearLight' blend on RGBA images.
-// Logic: Cr = Cd + 2*Cs - 1
-func BlendLinearLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendGlow performs a 'Glow' blend on RGBA images.
+// Logic: Cr = Cs^2 / (1 - Cd)
+func BlendGlow(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1549,23 +1528,20 @@ func BlendLinearLight(pixIter core.PixelIterator, calc core.PixCalculator[*image
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = dR + 2*sR - 255
-				if kR > 510 {
-					kR = 0
-				} else if kR > 255 {
+				if dR == 255 {
 					kR = 255
+				} else {
+					kR = min((sR*sR+(255-dR)/2)/(255-dR), 255)
 				}
-				kG = dG + 2*sG - 255
-				if kG > 510 {
-					kG = 0
-				} else if kG > 255 {
+				if dG == 255 {
 					kG = 255
+				} else {
+					kG = min((sG*sG+(255-dG)/2)/(255-dG), 255)
 				}
-				kB = dB + 2*sB - 255
-				if kB > 510 {
-					kB = 0
-				} else if kB > 255 {
+				if dB == 255 {
 					kB = 255
+				} else {
+					kB = min((sB*sB+(255-dB)/2)/(255-dB), 255)
 				}
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
@@ -1592,23 +1568,20 @@ func BlendLinearLight(pixIter core.PixelIterator, calc core.PixCalculator[*image
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = dR + 2*sR - 255
-				if kR > 510 {
-					kR = 0
-				} else if kR > 255 {
+				if dR == 255 {
 					kR = 255
+				} else {
+					kR = min((sR*sR+(255-dR)/2)/(255-dR), 255)
 				}
-				kG = dG + 2*sG - 255
-				if kG > 510 {
-					kG = 0
-				} else if kG > 255 {
+				if dG == 255 {
 					kG = 255
+				} else {
+					kG = min((sG*sG+(255-dG)/2)/(255-dG), 255)
 				}
-				kB = dB + 2*sB - 255
-				if kB > 510 {
-					kB = 0
-				} else if kB > 255 {
+				if dB == 255 {
 					kB = 255
+				} else {
+					kB = min((sB*sB+(255-dB)/2)/(255-dB), 255)
 				}
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
@@ -1670,9 +1643,9 @@ func BlendLinearLight(pixIter core.PixelIterator, calc core.PixCalculator[*image
 	})
 }
 
-// BlendMultiply performs a 'Multiply' blend on RGBA images.
-// Logic: Cr = Cs * Cd
-func BlendMultiply(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendGrainExtract performs a 'GrainExtract' blend on RGBA images.
+// Logic: Cr = Cd - Cs + 0.5
+func BlendGrainExtract(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1704,9 +1677,9 @@ func BlendMultiply(pixIter core.PixelIterator, calc core.PixCalculator[*image.RG
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = md255(sR, dR)
-				kG = md255(sG, dG)
-				kB = md255(sB, dB)
+				kR = uint32(max(min(int(dR)-int(sR)+128, 255), 0))
+				kG = uint32(max(min(int(dG)-int(sG)+128, 255), 0))
+				kB = uint32(max(min(int(dB)-int(sB)+128, 255), 0))
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -1717,15 +1690,7 @@ func BlendMultiply(pixIter core.PixelIterator, calc core.PixCalculator[*image.RG
 
 			// final output colors & alpha
 			var oRp, oGp, oBp, oA uint32
-			if compositing == internal.CompositeAll {
-				// Fast path for Source Over using direct premultiplied equation
-				oA = sA + md255(dA, 255-sA)
-				// region BLEND-SPECIFIC EQUATION LOGIC
-				oRp = md255(dR, 255-sA) + md255(sR, 255-dA) + md255(sR, dR)
-				oGp = md255(dG, 255-sA) + md255(sG, 255-dA) + md255(sG, dG)
-				oBp = md255(dB, 255-sA) + md255(sB, 255-dA) + md255(sB, dB)
-				// endregion BLEND-SPECIFIC EQUATION LOGIC
-			} else {
+			{
 				// Fallback path for other compositions, or for blend modes without a direct equation.
 				// This path is accurate but slower as it must un-premultiply.
 				sR = unpremultiply(sR, sA)
@@ -1740,9 +1705,9 @@ func BlendMultiply(pixIter core.PixelIterator, calc core.PixCalculator[*image.RG
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = md255(sR, dR)
-				kG = md255(sG, dG)
-				kB = md255(sB, dB)
+				kR = uint32(max(min(int(dR)-int(sR)+128, 255), 0))
+				kG = uint32(max(min(int(dG)-int(sG)+128, 255), 0))
+				kB = uint32(max(min(int(dB)-int(sB)+128, 255), 0))
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
 				// premultiplied compositing color results
@@ -1752,6 +1717,19 @@ func BlendMultiply(pixIter core.PixelIterator, calc core.PixCalculator[*image.RG
 				var oRu, oGu, oBu uint32
 
 				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
 				case internal.CompositeBlendAndSrc:
 					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
 					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
@@ -1790,9 +1768,9 @@ func BlendMultiply(pixIter core.PixelIterator, calc core.PixCalculator[*image.RG
 	})
 }
 
-// BlendOverlay performs a 'Overlay' blend on RGBA images.
-// Logic: if Cd < 0.5 { Cr = 2 * Cs * Cd } else { Cr = 1 - 2 * (1 - Cs) * (1 - Cd) }
-func BlendOverlay(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendGrainMerge performs a 'GrainMerge' blend on RGBA images.
+// Logic: Cr = Cd + Cs - 0.5
+func BlendGrainMerge(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1824,21 +1802,9 @@ func BlendOverlay(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGB
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if dR < 128 {
-					kR = 2 * md255(sR, dR)
-				} else {
-					kR = 255 - 2*md255(255-sR, 255-dR)
-				}
-				if dG < 128 {
-					kG = 2 * md255(sG, dG)
-				} else {
-					kG = 255 - 2*md255(255-sG, 255-dG)
-				}
-				if dB < 128 {
-					kB = 2 * md255(sB, dB)
-				} else {
-					kB = 255 - 2*md255(255-sB, 255-dB)
-				}
+				kR = uint32(max(min(int(dR)+int(sR)-128, 255), 0))
+				kG = uint32(max(min(int(dG)+int(sG)-128, 255), 0))
+				kB = uint32(max(min(int(dB)+int(sB)-128, 255), 0))
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -1864,20 +1830,2486 @@ func BlendOverlay(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGB
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if dR < 128 {
-					kR = 2 * md255(sR, dR)
-				} else {
-					kR = 255 - 2*md255(255-sR, 255-dR)
-				}
-				if dG < 128 {
-					kG = 2 * md255(sG, dG)
-				} else {
-					kG = 255 - 2*md255(255-sG, 255-dG)
-				}
-				if dB < 128 {
+				kR = uint32(max(min(int(dR)+int(sR)-128, 255), 0))
+				kG = uint32(max(min(int(dG)+int(sG)-128, 255), 0))
+				kB = uint32(max(min(int(dB)+int(sB)-128, 255), 0))
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendHardLight performs a 'HardLight' blend on RGBA images.
+// Logic: if Cs < 0.5 { Cr = 2 * Cs * Cd } else { Cr = 1 - 2 * (1 - Cs) * (1 - Cd) }
+func BlendHardLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR < 128 {
+					kR = 2 * md255(sR, dR)
+				} else {
+					kR = 255 - 2*md255(255-sR, 255-dR)
+				}
+				if sG < 128 {
+					kG = 2 * md255(sG, dG)
+				} else {
+					kG = 255 - 2*md255(255-sG, 255-dG)
+				}
+				if sB < 128 {
+					kB = 2 * md255(sB, dB)
+				} else {
+					kB = 255 - 2*md255(255-sB, 255-dB)
+				}
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR < 128 {
+					kR = 2 * md255(sR, dR)
+				} else {
+					kR = 255 - 2*md255(255-sR, 255-dR)
+				}
+				if sG < 128 {
+					kG = 2 * md255(sG, dG)
+				} else {
+					kG = 255 - 2*md255(255-sG, 255-dG)
+				}
+				if sB < 128 {
 					kB = 2 * md255(sB, dB)
 				} else {
-					kB = 255 - 2*md255(255-sB, 255-dB)
+					kB = 255 - 2*md255(255-sB, 255-dB)
+				}
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendHardMix performs a 'HardMix' blend on RGBA images.
+// Logic: if Cs + Cd < 1 { Cr = 0 } else { Cr = 1 }
+func BlendHardMix(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR+dR < 255 {
+					kR = 0
+				} else {
+					kR = 255
+				}
+				if sG+dG < 255 {
+					kG = 0
+				} else {
+					kG = 255
+				}
+				if sB+dB < 255 {
+					kB = 0
+				} else {
+					kB = 255
+				}
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR+dR < 255 {
+					kR = 0
+				} else {
+					kR = 255
+				}
+				if sG+dG < 255 {
+					kG = 0
+				} else {
+					kG = 255
+				}
+				if sB+dB < 255 {
+					kB = 0
+				} else {
+					kB = 255
+				}
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendHeat performs a 'Heat' blend on RGBA images.
+// Logic: Cr = 1 - (1 - Cs)^2 / Cd
+func BlendHeat(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if dR == 0 {
+					kR = 0
+				} else {
+					kR = 255 - min(((255-sR)*(255-sR)+dR/2)/dR, 255)
+				}
+				if dG == 0 {
+					kG = 0
+				} else {
+					kG = 255 - min(((255-sG)*(255-sG)+dG/2)/dG, 255)
+				}
+				if dB == 0 {
+					kB = 0
+				} else {
+					kB = 255 - min(((255-sB)*(255-sB)+dB/2)/dB, 255)
+				}
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if dR == 0 {
+					kR = 0
+				} else {
+					kR = 255 - min(((255-sR)*(255-sR)+dR/2)/dR, 255)
+				}
+				if dG == 0 {
+					kG = 0
+				} else {
+					kG = 255 - min(((255-sG)*(255-sG)+dG/2)/dG, 255)
+				}
+				if dB == 0 {
+					kB = 0
+				} else {
+					kB = 255 - min(((255-sB)*(255-sB)+dB/2)/dB, 255)
+				}
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendLUT performs a 'LUT' blend on RGBA images.
+// Logic: Cr = lut[Cs][Cd], a blend function precomputed for every pair of 8-bit values
+func BlendLUT(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing, lut *[256][256]uint8) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = uint32(lut[sR][dR])
+				kG = uint32(lut[sG][dG])
+				kB = uint32(lut[sB][dB])
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = uint32(lut[sR][dR])
+				kG = uint32(lut[sG][dG])
+				kB = uint32(lut[sB][dB])
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendLighten performs a 'Lighten' blend on RGBA images.
+// Logic: Cr = max(Cs, Cd)
+func BlendLighten(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = max(sR, dR)
+				kG = max(sG, dG)
+				kB = max(sB, dB)
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = max(sR, dR)
+				kG = max(sG, dG)
+				kB = max(sB, dB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendLighterColor performs a 'LighterColor' blend on RGBA images.
+// Logic: if Lum(Cs) > Lum(Cd) { Cr = Cs } else { Cr = Cd }, where Lum(C) = 0.3*R + 0.59*G + 0.11*B
+func BlendLighterColor(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if lum(sR, sG, sB) > lum(dR, dG, dB) {
+					kR, kG, kB = sR, sG, sB
+				} else {
+					kR, kG, kB = dR, dG, dB
+				}
+
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			if compositing == internal.CompositeAll {
+				// Fast path for Source Over using direct premultiplied equation
+				oA = sA + md255(dA, 255-sA)
+				// region BLEND-SPECIFIC EQUATION LOGIC
+				if lum(sR, sG, sB)*dA > lum(dR, dG, dB)*sA {
+					oRp, oGp, oBp = sR+md255(dR, 255-sA), sG+md255(dG, 255-sA), sB+md255(dB, 255-sA)
+				} else {
+					oRp, oGp, oBp = md255(sR, 255-dA)+dR, md255(sG, 255-dA)+dG, md255(sB, 255-dA)+dB
+				}
+				// endregion BLEND-SPECIFIC EQUATION LOGIC
+			} else {
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if lum(sR, sG, sB) > lum(dR, dG, dB) {
+					kR, kG, kB = sR, sG, sB
+				} else {
+					kR, kG, kB = dR, dG, dB
+				}
+
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendLinearBurn performs a 'LinearBurn' blend on RGBA images.
+// Logic: Cr = Cs + Cd - 1
+func BlendLinearBurn(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = sR + dR - 255
+				if kR > 255 {
+					kR = 0
+				}
+				kG = sG + dG - 255
+				if kG > 255 {
+					kG = 0
+				}
+				kB = sB + dB - 255
+				if kB > 255 {
+					kB = 0
+				}
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = sR + dR - 255
+				if kR > 255 {
+					kR = 0
+				}
+				kG = sG + dG - 255
+				if kG > 255 {
+					kG = 0
+				}
+				kB = sB + dB - 255
+				if kB > 255 {
+					kB = 0
+				}
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendLinearDodge performs a 'LinearDodge' blend on RGBA images.
+// Logic: Cr = Cs + Cd
+func BlendLinearDodge(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = min(sR+dR, 255)
+				kG = min(sG+dG, 255)
+				kB = min(sB+dB, 255)
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = min(sR+dR, 255)
+				kG = min(sG+dG, 255)
+				kB = min(sB+dB, 255)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendLinearLight performs a 'LinearLight' blend on RGBA images.
+// Logic: Cr = Cd + 2*Cs - 1
+func BlendLinearLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = dR + 2*sR - 255
+				if kR > 510 {
+					kR = 0
+				} else if kR > 255 {
+					kR = 255
+				}
+				kG = dG + 2*sG - 255
+				if kG > 510 {
+					kG = 0
+				} else if kG > 255 {
+					kG = 255
+				}
+				kB = dB + 2*sB - 255
+				if kB > 510 {
+					kB = 0
+				} else if kB > 255 {
+					kB = 255
+				}
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = dR + 2*sR - 255
+				if kR > 510 {
+					kR = 0
+				} else if kR > 255 {
+					kR = 255
+				}
+				kG = dG + 2*sG - 255
+				if kG > 510 {
+					kG = 0
+				} else if kG > 255 {
+					kG = 255
+				}
+				kB = dB + 2*sB - 255
+				if kB > 510 {
+					kB = 0
+				} else if kB > 255 {
+					kB = 255
+				}
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendMultiply performs a 'Multiply' blend on RGBA images.
+// Logic: Cr = Cs * Cd
+func BlendMultiply(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = md255(sR, dR)
+				kG = md255(sG, dG)
+				kB = md255(sB, dB)
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			if compositing == internal.CompositeAll {
+				// Fast path for Source Over using direct premultiplied equation
+				oA = sA + md255(dA, 255-sA)
+				// region BLEND-SPECIFIC EQUATION LOGIC
+				oRp = md255(dR, 255-sA) + md255(sR, 255-dA) + md255(sR, dR)
+				oGp = md255(dG, 255-sA) + md255(sG, 255-dA) + md255(sG, dG)
+				oBp = md255(dB, 255-sA) + md255(sB, 255-dA) + md255(sB, dB)
+				// endregion BLEND-SPECIFIC EQUATION LOGIC
+			} else {
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = md255(sR, dR)
+				kG = md255(sG, dG)
+				kB = md255(sB, dB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendNegation performs a 'Negation' blend on RGBA images.
+// Logic: Cr = 1 - abs(1 - Cs - Cd)
+func BlendNegation(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR+dR > 255 {
+					kR = 510 - sR - dR
+				} else {
+					kR = sR + dR
+				}
+				if sG+dG > 255 {
+					kG = 510 - sG - dG
+				} else {
+					kG = sG + dG
+				}
+				if sB+dB > 255 {
+					kB = 510 - sB - dB
+				} else {
+					kB = sB + dB
+				}
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR+dR > 255 {
+					kR = 510 - sR - dR
+				} else {
+					kR = sR + dR
+				}
+				if sG+dG > 255 {
+					kG = 510 - sG - dG
+				} else {
+					kG = sG + dG
+				}
+				if sB+dB > 255 {
+					kB = 510 - sB - dB
+				} else {
+					kB = sB + dB
+				}
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendNormal performs a 'Normal' blend on RGBA images.
+// Logic: Cr = Cs
+func BlendNormal(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = sR
+				kG = sG
+				kB = sB
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			if compositing == internal.CompositeAll {
+				// Fast path for Source Over using direct premultiplied equation
+				oA = sA + md255(dA, 255-sA)
+				// region BLEND-SPECIFIC EQUATION LOGIC
+				oRp = sR + md255(dR, 255-sA)
+				oGp = sG + md255(dG, 255-sA)
+				oBp = sB + md255(dB, 255-sA)
+				// endregion BLEND-SPECIFIC EQUATION LOGIC
+			} else {
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = sR
+				kG = sG
+				kB = sB
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendOverlay performs a 'Overlay' blend on RGBA images.
+// Logic: if Cd < 0.5 { Cr = 2 * Cs * Cd } else { Cr = 1 - 2 * (1 - Cs) * (1 - Cd) }
+func BlendOverlay(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if dR < 128 {
+					kR = 2 * md255(sR, dR)
+				} else {
+					kR = 255 - 2*md255(255-sR, 255-dR)
+				}
+				if dG < 128 {
+					kG = 2 * md255(sG, dG)
+				} else {
+					kG = 255 - 2*md255(255-sG, 255-dG)
+				}
+				if dB < 128 {
+					kB = 2 * md255(sB, dB)
+				} else {
+					kB = 255 - 2*md255(255-sB, 255-dB)
+				}
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if dR < 128 {
+					kR = 2 * md255(sR, dR)
+				} else {
+					kR = 255 - 2*md255(255-sR, 255-dR)
+				}
+				if dG < 128 {
+					kG = 2 * md255(sG, dG)
+				} else {
+					kG = 255 - 2*md255(255-sG, 255-dG)
+				}
+				if dB < 128 {
+					kB = 2 * md255(sB, dB)
+				} else {
+					kB = 255 - 2*md255(255-sB, 255-dB)
+				}
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendPhoenix performs a 'Phoenix' blend on RGBA images.
+// Logic: Cr = min(Cs, Cd) - max(Cs, Cd) + 1
+func BlendPhoenix(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = min(sR, dR) + 255 - max(sR, dR)
+				kG = min(sG, dG) + 255 - max(sG, dG)
+				kB = min(sB, dB) + 255 - max(sB, dB)
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = min(sR, dR) + 255 - max(sR, dR)
+				kG = min(sG, dG) + 255 - max(sG, dG)
+				kB = min(sB, dB) + 255 - max(sB, dB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendPinLight performs a 'PinLight' blend on RGBA images.
+// Logic: if Cs < 0.5 { Cr = min(Cd, 2 * Cs) } else { Cr = max(Cd, 2 * Cs - 1) }
+func BlendPinLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR < 128 {
+					kR = min(dR, 2*sR)
+				} else {
+					kR = max(dR, 2*sR-255)
+				}
+				if sG < 128 {
+					kG = min(dG, 2*sG)
+				} else {
+					kG = max(dG, 2*sG-255)
+				}
+				if sB < 128 {
+					kB = min(dB, 2*sB)
+				} else {
+					kB = max(dB, 2*sB-255)
+				}
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR < 128 {
+					kR = min(dR, 2*sR)
+				} else {
+					kR = max(dR, 2*sR-255)
+				}
+				if sG < 128 {
+					kG = min(dG, 2*sG)
+				} else {
+					kG = max(dG, 2*sG-255)
+				}
+				if sB < 128 {
+					kB = min(dB, 2*sB)
+				} else {
+					kB = max(dB, 2*sB-255)
+				}
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendReflect performs a 'Reflect' blend on RGBA images.
+// Logic: Cr = Cd^2 / (1 - Cs)
+func BlendReflect(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR == 255 {
+					kR = 255
+				} else {
+					kR = min((dR*dR+(255-sR)/2)/(255-sR), 255)
+				}
+				if sG == 255 {
+					kG = 255
+				} else {
+					kG = min((dG*dG+(255-sG)/2)/(255-sG), 255)
+				}
+				if sB == 255 {
+					kB = 255
+				} else {
+					kB = min((dB*dB+(255-sB)/2)/(255-sB), 255)
+				}
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR == 255 {
+					kR = 255
+				} else {
+					kR = min((dR*dR+(255-sR)/2)/(255-sR), 255)
+				}
+				if sG == 255 {
+					kG = 255
+				} else {
+					kG = min((dG*dG+(255-sG)/2)/(255-sG), 255)
+				}
+				if sB == 255 {
+					kB = 255
+				} else {
+					kB = min((dB*dB+(255-sB)/2)/(255-sB), 255)
+				}
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendScreen performs a 'Screen' blend on RGBA images.
+// Logic: Cr = 1 - (1 - Cs) * (1 - Cd)
+func BlendScreen(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = 255 - md255(255-sR, 255-dR)
+				kG = 255 - md255(255-sG, 255-dG)
+				kB = 255 - md255(255-sB, 255-dB)
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			if compositing == internal.CompositeAll {
+				// Fast path for Source Over using direct premultiplied equation
+				oA = sA + md255(dA, 255-sA)
+				// region BLEND-SPECIFIC EQUATION LOGIC
+				oRp = sR + dR - md255(sR, dR)
+				oGp = sG + dG - md255(sG, dG)
+				oBp = sB + dB - md255(sB, dB)
+				// endregion BLEND-SPECIFIC EQUATION LOGIC
+			} else {
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				kR = 255 - md255(255-sR, 255-dR)
+				kG = 255 - md255(255-sG, 255-dG)
+				kB = 255 - md255(255-sB, 255-dB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
+
+// BlendSoftLight performs a 'SoftLight' blend on RGBA images.
+// Logic: if Cs < 0.5 { Cr = Cd - (1 - 2*Cs) * Cd * (1 - Cd) } else { Cr = Cd + (2*Cs - 1) * (sqrt(Cd) - Cd) }
+func BlendSoftLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR < 128 {
+					kR = dR - div255(div255((255-2*sR)*dR*(255-dR)))
+				} else {
+					kR = dR + md255(2*sR-255, sqrt(dR)-dR)
+				}
+				if sG < 128 {
+					kG = dG - div255(div255((255-2*sG)*dG*(255-dG)))
+				} else {
+					kG = dG + md255(2*sG-255, sqrt(dG)-dG)
+				}
+				if sB < 128 {
+					kB = dB - div255(div255((255-2*sB)*dB*(255-dB)))
+				} else {
+					kB = dB + md255(2*sB-255, sqrt(dB)-dB)
+				}
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				if sR < 128 {
+					kR = dR - div255(div255((255-2*sR)*dR*(255-dR)))
+				} else {
+					kR = dR + md255(2*sR-255, sqrt(dR)-dR)
+				}
+				if sG < 128 {
+					kG = dG - div255(div255((255-2*sG)*dG*(255-dG)))
+				} else {
+					kG = dG + md255(2*sG-255, sqrt(dG)-dG)
+				}
+				if sB < 128 {
+					kB = dB - div255(div255((255-2*sB)*dB*(255-dB)))
+				} else {
+					kB = dB + md255(2*sB-255, sqrt(dB)-dB)
 				}
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
@@ -1939,9 +4371,9 @@ func BlendOverlay(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGB
 	})
 }
 
-// BlendPinLight performs a 'PinLight' blend on RGBA images.
-// Logic: if Cs < 0.5 { Cr = min(Cd, 2 * Cs) } else { Cr = max(Cd, 2 * Cs - 1) }
-func BlendPinLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendSoftLightIllusions performs a 'SoftLightIllusions' blend on RGBA images.
+// Logic: Cr = Cd^(2^(2 * (0.5 - Cs)))
+func BlendSoftLightIllusions(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -1973,21 +4405,9 @@ func BlendPinLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.RG
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR < 128 {
-					kR = min(dR, 2*sR)
-				} else {
-					kR = max(dR, 2*sR-255)
-				}
-				if sG < 128 {
-					kG = min(dG, 2*sG)
-				} else {
-					kG = max(dG, 2*sG-255)
-				}
-				if sB < 128 {
-					kB = min(dB, 2*sB)
-				} else {
-					kB = max(dB, 2*sB-255)
-				}
+				kR = softLightIllusions(sR, dR)
+				kG = softLightIllusions(sG, dG)
+				kB = softLightIllusions(sB, dB)
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -2013,21 +4433,9 @@ func BlendPinLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.RG
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				if sR < 128 {
-					kR = min(dR, 2*sR)
-				} else {
-					kR = max(dR, 2*sR-255)
-				}
-				if sG < 128 {
-					kG = min(dG, 2*sG)
-				} else {
-					kG = max(dG, 2*sG-255)
-				}
-				if sB < 128 {
-					kB = min(dB, 2*sB)
-				} else {
-					kB = max(dB, 2*sB-255)
-				}
+				kR = softLightIllusions(sR, dR)
+				kG = softLightIllusions(sG, dG)
+				kB = softLightIllusions(sB, dB)
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
 				// premultiplied compositing color results
@@ -2088,9 +4496,9 @@ func BlendPinLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.RG
 	})
 }
 
-// BlendScreen performs a 'Screen' blend on RGBA images.
-// Logic: Cr = 1 - (1 - Cs) * (1 - Cd)
-func BlendScreen(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendSoftLightPegtop performs a 'SoftLightPegtop' blend on RGBA images.
+// Logic: Cr = (1 - 2*Cs) * Cd^2 + 2 * Cs * Cd
+func BlendSoftLightPegtop(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -2122,9 +4530,9 @@ func BlendScreen(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = 255 - md255(255-sR, 255-dR)
-				kG = 255 - md255(255-sG, 255-dG)
-				kB = 255 - md255(255-sB, 255-dB)
+				kR = (dR*dR*(255-2*sR) + 2*sR*dR*255 + 32512) / 65025
+				kG = (dG*dG*(255-2*sG) + 2*sG*dG*255 + 32512) / 65025
+				kB = (dB*dB*(255-2*sB) + 2*sB*dB*255 + 32512) / 65025
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
 				out[i+2] = uint8(kB)
@@ -2135,15 +4543,7 @@ func BlendScreen(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA
 
 			// final output colors & alpha
 			var oRp, oGp, oBp, oA uint32
-			if compositing == internal.CompositeAll {
-				// Fast path for Source Over using direct premultiplied equation
-				oA = sA + md255(dA, 255-sA)
-				// region BLEND-SPECIFIC EQUATION LOGIC
-				oRp = sR + dR - md255(sR, dR)
-				oGp = sG + dG - md255(sG, dG)
-				oBp = sB + dB - md255(sB, dB)
-				// endregion BLEND-SPECIFIC EQUATION LOGIC
-			} else {
+			{
 				// Fallback path for other compositions, or for blend modes without a direct equation.
 				// This path is accurate but slower as it must un-premultiply.
 				sR = unpremultiply(sR, sA)
@@ -2158,9 +4558,9 @@ func BlendScreen(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA
 				var kR, kG, kB uint32
 
 				// region BLEND-SPECIFIC KERNEL LOGIC
-				kR = 255 - md255(255-sR, 255-dR)
-				kG = 255 - md255(255-sG, 255-dG)
-				kB = 255 - md255(255-sB, 255-dB)
+				kR = (dR*dR*(255-2*sR) + 2*sR*dR*255 + 32512) / 65025
+				kG = (dG*dG*(255-2*sG) + 2*sG*dG*255 + 32512) / 65025
+				kB = (dB*dB*(255-2*sB) + 2*sB*dB*255 + 32512) / 65025
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
 				// premultiplied compositing color results
@@ -2170,6 +4570,19 @@ func BlendScreen(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA
 				var oRu, oGu, oBu uint32
 
 				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
 				case internal.CompositeBlendAndSrc:
 					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
 					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
@@ -2208,9 +4621,9 @@ func BlendScreen(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA
 	})
 }
 
-// BlendSoftLight performs a 'SoftLight' blend on RGBA images.
-// Logic: if Cs < 0.5 { Cr = Cd - (1 - 2*Cs) * Cd * (1 - Cd) } else { Cr = Cd + (2*Cs - 1) * (sqrt(Cd) - Cd) }
-func BlendSoftLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+// BlendSoftLightW3C performs a 'SoftLightW3C' blend on RGBA images.
+// Logic: if Cs <= 0.5 { Cr = Cd - (1 - 2*Cs) * Cd * (1 - Cd) } else { Cr = Cd + (2*Cs - 1) * (D(Cd) - Cd) }, where D(x) = ((16*x - 12)*x + 4)*x if x <= 0.25 else sqrt(x)
+func BlendSoftLightW3C(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
 	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
 		for i := 0; i < len(src); i += 4 {
 			sA := uint32(src[i+3])
@@ -2245,17 +4658,17 @@ func BlendSoftLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 				if sR < 128 {
 					kR = dR - div255(div255((255-2*sR)*dR*(255-dR)))
 				} else {
-					kR = dR + md255(2*sR-255, sqrt(dR)-dR)
+					kR = dR + md255(2*sR-255, softLightD(dR)-dR)
 				}
 				if sG < 128 {
 					kG = dG - div255(div255((255-2*sG)*dG*(255-dG)))
 				} else {
-					kG = dG + md255(2*sG-255, sqrt(dG)-dG)
+					kG = dG + md255(2*sG-255, softLightD(dG)-dG)
 				}
 				if sB < 128 {
 					kB = dB - div255(div255((255-2*sB)*dB*(255-dB)))
 				} else {
-					kB = dB + md255(2*sB-255, sqrt(dB)-dB)
+					kB = dB + md255(2*sB-255, softLightD(dB)-dB)
 				}
 				out[i] = uint8(kR)
 				out[i+1] = uint8(kG)
@@ -2285,17 +4698,17 @@ func BlendSoftLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.R
 				if sR < 128 {
 					kR = dR - div255(div255((255-2*sR)*dR*(255-dR)))
 				} else {
-					kR = dR + md255(2*sR-255, sqrt(dR)-dR)
+					kR = dR + md255(2*sR-255, softLightD(dR)-dR)
 				}
 				if sG < 128 {
 					kG = dG - div255(div255((255-2*sG)*dG*(255-dG)))
 				} else {
-					kG = dG + md255(2*sG-255, sqrt(dG)-dG)
+					kG = dG + md255(2*sG-255, softLightD(dG)-dG)
 				}
 				if sB < 128 {
 					kB = dB - div255(div255((255-2*sB)*dB*(255-dB)))
 				} else {
-					kB = dB + md255(2*sB-255, sqrt(dB)-dB)
+					kB = dB + md255(2*sB-255, softLightD(dB)-dB)
 				}
 				// endregion BLEND-SPECIFIC KERNEL LOGIC
 
@@ -2648,3 +5061,182 @@ func BlendVividLight(pixIter core.PixelIterator, calc core.PixCalculator[*image.
 		}
 	})
 }
+
+// BlendVividLightGIMP performs a 'VividLightGIMP' blend on RGBA images.
+// Logic: if Cs <= 0.5 { Cr = 1 - (1 - Cd) / (2 * Cs) } else { Cr = Cd / (2 * (1 - Cs)) }, where x / 0 = 0
+func BlendVividLightGIMP(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing) *image.RGBA {
+	return core.Iterate(pixIter, calc, func(dst, src, out []uint8) {
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			dA := uint32(dst[i+3])
+
+			if sA == 0 {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			if dA == 0 {
+				if compositing&internal.CompositeBlendAndSrc != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], src[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+
+			// pre-multiplied source & destination colors
+			sR, sG, sB := uint32(src[i]), uint32(src[i+1]), uint32(src[i+2])
+			dR, dG, dB := uint32(dst[i]), uint32(dst[i+1]), uint32(dst[i+2])
+
+			if (sA & dA) == 255 {
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				switch {
+				case sR == 0:
+					kR = 255
+				case sR == 255:
+					kR = 0
+				case sR < 128:
+					kR = 255 - min(((255-dR)*255+sR)/(2*sR), 255)
+				default:
+					kR = min(((dR*255)+(255-sR))/(510-2*sR), 255)
+				}
+				switch {
+				case sG == 0:
+					kG = 255
+				case sG == 255:
+					kG = 0
+				case sG < 128:
+					kG = 255 - min(((255-dG)*255+sG)/(2*sG), 255)
+				default:
+					kG = min(((dG*255)+(255-sG))/(510-2*sG), 255)
+				}
+				switch {
+				case sB == 0:
+					kB = 255
+				case sB == 255:
+					kB = 0
+				case sB < 128:
+					kB = 255 - min(((255-dB)*255+sB)/(2*sB), 255)
+				default:
+					kB = min(((dB*255)+(255-sB))/(510-2*sB), 255)
+				}
+				out[i] = uint8(kR)
+				out[i+1] = uint8(kG)
+				out[i+2] = uint8(kB)
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+				out[i+3] = uint8(255)
+				continue
+			}
+
+			// final output colors & alpha
+			var oRp, oGp, oBp, oA uint32
+			{
+				// Fallback path for other compositions, or for blend modes without a direct equation.
+				// This path is accurate but slower as it must un-premultiply.
+				sR = unpremultiply(sR, sA)
+				sG = unpremultiply(sG, sA)
+				sB = unpremultiply(sB, sA)
+
+				dR = unpremultiply(dR, dA)
+				dG = unpremultiply(dG, dA)
+				dB = unpremultiply(dB, dA)
+
+				// un-premultiplied kernel color results
+				var kR, kG, kB uint32
+
+				// region BLEND-SPECIFIC KERNEL LOGIC
+				switch {
+				case sR == 0:
+					kR = 255
+				case sR == 255:
+					kR = 0
+				case sR < 128:
+					kR = 255 - min(((255-dR)*255+sR)/(2*sR), 255)
+				default:
+					kR = min(((dR*255)+(255-sR))/(510-2*sR), 255)
+				}
+				switch {
+				case sG == 0:
+					kG = 255
+				case sG == 255:
+					kG = 0
+				case sG < 128:
+					kG = 255 - min(((255-dG)*255+sG)/(2*sG), 255)
+				default:
+					kG = min(((dG*255)+(255-sG))/(510-2*sG), 255)
+				}
+				switch {
+				case sB == 0:
+					kB = 255
+				case sB == 255:
+					kB = 0
+				case sB < 128:
+					kB = 255 - min(((255-dB)*255+sB)/(2*sB), 255)
+				default:
+					kB = min(((dB*255)+(255-sB))/(510-2*sB), 255)
+				}
+				// endregion BLEND-SPECIFIC KERNEL LOGIC
+
+				// premultiplied compositing color results
+				var cRp, cGp, cBp, cA uint32
+
+				// un-premultiplied output colors
+				var oRu, oGu, oBu uint32
+
+				switch compositing {
+				case internal.CompositeAll:
+					invSA, invDA := 255-sA, 255-dA
+					term1, term2, term3 := md255(sA, invDA), md255(dA, invSA), md255(sA, dA)
+
+					cRp = md255(term1, sR) + md255(term2, dR) + md255(term3, kR)
+					cGp = md255(term1, sG) + md255(term2, dG) + md255(term3, kG)
+					cBp = md255(term1, sB) + md255(term2, dB) + md255(term3, kB)
+					cA = sA + term2
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = cA
+				case internal.CompositeBlendAndSrc:
+					cRp = md255(md255(sA, 255-dA), sR) + md255(dA, kR)
+					cGp = md255(md255(sA, 255-dA), sG) + md255(dA, kG)
+					cBp = md255(md255(sA, 255-dA), sB) + md255(dA, kB)
+					cA = dA + md255(sA, 255-dA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = sA
+				case internal.CompositeBlendAndDst:
+					cRp = md255(md255(dA, 255-sA), dR) + md255(sA, kR)
+					cGp = md255(md255(dA, 255-sA), dG) + md255(sA, kG)
+					cBp = md255(md255(dA, 255-sA), dB) + md255(sA, kB)
+					cA = sA + md255(dA, 255-sA)
+
+					oRu = (cRp*255 + cA/2) / cA
+					oGu = (cGp*255 + cA/2) / cA
+					oBu = (cBp*255 + cA/2) / cA
+					oA = dA
+				case internal.CompositeBlendOnly:
+					oRu, oGu, oBu = kR, kG, kB
+					oA = md255(sA, dA)
+				}
+
+				oRp = md255(oRu, oA)
+				oGp = md255(oGu, oA)
+				oBp = md255(oBu, oA)
+			}
+
+			out[i] = uint8(oRp)
+			out[i+1] = uint8(oGp)
+			out[i+2] = uint8(oBp)
+			out[i+3] = uint8(oA)
+		}
+	})
+}
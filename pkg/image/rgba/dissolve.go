@@ -0,0 +1,52 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package rgba
+
+import (
+	"image"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+// BlendDissolve performs a "Dissolve" blend on RGBA images.
+// Each source pixel becomes either fully opaque or fully transparent, with a probability of
+// being opaque equal to its alpha, and is then blended as in Normal. The pattern is a function
+// of the seed and the destination coordinates only, so it does not depend on the PixelIterator.
+func BlendDissolve(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA], compositing internal.BlendCompositing, seed uint64) *image.RGBA {
+	rect := calc.Rect()
+	core.IterateRows(pixIter, rect.Dy(), func(row int) {
+		dst, src, out := calc.Calculate(row)
+		y := rect.Min.Y + row
+		for i := 0; i < len(src); i += 4 {
+			sA := uint32(src[i+3])
+			if !internal.Dissolve(seed, rect.Min.X+i/4, y, sA) {
+				if compositing&internal.CompositeBlendAndDst != 0 {
+					out[i], out[i+1], out[i+2], out[i+3] = dst[i], dst[i+1], dst[i+2], dst[i+3]
+				} else {
+					out[i], out[i+1], out[i+2], out[i+3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			// An opaque source replaces the color; only the alpha depends on the compositing.
+			if compositing&internal.CompositeBlendAndSrc != 0 || dst[i+3] == 255 {
+				if sA == 255 {
+					out[i], out[i+1], out[i+2], out[i+3] = src[i], src[i+1], src[i+2], 255
+					continue
+				}
+				out[i] = uint8(unpremultiply(uint32(src[i]), sA))
+				out[i+1] = uint8(unpremultiply(uint32(src[i+1]), sA))
+				out[i+2] = uint8(unpremultiply(uint32(src[i+2]), sA))
+				out[i+3] = 255
+				continue
+			}
+			oA := uint32(dst[i+3])
+			out[i] = uint8(md255(unpremultiply(uint32(src[i]), sA), oA))
+			out[i+1] = uint8(md255(unpremultiply(uint32(src[i+1]), sA), oA))
+			out[i+2] = uint8(md255(unpremultiply(uint32(src[i+2]), sA), oA))
+			out[i+3] = uint8(oA)
+		}
+	})
+	return calc.Result()
+}
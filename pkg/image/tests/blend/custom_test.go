@@ -0,0 +1,63 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package blend_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/image/nrgba"
+	"github.com/blazeroni/magpie/pkg/image/rgba"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+// TestBlendCustom checks that a custom blend matches the generated kernel of the same mode
+// for every compositing.
+func TestBlendCustom(t *testing.T) {
+	custom, err := op.CompileCustomBlend("$S*$D/255")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := image.Rect(0, 0, 16, 16)
+	dst, src := image.NewNRGBA(r), image.NewNRGBA(r)
+	for i := range dst.Pix {
+		dst.Pix[i] = uint8(i * 7)
+		src.Pix[i] = uint8(i * 13)
+	}
+	pdst, psrc := image.NewRGBA(r), image.NewRGBA(r)
+	for y := range 16 {
+		for x := range 16 {
+			pdst.Set(x, y, dst.At(x, y))
+			psrc.Set(x, y, src.At(x, y))
+		}
+	}
+
+	pixIter := core.NewSerialPixelIterator()
+	for _, compositing := range []op.BlendCompositing{op.CompositeAll, op.CompositeBlendAndSrc, op.CompositeBlendAndDst, op.CompositeBlendOnly} {
+		o := op.CustomBlendOp{Blend: custom, Compositing: compositing}
+
+		got, want := image.NewNRGBA(r), image.NewNRGBA(r)
+		o.ApplyNRGBA(pixIter, core.NewPixCalculatorNRGBA(dst, r, src, r.Min, got, r.Min))
+		nrgba.BlendMultiply(pixIter, core.NewPixCalculatorNRGBA(dst, r, src, r.Min, want, r.Min), compositing)
+		for i := range got.Pix {
+			if got.Pix[i] != want.Pix[i] {
+				t.Fatalf("NRGBA/%s: pixel %d = %v, want %v", compositeName(compositing), i/4, got.Pix[i/4*4:i/4*4+4], want.Pix[i/4*4:i/4*4+4])
+			}
+		}
+
+		// The generated RGBA kernel has a premultiplied fast path for CompositeAll, so allow
+		// for its rounding.
+		pgot, pwant := image.NewRGBA(r), image.NewRGBA(r)
+		o.ApplyRGBA(pixIter, core.NewPixCalculatorRGBA(pdst, r, psrc, r.Min, pgot, r.Min))
+		rgba.BlendMultiply(pixIter, core.NewPixCalculatorRGBA(pdst, r, psrc, r.Min, pwant, r.Min), compositing)
+		for y := range 16 {
+			for x := range 16 {
+				if g, w := pgot.RGBAAt(x, y), pwant.RGBAAt(x, y); !colorsAlmostEqual(g, w, 2) {
+					t.Fatalf("RGBA/%s: (%d, %d) = %v, want %v", compositeName(compositing), x, y, g, w)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package blend_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+// dissolve blends a uniform src color onto a uniform dst color over the region r of images
+// anchored at the origin, returning the result as NRGBA whatever the model.
+func dissolve(t *testing.T, rgbaModel bool, pixIter core.PixelIterator, o op.BlendOp, r image.Rectangle, dstC, srcC color.NRGBA) *image.NRGBA {
+	t.Helper()
+	bounds := image.Rect(0, 0, r.Max.X, r.Max.Y)
+	res := image.NewNRGBA(bounds)
+	if rgbaModel {
+		dst, src, out := image.NewRGBA(bounds), image.NewRGBA(bounds), image.NewRGBA(bounds)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				dst.SetRGBA(x, y, toRGBA(dstC))
+				src.SetRGBA(x, y, toRGBA(srcC))
+			}
+		}
+		o.ApplyRGBA(pixIter, core.NewPixCalculatorRGBA(dst, r, src, r.Min, out, r.Min))
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				res.Set(x, y, out.RGBAAt(x, y))
+			}
+		}
+		return res
+	}
+	dst, src := image.NewNRGBA(bounds), image.NewNRGBA(bounds)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dst.SetNRGBA(x, y, dstC)
+			src.SetNRGBA(x, y, srcC)
+		}
+	}
+	o.ApplyNRGBA(pixIter, core.NewPixCalculatorNRGBA(dst, r, src, r.Min, res, r.Min))
+	return res
+}
+
+func TestBlendDissolve(t *testing.T) {
+	r := image.Rect(10, 20, 74, 84)
+	dstC, srcC := c(0x40_80_c0_ff), c(0xc0_40_80_80)
+	opaqueSrc := c(0xc0_40_80_ff)
+
+	for _, model := range []struct {
+		name string
+		rgba bool
+	}{{"NRGBA", false}, {"RGBA", true}} {
+		t.Run(model.name, func(t *testing.T) {
+			o := op.BlendOp{Mode: op.Dissolve, Compositing: op.CompositeAll, Seed: 7}
+			serial := dissolve(t, model.rgba, core.NewSerialPixelIterator(), o, r, dstC, srcC)
+			parallel := dissolve(t, model.rgba, core.NewParallelPixelIterator(4), o, r, dstC, srcC)
+
+			var covered int
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				for x := r.Min.X; x < r.Max.X; x++ {
+					got := serial.NRGBAAt(x, y)
+					if p := parallel.NRGBAAt(x, y); p != got {
+						t.Fatalf("(%d, %d): serial %v, parallel %v", x, y, got, p)
+					}
+					switch {
+					case colorsAlmostEqual(got, opaqueSrc, 1):
+						covered++
+					case got != dstC:
+						t.Fatalf("(%d, %d) = %v, want the opaque source or the destination", x, y, got)
+					}
+				}
+			}
+			// Alpha 0x80 covers about half of the 4096 pixels.
+			if covered < 1800 || covered > 2300 {
+				t.Errorf("%d pixels covered, want about 2048", covered)
+			}
+
+			o.Seed = 8
+			other := dissolve(t, model.rgba, core.NewSerialPixelIterator(), o, r, dstC, srcC)
+			if string(other.Pix) == string(serial.Pix) {
+				t.Error("a different seed gives the same pattern")
+			}
+		})
+	}
+}
+
+func TestBlendDissolveCompositing(t *testing.T) {
+	r := image.Rect(0, 0, 1, 1)
+	tests := []struct {
+		name        string
+		compositing op.BlendCompositing
+		dst, src    color.NRGBA
+		want        color.NRGBA
+	}{
+		{"OpaqueSrc/All", op.CompositeAll, c(0x40_80_c0_80), c(0xc0_40_80_ff), c(0xc0_40_80_ff)},
+		{"OpaqueSrc/BlendAndSrc", op.CompositeBlendAndSrc, c(0x40_80_c0_80), c(0xc0_40_80_ff), c(0xc0_40_80_ff)},
+		{"OpaqueSrc/BlendAndDst", op.CompositeBlendAndDst, c(0x40_80_c0_80), c(0xc0_40_80_ff), c(0xc0_40_80_80)},
+		{"OpaqueSrc/BlendOnly", op.CompositeBlendOnly, c(0x40_80_c0_80), c(0xc0_40_80_ff), c(0xc0_40_80_80)},
+		{"TransparentSrc/All", op.CompositeAll, c(0x40_80_c0_80), c(0), c(0x40_80_c0_80)},
+		{"TransparentSrc/BlendAndSrc", op.CompositeBlendAndSrc, c(0x40_80_c0_80), c(0), c(0)},
+		{"TransparentSrc/BlendAndDst", op.CompositeBlendAndDst, c(0x40_80_c0_80), c(0), c(0x40_80_c0_80)},
+		{"TransparentSrc/BlendOnly", op.CompositeBlendOnly, c(0x40_80_c0_80), c(0), c(0)},
+		{"TransparentDst/All", op.CompositeAll, c(0), c(0xc0_40_80_ff), c(0xc0_40_80_ff)},
+		{"TransparentDst/BlendOnly", op.CompositeBlendOnly, c(0), c(0xc0_40_80_ff), c(0)},
+	}
+	for _, tt := range tests {
+		for _, rgbaModel := range []bool{false, true} {
+			o := op.BlendOp{Mode: op.Dissolve, Compositing: tt.compositing}
+			got := dissolve(t, rgbaModel, core.NewSerialPixelIterator(), o, r, tt.dst, tt.src).NRGBAAt(0, 0)
+			if !colorsAlmostEqual(got, tt.want, 1) {
+				t.Errorf("%s (RGBA: %v) = %s, want %s", tt.name, rgbaModel, hexNRGBA(got), hexNRGBA(tt.want))
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package blend_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/image/nrgba"
+	"github.com/blazeroni/magpie/pkg/image/rgba"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+func TestBlendGeometricMean(t *testing.T) {
+	testCases := []blendTestCase{
+		{
+			colors: Opaque1,
+			compositing: map[op.BlendCompositing]color.NRGBA{
+				op.CompositeAll:         c(0x00_80_00_ff),
+				op.CompositeBlendAndSrc: c(0x00_80_00_ff),
+				op.CompositeBlendAndDst: c(0x00_80_00_ff),
+				op.CompositeBlendOnly:   c(0x00_80_00_ff),
+			},
+			tolerance: 0,
+		},
+		{
+			colors: Opaque2,
+			compositing: map[op.BlendCompositing]color.NRGBA{
+				op.CompositeAll:         c(0x6f_5b_9d_ff),
+				op.CompositeBlendAndSrc: c(0x6f_5b_9d_ff),
+				op.CompositeBlendAndDst: c(0x6f_5b_9d_ff),
+				op.CompositeBlendOnly:   c(0x6f_5b_9d_ff),
+			},
+			tolerance: 1,
+		},
+		{
+			colors: TransparentSrc,
+			compositing: map[op.BlendCompositing]color.NRGBA{
+				op.CompositeAll:         c(0x00_80_ff_ff),
+				op.CompositeBlendAndSrc: c(0x00_00_00_00),
+				op.CompositeBlendAndDst: c(0x00_80_ff_ff),
+				op.CompositeBlendOnly:   c(0x00_00_00_00),
+			},
+			tolerance: 0,
+		},
+		{
+			colors: TransparentDst,
+			compositing: map[op.BlendCompositing]color.NRGBA{
+				op.CompositeAll:         c(0x00_80_ff_ff),
+				op.CompositeBlendAndSrc: c(0x00_80_ff_ff),
+				op.CompositeBlendAndDst: c(0x00_00_00_00),
+				op.CompositeBlendOnly:   c(0x00_00_00_00),
+			},
+			tolerance: 0,
+		},
+		{
+			colors: Translucent,
+			compositing: map[op.BlendCompositing]color.NRGBA{
+				op.CompositeAll:         c(0x7a_5e_9f_c0),
+				op.CompositeBlendAndSrc: c(0x8a_52_93_80),
+				op.CompositeBlendAndDst: c(0x5f_67_a8_80),
+				op.CompositeBlendOnly:   c(0x6f_5b_9d_40),
+			},
+			tolerance: 1,
+		},
+	}
+
+	runBlendTest(t, "GeometricMean", testCases, nrgba.BlendGeometricMean, rgba.BlendGeometricMean)
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package blend_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/image/nrgba"
+	"github.com/blazeroni/magpie/pkg/image/rgba"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+func TestBlendLighterColor(t *testing.T) {
+	testCases := []blendTestCase{
+		{
+			colors: Opaque1,
+			compositing: map[op.BlendCompositing]color.NRGBA{
+				op.CompositeAll:         c(0xff_80_00_ff),
+				op.CompositeBlendAndSrc: c(0xff_80_00_ff),
+				op.CompositeBlendAndDst: c(0xff_80_00_ff),
+				op.CompositeBlendOnly:   c(0xff_80_00_ff),
+			},
+			tolerance: 0,
+		},
+		{
+			colors: Opaque2,
+			compositing: map[op.BlendCompositing]color.NRGBA{
+				op.CompositeAll:         c(0x40_80_c0_ff),
+				op.CompositeBlendAndSrc: c(0x40_80_c0_ff),
+				op.CompositeBlendAndDst: c(0x40_80_c0_ff),
+				op.CompositeBlendOnly:   c(0x40_80_c0_ff),
+			},
+			tolerance: 0,
+		},
+		{
+			colors: TransparentSrc,
+			compositing: map[op.BlendCompositing]color.NRGBA{
+				op.CompositeAll:         c(0x00_80_ff_ff),
+				op.CompositeBlendAndSrc: c(0x00_00_00_00),
+				op.CompositeBlendAndDst: c(0x00_80_ff_ff),
+				op.CompositeBlendOnly:   c(0x00_00_00_00),
+			},
+			tolerance: 0,
+		},
+		{
+			colors: TransparentDst,
+			compositing: map[op.BlendCompositing]color.NRGBA{
+				op.CompositeAll:         c(0x00_80_ff_ff),
+				op.CompositeBlendAndSrc: c(0x00_80_ff_ff),
+				op.CompositeBlendAndDst: c(0x00_00_00_00),
+				op.CompositeBlendOnly:   c(0x00_00_00_00),
+			},
+			tolerance: 0,
+		},
+		{
+			colors: Translucent,
+			compositing: map[op.BlendCompositing]color.NRGBA{
+				op.CompositeAll:         c(0x6b_6b_ab_c0),
+				op.CompositeBlendAndSrc: c(0x6b_6b_ab_80),
+				op.CompositeBlendAndDst: c(0x40_80_c0_80),
+				op.CompositeBlendOnly:   c(0x40_80_c0_40),
+			},
+			tolerance: 1,
+		},
+		{
+			// The pixel with the higher luminosity is kept whole, including its darker green channel.
+			colors: testColors{
+				name: "WholePixel",
+				dst:  c(0x20_c0_20_ff),
+				src:  c(0x80_80_80_ff),
+			},
+			compositing: map[op.BlendCompositing]color.NRGBA{
+				op.CompositeAll: c(0x80_80_80_ff),
+			},
+		},
+	}
+
+	runBlendTest(t, "LighterColor", testCases, nrgba.BlendLighterColor, rgba.BlendLighterColor)
+}
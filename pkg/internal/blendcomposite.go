@@ -3,6 +3,11 @@
 
 package internal
 
+import (
+	"fmt"
+	"strings"
+)
+
 // BlendCompositing defines the blending mode for composite operations.
 // Exposed for public use in the op package.
 type BlendCompositing int
@@ -13,3 +18,46 @@ const (
 	CompositeBlendAndSrc BlendCompositing = 4
 	CompositeAll         BlendCompositing = 6 // CompositeBlendAndDst | CompositeBlendAndSrc
 )
+
+var blendCompositingNames = map[BlendCompositing]string{
+	CompositeBlendOnly:   "blend-only",
+	CompositeBlendAndDst: "blend-and-dst",
+	CompositeBlendAndSrc: "blend-and-src",
+	CompositeAll:         "all",
+}
+
+// String returns the name of the compositing, e.g. "blend-and-dst".
+func (c BlendCompositing) String() string {
+	if name, ok := blendCompositingNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("BlendCompositing(%d)", int(c))
+}
+
+// MarshalText implements encoding.TextMarshaler. It fails for values without a name.
+func (c BlendCompositing) MarshalText() ([]byte, error) {
+	if name, ok := blendCompositingNames[c]; ok {
+		return []byte(name), nil
+	}
+	return nil, fmt.Errorf("invalid blend compositing %d", int(c))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. See ParseBlendCompositing.
+func (c *BlendCompositing) UnmarshalText(text []byte) error {
+	v, err := ParseBlendCompositing(string(text))
+	if err != nil {
+		return err
+	}
+	*c = v
+	return nil
+}
+
+// ParseBlendCompositing returns the compositing with the given name, ignoring case.
+func ParseBlendCompositing(s string) (BlendCompositing, error) {
+	for c, name := range blendCompositingNames {
+		if strings.EqualFold(s, name) {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown blend compositing %q", s)
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package internal
+
+import (
+	"image"
+	"image/draw"
+)
+
+// AsNRGBA returns the image as an *image.NRGBA. If the image is already in
+// this format, it is returned directly. Otherwise, a new NRGBA image is created
+// and the content is drawn onto it.
+func AsNRGBA(img image.Image) *image.NRGBA {
+	if rgba, ok := img.(*image.NRGBA); ok {
+		return rgba
+	}
+	bounds := img.Bounds()
+	out := image.NewNRGBA(bounds)
+	draw.Draw(out, bounds, img, bounds.Min, draw.Src)
+	return out
+}
+
+// AsRGBA returns the image as an *image.RGBA. If the image is already in
+// this format, it is returned directly. Otherwise, a new RGBA image is created
+// and the content is drawn onto it.
+func AsRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	bounds := img.Bounds()
+	out := image.NewRGBA(bounds)
+	draw.Draw(out, bounds, img, bounds.Min, draw.Over)
+	return out
+}
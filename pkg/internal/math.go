@@ -7,7 +7,10 @@ import "math"
 
 var _md255 [256][256]uint8
 var _sqrt [256]uint8
+var _isqrt [255*255 + 1]uint8
 var _unpremult [256][256]uint8
+var _softLightD [256]uint8
+var _softLightIllusions [256][256]uint8
 
 func init() {
 	for i := range 256 {
@@ -16,8 +19,21 @@ func init() {
 			if j > 0 {
 				_unpremult[i][j] = uint8((uint32(i)*255 + uint32(j)/2) / uint32(j))
 			}
+			_softLightIllusions[i][j] = uint8(math.Pow(float64(j)/255, math.Exp2(2*(0.5-float64(i)/255)))*255 + 0.5)
 		}
 		_sqrt[i] = uint8(math.Sqrt(float64(i)/255)*255 + 0.5)
+
+		x := float64(i) / 255
+		d := math.Sqrt(x)
+		if x <= 0.25 {
+			d = ((16*x-12)*x + 4) * x
+		}
+		_softLightD[i] = uint8(d*255 + 0.5)
+
+		// round(sqrt(x)) is i for i*i - i < x <= i*i + i.
+		for x := max(i*i-i+1, 0); x <= min(i*i+i, len(_isqrt)-1); x++ {
+			_isqrt[x] = uint8(i)
+		}
 	}
 }
 
@@ -35,6 +51,13 @@ func Sqrt(x uint32) uint32 {
 	return uint32(_sqrt[uint8(x)])
 }
 
+// Isqrt performs an integer square root, such as of the product of two color channels.
+// Parameter x must be at most 255*255 due to using a lookup table.
+// Computes: round(sqrt(x)).
+func Isqrt(x uint32) uint32 {
+	return uint32(_isqrt[min(x, 255*255)])
+}
+
 // Div255 performs a division by 255.
 // Approximates: (x / 255).
 func Div255(x uint32) uint32 {
@@ -47,3 +70,44 @@ func Div255(x uint32) uint32 {
 func Unpremultiply(color, alpha uint32) uint32 {
 	return uint32(_unpremult[uint8(color)][uint8(alpha)])
 }
+
+// SoftLightD evaluates the D(x) function of the W3C soft light blend mode.
+// Parameter x must be less than 256 due to using a lookup table.
+// Approximates: ((16*x - 12)*x + 4)*x for x <= 0.25, sqrt(x) otherwise.
+func SoftLightD(x uint32) uint32 {
+	return uint32(_softLightD[uint8(x)])
+}
+
+// SoftLightIllusions performs the illusions.hu soft light blend of a source and destination.
+// Parameters s and d must be less than 256 due to using a lookup table.
+// Approximates: d^(2^(2*(0.5 - s))).
+func SoftLightIllusions(s, d uint32) uint32 {
+	return uint32(_softLightIllusions[uint8(s)][uint8(d)])
+}
+
+// Lum returns the W3C luminosity of a color, scaled by 100 so it can be compared exactly.
+// Computes: 30*r + 59*g + 11*b.
+func Lum(r, g, b uint32) uint32 {
+	return 30*r + 59*g + 11*b
+}
+
+// Hash2D returns a pseudo-random value for the coordinate (x, y) under the given seed.
+// It is a counter-based generator: each value depends only on its inputs, so the results do
+// not depend on the order or concurrency in which pixels are processed.
+func Hash2D(seed uint64, x, y int) uint64 {
+	return mix64(mix64(seed) ^ uint64(uint32(x)) ^ uint64(uint32(y))<<32)
+}
+
+// mix64 is the SplitMix64 step.
+func mix64(h uint64) uint64 {
+	h += 0x9e3779b97f4a7c15
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return h ^ h>>31
+}
+
+// Dissolve reports whether the pixel (x, y) of a dissolved layer is opaque. Over many pixels
+// the fraction of opaque ones approaches alpha / 255; alpha must be less than 256.
+func Dissolve(seed uint64, x, y int, alpha uint32) bool {
+	return uint32((Hash2D(seed, x, y)>>32)*255>>32) < alpha
+}
@@ -41,6 +41,70 @@ func TestSqrt(t *testing.T) {
 	}
 }
 
+func TestIsqrt(t *testing.T) {
+	for x := range uint32(255*255 + 1) {
+		r := Isqrt(x)
+		// r is the nearest integer to sqrt(x): (r-0.5)^2 < x <= (r+0.5)^2, times 4.
+		if 4*x <= (2*r-1)*(2*r-1) && r > 0 || 4*x > (2*r+1)*(2*r+1) {
+			t.Fatalf("Isqrt(%d) = %d", x, r)
+		}
+	}
+}
+
+func TestSoftLightD(t *testing.T) {
+	tests := []struct {
+		x, want uint32
+	}{
+		{0, 0},
+		{255, 255},
+		{32, 88},
+		{63, 127},
+		{64, 128},
+	}
+
+	for _, tt := range tests {
+		if got := SoftLightD(tt.x); got != tt.want {
+			t.Errorf("SoftLightD(%d) = %d, want %d", tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestSoftLightIllusions(t *testing.T) {
+	tests := []struct {
+		s, d, want uint32
+	}{
+		{0, 128, 64},
+		{255, 64, 128},
+		{128, 64, 64},
+		{0, 255, 255},
+		{255, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := SoftLightIllusions(tt.s, tt.d); got != tt.want {
+			t.Errorf("SoftLightIllusions(%d, %d) = %d, want %d", tt.s, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestLum(t *testing.T) {
+	tests := []struct {
+		r, g, b, want uint32
+	}{
+		{0, 0, 0, 0},
+		{255, 255, 255, 25500},
+		{255, 0, 0, 7650},
+		{0, 255, 0, 15045},
+		{0, 0, 255, 2805},
+	}
+
+	for _, tt := range tests {
+		if got := Lum(tt.r, tt.g, tt.b); got != tt.want {
+			t.Errorf("Lum(%d, %d, %d) = %d, want %d", tt.r, tt.g, tt.b, got, tt.want)
+		}
+	}
+}
+
 func TestDiv255(t *testing.T) {
 	tests := []struct {
 		x, want uint32
@@ -80,3 +144,30 @@ func TestUnpremultiply(t *testing.T) {
 		}
 	}
 }
+
+func TestHash2D(t *testing.T) {
+	if Hash2D(1, 2, 3) != Hash2D(1, 2, 3) {
+		t.Error("Hash2D is not deterministic")
+	}
+	seen := map[uint64]bool{}
+	for _, in := range [][3]int{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {0, -1, 0}, {0, 0, -1}} {
+		h := Hash2D(uint64(in[0]), in[1], in[2])
+		if seen[h] {
+			t.Errorf("Hash2D(%d, %d, %d) = %#x collides", in[0], in[1], in[2], h)
+		}
+		seen[h] = true
+	}
+
+	// The top byte is close to uniform.
+	var counts [256]int
+	for y := range 256 {
+		for x := range 256 {
+			counts[Hash2D(42, x, y)>>56]++
+		}
+	}
+	for v, n := range counts {
+		if n < 192 || n > 320 {
+			t.Errorf("value %d occurs %d times, want about 256", v, n)
+		}
+	}
+}
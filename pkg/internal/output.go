@@ -0,0 +1,79 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package internal
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// WriteNRGBA copies all of img to out with img's top-left corner at outPt.
+// The output must be an *image.NRGBA or *image.RGBA; colors are premultiplied for RGBA outputs.
+// Pixels falling outside of out are skipped.
+func WriteNRGBA(pixIter core.PixelIterator, img *image.NRGBA, out image.Image, outPt image.Point) error {
+	return WriteNRGBAMasked(pixIter, img, nil, out, outPt)
+}
+
+// WriteNRGBAMasked copies the pixels of img whose entry in mask is true to out, as WriteNRGBA
+// does. mask holds one entry per pixel of img in row order; a nil mask selects all pixels.
+func WriteNRGBAMasked(pixIter core.PixelIterator, img *image.NRGBA, mask []bool, out image.Image, outPt image.Point) error {
+	r := img.Rect.Sub(img.Rect.Min).Add(outPt).Intersect(out.Bounds())
+	if r.Empty() {
+		return nil
+	}
+	sp := img.Rect.Min.Add(r.Min.Sub(outPt))
+
+	var write func(dst, src []uint8)
+	var pix []uint8
+	var stride int
+	switch o := out.(type) {
+	case *image.NRGBA:
+		pix, stride = o.Pix[o.PixOffset(r.Min.X, r.Min.Y):], o.Stride
+		write = func(dst, src []uint8) { copy(dst, src) }
+	case *image.RGBA:
+		pix, stride = o.Pix[o.PixOffset(r.Min.X, r.Min.Y):], o.Stride
+		write = func(dst, src []uint8) {
+			for i := 0; i < len(dst); i += 4 {
+				a := uint32(src[i+3])
+				dst[i] = uint8(Md255(uint32(src[i]), a))
+				dst[i+1] = uint8(Md255(uint32(src[i+1]), a))
+				dst[i+2] = uint8(Md255(uint32(src[i+2]), a))
+				dst[i+3] = src[i+3]
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported output color model %v", out.ColorModel())
+	}
+
+	w := img.Rect.Dx()
+	core.IterateRows(pixIter, r.Dy(), func(row int) {
+		dst := pix[row*stride:][:r.Dx()*4]
+		src := img.Pix[img.PixOffset(sp.X, sp.Y+row):][:r.Dx()*4]
+		if mask == nil {
+			write(dst, src)
+			return
+		}
+		m := mask[(sp.Y+row-img.Rect.Min.Y)*w+sp.X-img.Rect.Min.X:][:r.Dx()]
+		for x, in := range m {
+			if in {
+				write(dst[x*4:x*4+4], src[x*4:x*4+4])
+			}
+		}
+	})
+	return nil
+}
+
+// CopyToOutput copies the region r of dst to out at outPt in the color model of out, so that
+// the pixels of the region that an operation leaves alone keep their exact value. Nothing is
+// copied when out is dst at r.Min.
+func CopyToOutput(dst image.Image, r image.Rectangle, out image.Image, outPt image.Point) {
+	o, ok := out.(draw.Image)
+	if !ok || out == dst && outPt == r.Min {
+		return
+	}
+	draw.Draw(o, image.Rectangle{Min: outPt, Max: outPt.Add(r.Size())}, dst, r.Min, draw.Src)
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package plane
+
+import (
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// Sample is the type of the samples that MinMax filters.
+type Sample interface {
+	~uint8 | ~float32
+}
+
+// Run covers the offsets (X0, DY) through (X0+N-1, DY) of an Element.
+type Run struct {
+	DY, X0, N int
+}
+
+// Element is a neighborhood of offsets relative to a sample, stored as horizontal runs.
+type Element struct {
+	Runs []Run
+	// Rect is set when the runs form a solid rectangle, which allows a separable filter.
+	Rect bool
+}
+
+// NewElement returns the element made of runs.
+func NewElement(runs []Run) Element {
+	e := Element{Runs: runs}
+	if len(runs) > 0 {
+		e.Rect = true
+		for i, r := range runs {
+			if r.DY != runs[0].DY+i || r.X0 != runs[0].X0 || r.N != runs[0].N {
+				e.Rect = false
+			}
+		}
+	}
+	return e
+}
+
+// RectElement returns a w x h rectangle with the origin at (w/2, h/2).
+func RectElement(w, h int) Element {
+	if w <= 0 || h <= 0 {
+		return Element{}
+	}
+	runs := make([]Run, h)
+	for i := range runs {
+		runs[i] = Run{DY: i - h/2, X0: -(w / 2), N: w}
+	}
+	return Element{Runs: runs, Rect: true}
+}
+
+// DiskElement returns a disk containing all offsets within radius of the origin.
+func DiskElement(radius float64) Element {
+	if radius < 0 {
+		return Element{}
+	}
+	r := int(radius)
+	runs := make([]Run, 0, 2*r+1)
+	for dy := -r; dy <= r; dy++ {
+		span := int(math.Sqrt(radius*radius - float64(dy*dy)))
+		runs = append(runs, Run{DY: dy, X0: -span, N: 2*span + 1})
+	}
+	return Element{Runs: runs, Rect: r == 0}
+}
+
+// Reflect returns the element mirrored through the origin.
+func (e Element) Reflect() Element {
+	runs := make([]Run, len(e.Runs))
+	for i, r := range e.Runs {
+		runs[len(runs)-1-i] = Run{DY: -r.DY, X0: -(r.X0 + r.N - 1), N: r.N}
+	}
+	return Element{Runs: runs, Rect: e.Rect}
+}
+
+// MinMax returns the maximum (isMax) or minimum of p over the offsets of e at each sample of
+// a w x h plane. Samples outside of the plane have the value outside.
+//
+// Each run is filtered with the van Herk/Gil-Werman algorithm, which needs three comparisons
+// per sample for any run length, so an element costs a constant amount per run. Rectangles
+// are filtered with separable passes at a constant cost per sample.
+func MinMax[T Sample](pixIter core.PixelIterator, p []T, w, h int, e Element, isMax bool, outside T) []T {
+	if e.Rect {
+		return minMaxRect(pixIter, p, w, h, e.Runs[0], len(e.Runs), isMax, outside)
+	}
+
+	dst := make([]T, w*h)
+	core.IterateRows(pixIter, h, func(y int) {
+		out := dst[y*w : (y+1)*w]
+		var buf []T
+		win := make([]T, w)
+		for i, r := range e.Runs {
+			sy := y + r.DY
+			if sy < 0 || sy >= h {
+				for x := range win {
+					win[x] = outside
+				}
+			} else {
+				buf = window(win, p[sy*w:(sy+1)*w], r.X0, r.N, isMax, outside, buf)
+			}
+			if i == 0 {
+				copy(out, win)
+				continue
+			}
+			for x, v := range win {
+				out[x] = pick(out[x], v, isMax)
+			}
+		}
+	})
+	return dst
+}
+
+// minMaxRect filters p with a solid rectangle of rows runs, each equal to r, using separable
+// horizontal and vertical van Herk/Gil-Werman passes.
+func minMaxRect[T Sample](pixIter core.PixelIterator, p []T, w, h int, r Run, rows int, isMax bool, outside T) []T {
+	tmp := make([]T, w*h)
+	core.IterateRows(pixIter, h, func(y int) {
+		window(tmp[y*w:(y+1)*w], p[y*w:(y+1)*w], r.X0, r.N, isMax, outside, nil)
+	})
+
+	// The vertical pass works on whole rows. Row i of the padded sequence is row i+r.DY of tmp,
+	// rows outside of tmp are outside. For each block of n rows, prefix holds the running
+	// extremum from the start of the block and suffix the running extremum to its end.
+	n := rows
+	length := h + n - 1
+	prefix := make([]T, length*w)
+	suffix := make([]T, length*w)
+	source := func(i int) []T {
+		if sy := i + r.DY; sy >= 0 && sy < h {
+			return tmp[sy*w : (sy+1)*w]
+		}
+		return nil
+	}
+	set := func(dst, a, b []T) {
+		switch {
+		case a == nil && b == nil:
+			for x := range dst {
+				dst[x] = outside
+			}
+		case a == nil:
+			copy(dst, b)
+		case b == nil:
+			copy(dst, a)
+		default:
+			for x := range dst {
+				dst[x] = pick(a[x], b[x], isMax)
+			}
+		}
+	}
+	core.IterateRows(pixIter, (length+n-1)/n, func(block int) {
+		start := block * n
+		end := min(start+n, length)
+		set(prefix[start*w:(start+1)*w], source(start), nil)
+		for i := start + 1; i < end; i++ {
+			set(prefix[i*w:(i+1)*w], prefix[(i-1)*w:i*w], source(i))
+		}
+		set(suffix[(end-1)*w:end*w], source(end-1), nil)
+		for i := end - 2; i >= start; i-- {
+			set(suffix[i*w:(i+1)*w], suffix[(i+1)*w:(i+2)*w], source(i))
+		}
+	})
+
+	dst := make([]T, w*h)
+	core.IterateRows(pixIter, h, func(y int) {
+		set(dst[y*w:(y+1)*w], suffix[y*w:(y+1)*w], prefix[(y+n-1)*w:(y+n)*w])
+	})
+	return dst
+}
+
+// window sets out[x] to the extremum of in[x+off] through in[x+off+n-1] using the van
+// Herk/Gil-Werman algorithm. Samples outside of in have the value outside. buf is scratch
+// space that is grown as needed and returned.
+func window[T Sample](out, in []T, off, n int, isMax bool, outside T, buf []T) []T {
+	length := len(out) + n - 1
+	if cap(buf) < 3*length {
+		buf = make([]T, 3*length)
+	}
+	buf = buf[:3*length]
+	seq, prefix, suffix := buf[:length], buf[length:2*length], buf[2*length:]
+	for i := range seq {
+		seq[i] = outside
+		if sx := i + off; sx >= 0 && sx < len(in) {
+			seq[i] = in[sx]
+		}
+	}
+	for i, v := range seq {
+		if i%n == 0 {
+			prefix[i] = v
+		} else {
+			prefix[i] = pick(prefix[i-1], v, isMax)
+		}
+	}
+	for i := length - 1; i >= 0; i-- {
+		if i == length-1 || (i+1)%n == 0 {
+			suffix[i] = seq[i]
+		} else {
+			suffix[i] = pick(suffix[i+1], seq[i], isMax)
+		}
+	}
+	for x := range out {
+		out[x] = pick(suffix[x], prefix[x+n-1], isMax)
+	}
+	return buf
+}
+
+func pick[T Sample](a, b T, isMax bool) T {
+	if isMax {
+		return max(a, b)
+	}
+	return min(a, b)
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package plane provides single-channel float32 image planes and the
+// neighborhood filters shared by the higher level magpie packages.
+package plane
+
+import (
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// Plane is a single-channel image of float32 samples stored in row-major order.
+type Plane struct {
+	W, H int
+	Pix  []float32
+}
+
+// New creates a zeroed plane with the given dimensions.
+func New(w, h int) *Plane {
+	return &Plane{W: w, H: h, Pix: make([]float32, w*h)}
+}
+
+// At returns the sample at (x, y). Coordinates are clamped to the plane bounds.
+func (p *Plane) At(x, y int) float32 {
+	x = core.Clamp(x, 0, p.W-1)
+	y = core.Clamp(y, 0, p.H-1)
+	return p.Pix[y*p.W+x]
+}
+
+// Set sets the sample at (x, y).
+func (p *Plane) Set(x, y int, v float32) {
+	p.Pix[y*p.W+x] = v
+}
+
+// Row returns the samples for row y.
+func (p *Plane) Row(y int) []float32 {
+	return p.Pix[y*p.W : (y+1)*p.W]
+}
+
+// Clone returns a deep copy of the plane.
+func (p *Plane) Clone() *Plane {
+	c := New(p.W, p.H)
+	copy(c.Pix, p.Pix)
+	return c
+}
+
+// GaussianKernel returns a normalized 1D Gaussian kernel for sigma.
+// The kernel has a radius of ceil(3*sigma) and always contains at least one tap.
+func GaussianKernel(sigma float64) []float32 {
+	if sigma <= 0 {
+		return []float32{1}
+	}
+	radius := int(math.Ceil(3 * sigma))
+	kernel := make([]float32, 2*radius+1)
+	var sum float64
+	for i := -radius; i <= radius; i++ {
+		v := math.Exp(-float64(i*i) / (2 * sigma * sigma))
+		kernel[i+radius] = float32(v)
+		sum += v
+	}
+	for i := range kernel {
+		kernel[i] = float32(float64(kernel[i]) / sum)
+	}
+	return kernel
+}
+
+// Convolve applies a separable convolution with the given horizontal and vertical kernels.
+// Kernels must have an odd length. Samples outside the plane are clamped to the nearest edge.
+func Convolve(pixIter core.PixelIterator, p *Plane, kx, ky []float32) *Plane {
+	tmp := New(p.W, p.H)
+	rx := len(kx) / 2
+	core.IterateRows(pixIter, p.H, func(y int) {
+		in, out := p.Row(y), tmp.Row(y)
+		for x := range p.W {
+			var sum float32
+			for k, w := range kx {
+				sx := core.Clamp(x+k-rx, 0, p.W-1)
+				sum += in[sx] * w
+			}
+			out[x] = sum
+		}
+	})
+
+	dst := New(p.W, p.H)
+	ry := len(ky) / 2
+	core.IterateRows(pixIter, p.H, func(y int) {
+		out := dst.Row(y)
+		for k, w := range ky {
+			in := tmp.Row(core.Clamp(y+k-ry, 0, p.H-1))
+			for x := range p.W {
+				out[x] += in[x] * w
+			}
+		}
+	})
+	return dst
+}
+
+// GaussianBlur returns a copy of p blurred with a Gaussian of the given sigma.
+func GaussianBlur(pixIter core.PixelIterator, p *Plane, sigma float64) *Plane {
+	if sigma <= 0 {
+		return p.Clone()
+	}
+	kernel := GaussianKernel(sigma)
+	return Convolve(pixIter, p, kernel, kernel)
+}
+
+// Dilate returns the maximum of p over a disk of the given radius around each sample.
+func Dilate(pixIter core.PixelIterator, p *Plane, radius float64) *Plane {
+	return diskFilter(pixIter, p, radius, true)
+}
+
+// Erode returns the minimum of p over a disk of the given radius around each sample.
+// Samples outside the plane are treated as zero, so coverage shrinks away from the edges.
+func Erode(pixIter core.PixelIterator, p *Plane, radius float64) *Plane {
+	return diskFilter(pixIter, p, radius, false)
+}
+
+func diskFilter(pixIter core.PixelIterator, p *Plane, radius float64, isMax bool) *Plane {
+	if radius <= 0 {
+		return p.Clone()
+	}
+	return &Plane{W: p.W, H: p.H, Pix: MinMax(pixIter, p.Pix, p.W, p.H, DiskElement(radius), isMax, 0)}
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package plane
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+func TestGaussianKernel(t *testing.T) {
+	kernel := GaussianKernel(2)
+	if len(kernel) != 13 {
+		t.Fatalf("len(GaussianKernel(2)) = %d, want 13", len(kernel))
+	}
+	var sum float32
+	for _, v := range kernel {
+		sum += v
+	}
+	if math.Abs(float64(sum)-1) > 1e-5 {
+		t.Errorf("kernel sum = %v, want 1", sum)
+	}
+	if len(GaussianKernel(0)) != 1 {
+		t.Error("GaussianKernel(0) should be the identity")
+	}
+}
+
+func TestGaussianBlur(t *testing.T) {
+	p := New(21, 21)
+	p.Set(10, 10, 1)
+
+	serial := GaussianBlur(core.NewSerialPixelIterator(), p, 1.5)
+	parallel := GaussianBlur(core.NewParallelPixelIterator(4), p, 1.5)
+
+	var sum float64
+	for i, v := range serial.Pix {
+		sum += float64(v)
+		if v != parallel.Pix[i] {
+			t.Fatalf("serial and parallel blur differ at %d: %v vs %v", i, v, parallel.Pix[i])
+		}
+	}
+	if math.Abs(sum-1) > 1e-4 {
+		t.Errorf("blur should preserve energy, sum = %v", sum)
+	}
+	if serial.At(10, 10) <= serial.At(11, 10) || serial.At(11, 10) != serial.At(10, 11) {
+		t.Error("blur should be symmetric and peak at the impulse")
+	}
+	if serial.At(-5, 100) != serial.At(0, 20) {
+		t.Error("At should clamp coordinates")
+	}
+}
+
+func TestDilateErode(t *testing.T) {
+	p := New(11, 11)
+	for y := 3; y < 8; y++ {
+		for x := 3; x < 8; x++ {
+			p.Set(x, y, 1)
+		}
+	}
+	pixIter := core.NewSerialPixelIterator()
+
+	d := Dilate(pixIter, p, 2)
+	if d.At(1, 5) != 1 || d.At(0, 5) != 0 {
+		t.Error("Dilate should grow the square by the radius")
+	}
+	// Corners are rounded by the disk.
+	if d.At(1, 1) != 0 || d.At(2, 2) != 1 {
+		t.Error("Dilate should use a disk shaped neighborhood")
+	}
+
+	e := Erode(pixIter, p, 1)
+	if e.At(3, 5) != 0 || e.At(4, 5) != 1 {
+		t.Error("Erode should shrink the square by the radius")
+	}
+	if got := Erode(pixIter, New(3, 3), 0); len(got.Pix) != 9 {
+		t.Error("Erode with radius 0 should return a copy")
+	}
+}
+
+// bruteDisk filters p by visiting every offset of a disk, with zero outside of the plane.
+func bruteDisk(p *Plane, radius float64, isMax bool) *Plane {
+	out := New(p.W, p.H)
+	r := int(radius)
+	for y := range p.H {
+		for x := range p.W {
+			v := p.Pix[y*p.W+x]
+			for dy := -r; dy <= r; dy++ {
+				for dx := -r; dx <= r; dx++ {
+					if float64(dx*dx+dy*dy) > radius*radius {
+						continue
+					}
+					var s float32
+					if sx, sy := x+dx, y+dy; sx >= 0 && sx < p.W && sy >= 0 && sy < p.H {
+						s = p.Pix[sy*p.W+sx]
+					}
+					v = pick(v, s, isMax)
+				}
+			}
+			out.Pix[y*p.W+x] = v
+		}
+	}
+	return out
+}
+
+func TestDilateErodeMatchBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	p := New(31, 23)
+	for i := range p.Pix {
+		p.Pix[i] = rng.Float32()
+	}
+	pixIter := core.NewSerialPixelIterator()
+	for _, radius := range []float64{0.5, 1, 2.5, 7, 40} {
+		for _, isMax := range []bool{true, false} {
+			got := Erode(pixIter, p, radius)
+			if isMax {
+				got = Dilate(pixIter, p, radius)
+			}
+			if want := bruteDisk(p, radius, isMax); !slices.Equal(got.Pix, want.Pix) {
+				t.Errorf("radius %v, max %v: differs from the brute force filter", radius, isMax)
+			}
+		}
+	}
+}
+
+func TestMinMaxRect(t *testing.T) {
+	p := []uint8{
+		1, 2, 3,
+		4, 9, 5,
+		6, 7, 8,
+	}
+	got := MinMax(core.NewSerialPixelIterator(), p, 3, 3, RectElement(3, 3), true, 0)
+	want := []uint8{9, 9, 9, 9, 9, 9, 9, 9, 9}
+	if !slices.Equal(got, want) {
+		t.Errorf("MinMax = %v, want %v", got, want)
+	}
+	got = MinMax(core.NewSerialPixelIterator(), p, 3, 3, RectElement(3, 1), false, 255)
+	want = []uint8{1, 1, 2, 4, 4, 5, 6, 6, 7}
+	if !slices.Equal(got, want) {
+		t.Errorf("MinMax = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkDilateLargeRadius(b *testing.B) {
+	p := New(512, 512)
+	for i := range p.Pix {
+		p.Pix[i] = float32(i%7) / 7
+	}
+	pixIter := core.NewSerialPixelIterator()
+	b.ResetTimer()
+	for range b.N {
+		Dilate(pixIter, p, 50)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package keying generates alpha mattes from a key color (green/blue screen keying).
+// The keyed result is an *image.NRGBA that can be passed directly to the composite ops,
+// e.g. composite.SourceOver().
+package keying
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal/plane"
+)
+
+// ColorSpace defines how the distance to the key color is measured.
+// Both spaces discard the luma (value) component, which makes keying tolerant of uneven
+// lighting across the screen.
+type ColorSpace int
+
+const (
+	// YCbCr measures the distance between the Cb/Cr chroma components.
+	YCbCr ColorSpace = iota
+	// HSV measures the distance on the hue/saturation disk.
+	HSV
+
+	_maxColorSpace
+)
+
+// Spill defines the spill suppression applied to the foreground colors.
+type Spill int
+
+const (
+	// SpillNone leaves colors unchanged.
+	SpillNone Spill = iota
+	// SpillGreen limits green to the average of red and blue.
+	SpillGreen
+	// SpillBlue limits blue to the average of red and green.
+	SpillBlue
+
+	_maxSpill
+)
+
+// Op describes a keying operation.
+type Op struct {
+	// Key is the screen color to remove.
+	Key color.Color
+	// Space is the color space used to measure the distance to Key.
+	Space ColorSpace
+	// Tolerance is the normalized distance in [0, 1] below which pixels become fully transparent.
+	Tolerance float64
+	// Softness is the normalized distance range above Tolerance over which alpha ramps to opaque.
+	Softness float64
+	// Spill selects the spill suppression applied to the foreground.
+	Spill Spill
+	// Choke shrinks the matte by the given number of pixels; negative values grow it.
+	Choke int
+	// Feather blurs the matte with a Gaussian of the given sigma in pixels.
+	Feather float64
+}
+
+// GreenScreen returns an Op with settings suitable for a typical green screen.
+func GreenScreen() Op {
+	return Op{
+		Key:       color.NRGBA{G: 255, A: 255},
+		Space:     YCbCr,
+		Tolerance: 0.25,
+		Softness:  0.15,
+		Spill:     SpillGreen,
+	}
+}
+
+// BlueScreen returns an Op with settings suitable for a typical blue screen.
+func BlueScreen() Op {
+	return Op{
+		Key:       color.NRGBA{B: 255, A: 255},
+		Space:     YCbCr,
+		Tolerance: 0.25,
+		Softness:  0.15,
+		Spill:     SpillBlue,
+	}
+}
+
+// IsValid reports whether the operation can be applied.
+func (o Op) IsValid() bool {
+	return o.Key != nil &&
+		o.Space >= 0 && o.Space < _maxColorSpace &&
+		o.Spill >= 0 && o.Spill < _maxSpill &&
+		o.Tolerance >= 0 && o.Softness >= 0 && o.Feather >= 0
+}
+
+// Key keys the region r of src and returns a new *image.NRGBA with bounds r.
+// The alpha of each pixel is the source alpha multiplied by the matte.
+func Key(cfg core.Config, src image.Image, r image.Rectangle, o Op) (*image.NRGBA, error) {
+	if !o.IsValid() {
+		return nil, errors.New("invalid keying operation")
+	}
+	r = r.Intersect(src.Bounds())
+	out := image.NewNRGBA(r)
+	if r.Empty() {
+		return out, nil
+	}
+	draw.Draw(out, r, src, r.Min, draw.Src)
+
+	pixIter := cfg.PixelIterator()
+	matte := Matte(pixIter, out, o)
+	if o.Choke != 0 {
+		matte = choke(pixIter, matte, o.Choke)
+	}
+	if o.Feather > 0 {
+		matte = plane.GaussianBlur(pixIter, matte, o.Feather)
+	}
+
+	core.IterateRows(pixIter, r.Dy(), func(y int) {
+		pix := out.Pix[y*out.Stride : y*out.Stride+r.Dx()*4]
+		m := matte.Row(y)
+		for x := range r.Dx() {
+			i := x * 4
+			suppressSpill(pix[i:i+3], o.Spill)
+			a := float32(pix[i+3]) * core.Clamp(m[x], 0, 1)
+			pix[i+3] = uint8(a + 0.5)
+		}
+	})
+	return out, nil
+}
+
+// Matte computes the key matte of img without choke, feather or source alpha applied.
+// Values are in [0, 1] where 0 matches the key color.
+func Matte(pixIter core.PixelIterator, img *image.NRGBA, o Op) *plane.Plane {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	matte := plane.New(w, h)
+	kc := color.NRGBAModel.Convert(o.Key).(color.NRGBA) //nolint:errcheck
+	k1, k2 := chroma(o.Space, kc.R, kc.G, kc.B)
+	tol, soft := float32(o.Tolerance), float32(o.Softness)
+
+	core.IterateRows(pixIter, h, func(y int) {
+		pix := img.Pix[y*img.Stride:]
+		m := matte.Row(y)
+		for x := range w {
+			c1, c2 := chroma(o.Space, pix[x*4], pix[x*4+1], pix[x*4+2])
+			d := float32(math.Hypot(float64(c1-k1), float64(c2-k2)))
+			switch {
+			case d <= tol:
+				m[x] = 0
+			case d >= tol+soft:
+				m[x] = 1
+			default:
+				m[x] = (d - tol) / soft
+			}
+		}
+	})
+	return matte
+}
+
+// chroma returns the luma independent coordinates of a color, scaled so that the
+// distance between two fully saturated opposing colors is approximately 1.
+func chroma(space ColorSpace, r, g, b uint8) (float32, float32) {
+	switch space {
+	case HSV:
+		mx := max(r, g, b)
+		mn := min(r, g, b)
+		if mx == 0 || mx == mn {
+			return 0, 0
+		}
+		s := float64(mx-mn) / float64(mx)
+		var h float64
+		rf, gf, bf, d := float64(r), float64(g), float64(b), float64(mx-mn)
+		switch mx {
+		case r:
+			h = math.Mod((gf-bf)/d, 6)
+		case g:
+			h = (bf-rf)/d + 2
+		default:
+			h = (rf-gf)/d + 4
+		}
+		angle := h * math.Pi / 3
+		return float32(s * math.Cos(angle) / 2), float32(s * math.Sin(angle) / 2)
+	case YCbCr, _maxColorSpace:
+	}
+	_, cb, cr := color.RGBToYCbCr(r, g, b)
+	return (float32(cb) - 128) / 255, (float32(cr) - 128) / 255
+}
+
+// suppressSpill limits the spill channel of a straight alpha color to the average of the others.
+func suppressSpill(c []uint8, spill Spill) {
+	switch spill {
+	case SpillGreen:
+		c[1] = min(c[1], uint8((uint32(c[0])+uint32(c[2])+1)/2))
+	case SpillBlue:
+		c[2] = min(c[2], uint8((uint32(c[0])+uint32(c[1])+1)/2))
+	case SpillNone, _maxSpill:
+	}
+}
+
+// choke shrinks (positive n) or grows (negative n) the matte with a square min/max filter.
+// Samples outside of the matte are ignored, so a subject touching the edge is not shrunk from
+// it.
+func choke(pixIter core.PixelIterator, matte *plane.Plane, n int) *plane.Plane {
+	isMax, outside := false, float32(math.Inf(1))
+	if n < 0 {
+		n, isMax, outside = -n, true, float32(math.Inf(-1))
+	}
+	square := plane.RectElement(2*n+1, 2*n+1)
+	return &plane.Plane{W: matte.W, H: matte.H, Pix: plane.MinMax(pixIter, matte.Pix, matte.W, matte.H, square, isMax, outside)}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package keying
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+	"github.com/blazeroni/magpie/pkg/internal/plane"
+)
+
+// screenShot returns a green screen with a red subject in the middle.
+func screenShot() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+	for y := range 20 {
+		for x := range 20 {
+			c := color.NRGBA{R: 30, G: 200, B: 40, A: 255}
+			if y < 10 {
+				// darker part of the screen
+				c = color.NRGBA{R: 20, G: 150, B: 30, A: 255}
+			}
+			if x >= 5 && x < 15 && y >= 5 && y < 15 {
+				c = color.NRGBA{R: 200, G: 120, B: 90, A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestKey(t *testing.T) {
+	for _, space := range []ColorSpace{YCbCr, HSV} {
+		o := GreenScreen()
+		o.Key = color.NRGBA{R: 30, G: 200, B: 40, A: 255}
+		o.Space = space
+		out, err := Key(internal.DefaultConfig, screenShot(), image.Rect(0, 0, 20, 20), o)
+		if err != nil {
+			t.Fatalf("Key failed: %v", err)
+		}
+		if a := out.NRGBAAt(1, 1).A; a != 0 {
+			t.Errorf("space %d: dark screen alpha = %d, want 0", space, a)
+		}
+		if a := out.NRGBAAt(1, 18).A; a != 0 {
+			t.Errorf("space %d: bright screen alpha = %d, want 0", space, a)
+		}
+		c := out.NRGBAAt(10, 10)
+		if c.A != 255 {
+			t.Errorf("space %d: subject alpha = %d, want 255", space, c.A)
+		}
+		// Green spill is limited to the average of red and blue, 145, which the subject's green
+		// is already below.
+		if c.G != 120 {
+			t.Errorf("space %d: subject green = %d, want 120", space, c.G)
+		}
+	}
+}
+
+func TestKeySoftnessAndSpill(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 1))
+	img.SetNRGBA(0, 0, color.NRGBA{G: 255, A: 255})
+	img.SetNRGBA(1, 0, color.NRGBA{R: 80, G: 200, B: 80, A: 255})
+	img.SetNRGBA(2, 0, color.NRGBA{R: 100, G: 220, B: 60, A: 128})
+
+	o := Op{Key: color.NRGBA{G: 255, A: 255}, Tolerance: 0.05, Softness: 0.5, Spill: SpillGreen}
+	out, err := Key(internal.DefaultConfig, img, img.Bounds(), o)
+	if err != nil {
+		t.Fatalf("Key failed: %v", err)
+	}
+	if a := out.NRGBAAt(0, 0).A; a != 0 {
+		t.Errorf("key color alpha = %d, want 0", a)
+	}
+	if a := out.NRGBAAt(1, 0).A; a == 0 || a == 255 {
+		t.Errorf("partially matching alpha = %d, want partial coverage", a)
+	}
+	c := out.NRGBAAt(2, 0)
+	if c.G != 80 {
+		t.Errorf("spill suppressed green = %d, want 80", c.G)
+	}
+	if c.A >= 128 {
+		t.Errorf("source alpha should scale the matte, got %d", c.A)
+	}
+}
+
+func TestKeyChokeFeather(t *testing.T) {
+	o := GreenScreen()
+	o.Key = color.NRGBA{R: 30, G: 200, B: 40, A: 255}
+	base, _ := Key(internal.DefaultConfig, screenShot(), image.Rect(0, 0, 20, 20), o)
+
+	o.Choke = 2
+	choked, _ := Key(internal.DefaultConfig, screenShot(), image.Rect(0, 0, 20, 20), o)
+	if base.NRGBAAt(5, 10).A != 255 || choked.NRGBAAt(5, 10).A != 0 || choked.NRGBAAt(7, 10).A != 255 {
+		t.Error("choke should shrink the matte by 2 pixels")
+	}
+
+	o.Choke = -2
+	spread, _ := Key(internal.DefaultConfig, screenShot(), image.Rect(0, 0, 20, 20), o)
+	if spread.NRGBAAt(3, 10).A != 255 || spread.NRGBAAt(2, 10).A != 0 {
+		t.Error("negative choke should grow the matte by 2 pixels")
+	}
+
+	o.Choke = 0
+	o.Feather = 1.5
+	feathered, _ := Key(config{core.NewParallelPixelIterator(3)}, screenShot(), image.Rect(0, 0, 20, 20), o)
+	if a := feathered.NRGBAAt(5, 10).A; a == 0 || a == 255 {
+		t.Errorf("feathered edge alpha = %d, want partial coverage", a)
+	}
+}
+
+func TestChokeEdges(t *testing.T) {
+	// A subject covering the left half of the matte, touching three edges.
+	matte := plane.New(8, 4)
+	for y := range 4 {
+		for x := range 4 {
+			matte.Set(x, y, 1)
+		}
+	}
+	pixIter := core.NewSerialPixelIterator()
+	choked := choke(pixIter, matte, 1)
+	if choked.At(0, 0) != 1 || choked.At(2, 3) != 1 || choked.At(3, 1) != 0 {
+		t.Errorf("choked matte = %v, want the subject shrunk from its inner edge only", choked.Pix)
+	}
+	spread := choke(pixIter, matte, -2)
+	if spread.At(5, 0) != 1 || spread.At(6, 3) != 0 {
+		t.Errorf("spread matte = %v, want the subject grown by 2 pixels", spread.Pix)
+	}
+}
+
+func TestKeyInvalid(t *testing.T) {
+	img := screenShot()
+	for _, o := range []Op{{}, {Key: color.Black, Space: -1}, {Key: color.Black, Spill: _maxSpill}, {Key: color.Black, Tolerance: -1}} {
+		if _, err := Key(internal.DefaultConfig, img, img.Bounds(), o); err == nil {
+			t.Errorf("Key(%+v) should fail", o)
+		}
+	}
+}
+
+// config is a minimal core.Config with a fixed PixelIterator.
+type config struct {
+	pixIter core.PixelIterator
+}
+
+func (c config) PixelIterator() core.PixelIterator         { return c.pixIter }
+func (c config) DefaultOutputMode() core.DefaultOutputMode { return core.DefaultOutputToNewImage }
+func (c config) DefaultColorModel() color.Model            { return color.NRGBAModel }
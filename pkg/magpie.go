@@ -4,7 +4,6 @@
 package magpie
 
 import (
-	"fmt"
 	"image"
 
 	"github.com/blazeroni/magpie/pkg/core"
@@ -37,41 +36,42 @@ func SetDefaultContext(ctx Context) {
 	defaultContext.config = internal.DefaultConfig
 }
 
-// Draw is the primary function for applying image manipulation operations (both blend and composite) using the default context.
+// Draw is the primary function for applying image manipulation operations using the default context.
+// It accepts any core.Op, including blends, composites and operations implemented outside of Magpie.
 // It follows similar semantics as Go's draw.Draw function. However, rather than always writing to the destination image,
 // this function writes to a specified output which may be the destination image, a provided image, or a new image.
 // It returns the modified output image.
-func Draw(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, oper internal.Op, output core.Output) (image.Image, error) {
-	switch opType := oper.(type) {
-	case op.BlendOp:
-		return Blend(dst, r, src, sp, opType, output)
-	case op.CompositeOp:
-		return Composite(dst, r, src, sp, opType, output)
-	}
-	return nil, fmt.Errorf("unsupported operation type: %T", oper)
+func Draw(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, oper core.Op, output core.Output) (image.Image, error) {
+	return defaultContext.Draw(dst, r, src, sp, oper, output)
 }
 
 // DrawToDst is a convenience function that applies an operation using the default context
 // and writes the result directly to the destination image (dst).
 // It is equivalent to calling Draw with ToDst() as the output.
-func DrawToDst(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op internal.Op) (image.Image, error) {
+func DrawToDst(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op core.Op) (image.Image, error) {
 	return Draw(dst, r, src, sp, op, ToDst())
 }
 
 // DrawToNewImage is a convenience function that applies an operation using the default context
 // and writes the result to a newly created image.
 // It is equivalent to calling Draw with ToNewImage() as the output.
-func DrawToNewImage(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op internal.Op) (image.Image, error) {
+func DrawToNewImage(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op core.Op) (image.Image, error) {
 	return Draw(dst, r, src, sp, op, ToNewImage())
 }
 
 // DrawToImage is a convenience function that applies an operation using the default context
 // and writes the result to a user-provided image at a specified point.
 // It is equivalent to calling Draw with ToImage(out, outPt) as the output.
-func DrawToImage(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op internal.Op, out image.Image, outPt image.Point) (image.Image, error) {
+func DrawToImage(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op core.Op, out image.Image, outPt image.Point) (image.Image, error) {
 	return Draw(dst, r, src, sp, op, ToImage(out, outPt))
 }
 
+// DrawWrapped applies an operation with a wrapped source using the default context.
+// See Context.DrawWrapped for details.
+func DrawWrapped(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, wrap core.SourceWrap, oper core.Op, output core.Output) (image.Image, error) {
+	return defaultContext.DrawWrapped(dst, r, src, sp, wrap, oper, output)
+}
+
 // Blend performs a blend operation using the default context.
 // See Context.Blend for details.
 func Blend(dst image.Image, r image.Rectangle, src image.Image, sp image.Point, op op.BlendOp, output core.Output) (image.Image, error) {
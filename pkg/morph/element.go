@@ -0,0 +1,70 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package morph
+
+import (
+	"image"
+
+	"github.com/blazeroni/magpie/pkg/internal/plane"
+)
+
+// Element is a structuring element: the neighborhood, relative to the origin pixel, that a
+// morphological operation examines.
+type Element struct {
+	e plane.Element
+}
+
+// Square returns a (2*radius+1) x (2*radius+1) square element centered on the origin.
+func Square(radius int) Element {
+	return Rect(2*radius+1, 2*radius+1)
+}
+
+// Rect returns a w x h rectangular element with the origin at (w/2, h/2).
+func Rect(w, h int) Element {
+	return Element{plane.RectElement(w, h)}
+}
+
+// Disk returns a disk shaped element containing all offsets within radius of the origin.
+func Disk(radius float64) Element {
+	return Element{plane.DiskElement(radius)}
+}
+
+// Custom returns an element containing the pixels of mask whose alpha is at least 50%.
+// The origin of the element is at the anchor point of mask.
+func Custom(mask image.Image, anchor image.Point) Element {
+	var runs []plane.Run
+	b := mask.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		start := -1
+		for x := b.Min.X; x <= b.Max.X; x++ {
+			in := false
+			if x < b.Max.X {
+				_, _, _, a := mask.At(x, y).RGBA()
+				in = a >= 0x8000
+			}
+			switch {
+			case in && start < 0:
+				start = x
+			case !in && start >= 0:
+				runs = append(runs, plane.Run{DY: y - anchor.Y, X0: start - anchor.X, N: x - start})
+				start = -1
+			}
+		}
+	}
+	return Element{plane.NewElement(runs)}
+}
+
+// IsValid reports whether the element contains at least one offset.
+func (e Element) IsValid() bool {
+	return len(e.e.Runs) > 0
+}
+
+// Bounds returns the smallest rectangle containing all offsets of the element.
+func (e Element) Bounds() image.Rectangle {
+	var b image.Rectangle
+	for _, r := range e.e.Runs {
+		b = b.Union(image.Rect(r.X0, r.DY, r.X0+r.N, r.DY+1))
+	}
+	return b
+}
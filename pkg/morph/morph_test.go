@@ -0,0 +1,202 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package morph
+
+import (
+	"image"
+	"image/color"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+func randomAlpha(w, h int, seed uint64) *image.Alpha {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	img := image.NewAlpha(image.Rect(3, 5, 3+w, 5+h))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rng.IntN(256))
+	}
+	return img
+}
+
+// offsets expands an element into its individual offsets.
+func offsets(e Element) []image.Point {
+	var pts []image.Point
+	for _, r := range e.e.Runs {
+		for x := r.X0; x < r.X0+r.N; x++ {
+			pts = append(pts, image.Pt(x, r.DY))
+		}
+	}
+	return pts
+}
+
+// bruteForce computes a dilation or erosion by visiting every offset of the element.
+func bruteForce(src *image.Alpha, e Element, dilate bool) *image.Alpha {
+	out := image.NewAlpha(src.Rect)
+	for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+		for x := src.Rect.Min.X; x < src.Rect.Max.X; x++ {
+			v := uint8(255)
+			if dilate {
+				v = 0
+			}
+			for _, d := range offsets(e) {
+				p := image.Pt(x+d.X, y+d.Y)
+				if dilate {
+					p = image.Pt(x-d.X, y-d.Y)
+				}
+				if !p.In(src.Rect) {
+					continue
+				}
+				if a := src.AlphaAt(p.X, p.Y).A; dilate {
+					v = max(v, a)
+				} else {
+					v = min(v, a)
+				}
+			}
+			out.SetAlpha(x, y, color.Alpha{A: v})
+		}
+	}
+	return out
+}
+
+func customElement() Element {
+	mask := image.NewAlpha(image.Rect(0, 0, 4, 3))
+	for _, p := range []image.Point{{0, 0}, {1, 0}, {3, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}} {
+		mask.SetAlpha(p.X, p.Y, color.Alpha{A: 255})
+	}
+	return Custom(mask, image.Pt(1, 1))
+}
+
+func TestDilateErodeMatchBruteForce(t *testing.T) {
+	src := randomAlpha(37, 29, 1)
+	elements := map[string]Element{
+		"square0": Square(0),
+		"square2": Square(2),
+		"square9": Square(9),
+		"rect":    Rect(6, 3),
+		"disk":    Disk(4.5),
+		"custom":  customElement(),
+	}
+	for name, e := range elements {
+		for _, mode := range []Mode{Dilate, Erode} {
+			got, err := Apply(internal.DefaultConfig, src, src.Rect, Op{Mode: mode, Element: e})
+			if err != nil {
+				t.Fatalf("%s: Apply failed: %v", name, err)
+			}
+			want := bruteForce(src, e, mode == Dilate)
+			for i := range want.Pix {
+				if got.Pix[i] != want.Pix[i] {
+					t.Errorf("%s mode %d: sample %d = %d, want %d", name, mode, i, got.Pix[i], want.Pix[i])
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestCompoundOperations(t *testing.T) {
+	src := randomAlpha(30, 30, 2)
+	e := Disk(2)
+	apply := func(img *image.Alpha, mode Mode) *image.Alpha {
+		t.Helper()
+		out, err := Apply(internal.DefaultConfig, img, img.Rect, Op{Mode: mode, Element: e})
+		if err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+		return out
+	}
+
+	open, closed := apply(src, Open), apply(src, Close)
+	openTwice, closedTwice := apply(open, Open), apply(closed, Close)
+	grad, top, black := apply(src, Gradient), apply(src, TopHat), apply(src, BlackHat)
+	dil, ero := apply(src, Dilate), apply(src, Erode)
+	for i, v := range src.Pix {
+		if open.Pix[i] > v || closed.Pix[i] < v {
+			t.Fatalf("sample %d: open %d, close %d not around %d", i, open.Pix[i], closed.Pix[i], v)
+		}
+		if openTwice.Pix[i] != open.Pix[i] || closedTwice.Pix[i] != closed.Pix[i] {
+			t.Fatalf("sample %d: open or close is not idempotent", i)
+		}
+		if grad.Pix[i] != dil.Pix[i]-ero.Pix[i] {
+			t.Fatalf("sample %d: gradient = %d, want %d", i, grad.Pix[i], dil.Pix[i]-ero.Pix[i])
+		}
+		if top.Pix[i] != v-open.Pix[i] || black.Pix[i] != closed.Pix[i]-v {
+			t.Fatalf("sample %d: top-hat %d or black-hat %d mismatch", i, top.Pix[i], black.Pix[i])
+		}
+	}
+}
+
+func TestSubRegion(t *testing.T) {
+	src := randomAlpha(40, 40, 3)
+	o := Op{Mode: Close, Element: Square(3)}
+	full, _ := Apply(internal.DefaultConfig, src, src.Rect, o)
+	r := image.Rect(10, 12, 30, 25)
+	part, err := Apply(internal.DefaultConfig, src, r, o)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if part.Rect != r {
+		t.Fatalf("bounds = %v, want %v", part.Rect, r)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if part.AlphaAt(x, y) != full.AlphaAt(x, y) {
+				t.Fatalf("(%d, %d) = %v, want %v", x, y, part.AlphaAt(x, y), full.AlphaAt(x, y))
+			}
+		}
+	}
+}
+
+func TestColorImages(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 9, 9))
+	src.SetNRGBA(4, 4, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+	out, err := Apply(internal.DefaultConfig, src, src.Rect, Op{Mode: Dilate, Element: Square(1)})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if c := out.NRGBAAt(3, 5); c != (color.NRGBA{R: 200, G: 100, B: 50, A: 255}) {
+		t.Errorf("dilated NRGBA = %v", c)
+	}
+	if c := out.NRGBAAt(2, 4); c != (color.NRGBA{}) {
+		t.Errorf("outside NRGBA = %v, want transparent", c)
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, 9, 9))
+	for y := range 9 {
+		for x := range 9 {
+			rgba.SetRGBA(x, y, color.RGBA{R: uint8(x * 20), A: uint8(x * 20)})
+		}
+	}
+	rgba.SetRGBA(4, 4, color.RGBA{R: 255, A: 255})
+	grad, err := Apply(internal.DefaultConfig, rgba, rgba.Rect, Op{Mode: Gradient, Element: Square(1)})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	for i := 0; i < len(grad.Pix); i += 4 {
+		if grad.Pix[i] > grad.Pix[i+3] {
+			t.Fatalf("RGBA gradient pixel %d is not premultiplied: %v", i/4, grad.Pix[i:i+4])
+		}
+	}
+
+	gray := image.NewGray(image.Rect(0, 0, 5, 5))
+	gray.SetGray(2, 2, color.Gray{Y: 90})
+	eroded, err := Apply(internal.DefaultConfig, gray, gray.Rect, Op{Mode: Erode, Element: Disk(1)})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if y := eroded.GrayAt(2, 2).Y; y != 0 {
+		t.Errorf("eroded gray = %d, want 0", y)
+	}
+}
+
+func TestInvalidOp(t *testing.T) {
+	src := randomAlpha(4, 4, 4)
+	if _, err := Apply(internal.DefaultConfig, src, src.Rect, Op{Mode: Dilate}); err == nil {
+		t.Error("expected error for empty element")
+	}
+	if _, err := Apply(internal.DefaultConfig, src, src.Rect, Op{Mode: _maxMode, Element: Square(1)}); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+}
@@ -12,11 +12,13 @@ import (
 	"github.com/blazeroni/magpie/pkg/internal"
 )
 
-var _ internal.Op = (*BlendOp)(nil)
+var _ core.Op = (*BlendOp)(nil)
 
 type BlendOp struct {
 	Mode        BlendMode
 	Compositing BlendCompositing
+	// Seed selects the noise pattern of Dissolve; other modes ignore it.
+	Seed uint64
 }
 
 type BlendMode int
@@ -43,12 +45,34 @@ const (
 	Subtract
 	VividLight
 
+	// Modes are appended here so that the values of existing modes don't change.
+	Normal
+	AdditiveSubtractive
+	Average
+	Freeze
+	GeometricMean
+	Glow
+	GrainExtract
+	GrainMerge
+	Heat
+	Negation
+	Phoenix
+	Reflect
+	SoftLightIllusions
+	SoftLightPegtop
+	SoftLightW3C
+	VividLightGIMP
+	DarkerColor
+	LighterColor
+	Dissolve
+
 	_maxBlendMode
 )
 
 // Aliases for BlendModes defined above.
 const (
-	Add = LinearDodge
+	Add             = LinearDodge
+	LinearLightGIMP = LinearLight
 )
 
 const (
@@ -59,6 +83,9 @@ const (
 )
 
 func (o BlendOp) ApplyNRGBA(pixIter core.PixelIterator, calc core.PixCalculator[*image.NRGBA]) *image.NRGBA {
+	if o.Mode == Dissolve {
+		return nrgba.BlendDissolve(pixIter, calc, o.Compositing, o.Seed)
+	}
 	f := nrgbaBlendFuncs[o.Mode]
 	if f != nil {
 		f(pixIter, calc, o.Compositing)
@@ -67,6 +94,9 @@ func (o BlendOp) ApplyNRGBA(pixIter core.PixelIterator, calc core.PixCalculator[
 }
 
 func (o BlendOp) ApplyRGBA(pixIter core.PixelIterator, calc core.PixCalculator[*image.RGBA]) *image.RGBA {
+	if o.Mode == Dissolve {
+		return rgba.BlendDissolve(pixIter, calc, o.Compositing, o.Seed)
+	}
 	f := rgbaBlendFuncs[o.Mode]
 	if f != nil {
 		f(pixIter, calc, o.Compositing)
@@ -75,10 +105,11 @@ func (o BlendOp) ApplyRGBA(pixIter core.PixelIterator, calc core.PixCalculator[*
 }
 
 func (o BlendOp) IsValid() bool {
-	if o.Mode < 0 || o.Mode >= _maxBlendMode {
-		return false
-	}
-	switch o.Compositing {
+	return o.Mode >= 0 && o.Mode < _maxBlendMode && isValidCompositing(o.Compositing)
+}
+
+func isValidCompositing(c BlendCompositing) bool {
+	switch c {
 	case CompositeAll, CompositeBlendOnly, CompositeBlendAndDst, CompositeBlendAndSrc:
 		return true
 	default:
@@ -87,45 +118,81 @@ func (o BlendOp) IsValid() bool {
 }
 
 var nrgbaBlendFuncs = []func(core.PixelIterator, core.PixCalculator[*image.NRGBA], BlendCompositing) *image.NRGBA{
-	ColorBurn:   nrgba.BlendColorBurn,
-	ColorDodge:  nrgba.BlendColorDodge,
-	Darken:      nrgba.BlendDarken,
-	Difference:  nrgba.BlendDifference,
-	Divide:      nrgba.BlendDivide,
-	Exclusion:   nrgba.BlendExclusion,
-	HardLight:   nrgba.BlendHardLight,
-	HardMix:     nrgba.BlendHardMix,
-	Lighten:     nrgba.BlendLighten,
-	LinearBurn:  nrgba.BlendLinearBurn,
-	LinearDodge: nrgba.BlendLinearDodge,
-	LinearLight: nrgba.BlendLinearLight,
-	Multiply:    nrgba.BlendMultiply,
-	Overlay:     nrgba.BlendOverlay,
-	PinLight:    nrgba.BlendPinLight,
-	Screen:      nrgba.BlendScreen,
-	SoftLight:   nrgba.BlendSoftLight,
-	Subtract:    nrgba.BlendSubtract,
-	VividLight:  nrgba.BlendVividLight,
+	AdditiveSubtractive: nrgba.BlendAdditiveSubtractive,
+	Average:             nrgba.BlendAverage,
+	ColorBurn:           nrgba.BlendColorBurn,
+	ColorDodge:          nrgba.BlendColorDodge,
+	Darken:              nrgba.BlendDarken,
+	DarkerColor:         nrgba.BlendDarkerColor,
+	Difference:          nrgba.BlendDifference,
+	Divide:              nrgba.BlendDivide,
+	Exclusion:           nrgba.BlendExclusion,
+	Freeze:              nrgba.BlendFreeze,
+	GeometricMean:       nrgba.BlendGeometricMean,
+	Glow:                nrgba.BlendGlow,
+	GrainExtract:        nrgba.BlendGrainExtract,
+	GrainMerge:          nrgba.BlendGrainMerge,
+	HardLight:           nrgba.BlendHardLight,
+	HardMix:             nrgba.BlendHardMix,
+	Heat:                nrgba.BlendHeat,
+	Lighten:             nrgba.BlendLighten,
+	LighterColor:        nrgba.BlendLighterColor,
+	LinearBurn:          nrgba.BlendLinearBurn,
+	LinearDodge:         nrgba.BlendLinearDodge,
+	LinearLight:         nrgba.BlendLinearLight,
+	Multiply:            nrgba.BlendMultiply,
+	Negation:            nrgba.BlendNegation,
+	Normal:              nrgba.BlendNormal,
+	Overlay:             nrgba.BlendOverlay,
+	Phoenix:             nrgba.BlendPhoenix,
+	PinLight:            nrgba.BlendPinLight,
+	Reflect:             nrgba.BlendReflect,
+	Screen:              nrgba.BlendScreen,
+	SoftLight:           nrgba.BlendSoftLight,
+	SoftLightIllusions:  nrgba.BlendSoftLightIllusions,
+	SoftLightPegtop:     nrgba.BlendSoftLightPegtop,
+	SoftLightW3C:        nrgba.BlendSoftLightW3C,
+	Subtract:            nrgba.BlendSubtract,
+	VividLight:          nrgba.BlendVividLight,
+	VividLightGIMP:      nrgba.BlendVividLightGIMP,
 }
 
 var rgbaBlendFuncs = []func(core.PixelIterator, core.PixCalculator[*image.RGBA], BlendCompositing) *image.RGBA{
-	ColorBurn:   rgba.BlendColorBurn,
-	ColorDodge:  rgba.BlendColorDodge,
-	Darken:      rgba.BlendDarken,
-	Difference:  rgba.BlendDifference,
-	Divide:      rgba.BlendDivide,
-	Exclusion:   rgba.BlendExclusion,
-	HardLight:   rgba.BlendHardLight,
-	HardMix:     rgba.BlendHardMix,
-	Lighten:     rgba.BlendLighten,
-	LinearBurn:  rgba.BlendLinearBurn,
-	LinearDodge: rgba.BlendLinearDodge,
-	LinearLight: rgba.BlendLinearLight,
-	Multiply:    rgba.BlendMultiply,
-	Overlay:     rgba.BlendOverlay,
-	PinLight:    rgba.BlendPinLight,
-	Screen:      rgba.BlendScreen,
-	SoftLight:   rgba.BlendSoftLight,
-	Subtract:    rgba.BlendSubtract,
-	VividLight:  rgba.BlendVividLight,
+	AdditiveSubtractive: rgba.BlendAdditiveSubtractive,
+	Average:             rgba.BlendAverage,
+	ColorBurn:           rgba.BlendColorBurn,
+	ColorDodge:          rgba.BlendColorDodge,
+	Darken:              rgba.BlendDarken,
+	DarkerColor:         rgba.BlendDarkerColor,
+	Difference:          rgba.BlendDifference,
+	Divide:              rgba.BlendDivide,
+	Exclusion:           rgba.BlendExclusion,
+	Freeze:              rgba.BlendFreeze,
+	GeometricMean:       rgba.BlendGeometricMean,
+	Glow:                rgba.BlendGlow,
+	GrainExtract:        rgba.BlendGrainExtract,
+	GrainMerge:          rgba.BlendGrainMerge,
+	HardLight:           rgba.BlendHardLight,
+	HardMix:             rgba.BlendHardMix,
+	Heat:                rgba.BlendHeat,
+	Lighten:             rgba.BlendLighten,
+	LighterColor:        rgba.BlendLighterColor,
+	LinearBurn:          rgba.BlendLinearBurn,
+	LinearDodge:         rgba.BlendLinearDodge,
+	LinearLight:         rgba.BlendLinearLight,
+	Multiply:            rgba.BlendMultiply,
+	Negation:            rgba.BlendNegation,
+	Normal:              rgba.BlendNormal,
+	Overlay:             rgba.BlendOverlay,
+	Phoenix:             rgba.BlendPhoenix,
+	PinLight:            rgba.BlendPinLight,
+	Reflect:             rgba.BlendReflect,
+	Screen:              rgba.BlendScreen,
+	SoftLight:           rgba.BlendSoftLight,
+	SoftLightIllusions:  rgba.BlendSoftLightIllusions,
+	SoftLightPegtop:     rgba.BlendSoftLightPegtop,
+	SoftLightW3C:        rgba.BlendSoftLightW3C,
+	Subtract:            rgba.BlendSubtract,
+	VividLight:          rgba.BlendVividLight,
+	VividLightGIMP:      rgba.BlendVividLightGIMP,
 }
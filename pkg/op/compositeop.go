@@ -9,10 +9,9 @@ import (
 	"github.com/blazeroni/magpie/pkg/core"
 	"github.com/blazeroni/magpie/pkg/image/nrgba"
 	"github.com/blazeroni/magpie/pkg/image/rgba"
-	"github.com/blazeroni/magpie/pkg/internal"
 )
 
-var _ internal.Op = (*CompositeOp)(nil)
+var _ core.Op = (*CompositeOp)(nil)
 
 type CompositeOp struct {
 	Mode CompositeMode
@@ -0,0 +1,144 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package op
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompileCustomBlend(t *testing.T) {
+	tests := []struct {
+		expr string
+		s, d uint8
+		want uint8
+	}{
+		{"$S*$D/255", 128, 128, 64},
+		{"$S * $D / 255", 255, 100, 100},
+		{"max($S, $D)", 10, 200, 200},
+		{"min($S,$D)", 10, 200, 10},
+		{"abs($S - $D)", 10, 200, 190},
+		{"sqrt($S*$D)", 64, 255, 128},
+		{"pow($D/255, 2)*255", 0, 255, 255},
+		{"2 + 3 * 4", 0, 0, 14},
+		{"(2 + 3) * 4", 0, 0, 20},
+		{"-$S + 255", 55, 0, 200},
+		{"10 - 4 - 3", 0, 0, 3},
+		{"$S % 100", 250, 0, 50},
+		{"$S - $D", 10, 200, 0},    // clamped
+		{"$S + $D", 200, 200, 255}, // clamped
+		{"$S / 0", 100, 0, 0},
+		{"$D / 2", 0, 255, 128}, // rounded
+		{"0.5 * $S", 100, 0, 50},
+	}
+	for _, tt := range tests {
+		b, err := CompileCustomBlend(tt.expr)
+		if err != nil {
+			t.Errorf("CompileCustomBlend(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if got := b.At(tt.s, tt.d); got != tt.want {
+			t.Errorf("%q with S=%d, D=%d = %d, want %d", tt.expr, tt.s, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestCompileCustomBlendErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"$S +",
+		"$X",
+		"($S",
+		"$S $D",
+		"foo($S)",
+		"max($S)",
+		"abs $S",
+		"1.2.3",
+		"$S * #",
+	} {
+		if _, err := CompileCustomBlend(expr); err == nil {
+			t.Errorf("CompileCustomBlend(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCompileCustomBlendLimits(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("(", depth) + "$S" + strings.Repeat(")", depth)
+	}
+	if _, err := CompileCustomBlend(nested(MaxExpressionDepth)); err != nil {
+		t.Errorf("nesting %d levels deep failed: %v", MaxExpressionDepth, err)
+	}
+	for name, expr := range map[string]string{
+		"Parentheses":    nested(MaxExpressionDepth + 1),
+		"Calls":          strings.Repeat("abs(", MaxExpressionDepth+1) + "$S" + strings.Repeat(")", MaxExpressionDepth+1),
+		"Unary minus":    strings.Repeat("-", MaxExpressionDepth+1) + "$S",
+		"Too long":       strings.Repeat(" ", MaxExpressionLength) + "$S",
+		"Huge unclosed":  strings.Repeat("(", 8_000_000),
+		"Long and valid": strings.Repeat("$S+", 10_000) + "$S",
+	} {
+		if _, err := CompileCustomBlend(expr); err == nil {
+			t.Errorf("%s: CompileCustomBlend succeeded, want an error", name)
+		}
+	}
+
+	// The longest expressions compile in bounded time.
+	start := time.Now()
+	b, err := CompileCustomBlend(strings.Repeat("$S+", MaxExpressionLength/3-1) + "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.At(0, 0); got != 0 {
+		t.Errorf("At(0, 0) = %d, want 0", got)
+	}
+	if got := b.At(1, 0); got != 255 {
+		t.Errorf("At(1, 0) = %d, want 255", got)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("compiling took %v", elapsed)
+	}
+}
+
+func TestExprConstantFolding(t *testing.T) {
+	prog, err := parseExpr("$S * ((1 + 2) * -3 + max(4, 5)) / 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// $S, the folded constant -4, *, 2 and /.
+	if len(prog.code) != 5 || prog.code[1].op != exprConst || prog.code[1].val != -4 {
+		t.Errorf("got %d instructions %+v, want the constants folded", len(prog.code), prog.code)
+	}
+	if got := prog.eval(10, 0, make([]float64, prog.stack)); got != -20 {
+		t.Errorf("eval = %v, want -20", got)
+	}
+}
+
+func TestNewCustomBlend(t *testing.T) {
+	b := NewCustomBlend(func(s, d uint8) uint8 { return s ^ d })
+	for _, p := range [][2]uint8{{0, 0}, {255, 0}, {0x0f, 0xf0}, {200, 100}} {
+		if got := b.At(p[0], p[1]); got != p[0]^p[1] {
+			t.Errorf("At(%d, %d) = %d, want %d", p[0], p[1], got, p[0]^p[1])
+		}
+	}
+}
+
+func TestCustomBlendOp_IsValid(t *testing.T) {
+	b := NewCustomBlend(func(s, _ uint8) uint8 { return s })
+	tests := []struct {
+		name string
+		op   CustomBlendOp
+		want bool
+	}{
+		{"Valid", CustomBlendOp{Blend: b, Compositing: CompositeAll}, true},
+		{"BlendOnly", CustomBlendOp{Blend: b, Compositing: CompositeBlendOnly}, true},
+		{"Nil blend", CustomBlendOp{Compositing: CompositeAll}, false},
+		{"Invalid compositing", CustomBlendOp{Blend: b, Compositing: 0}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.op.IsValid(); got != tt.want {
+			t.Errorf("%s: IsValid() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
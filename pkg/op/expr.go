@@ -0,0 +1,362 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package op
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Limits of the expressions of CompileCustomBlend. They bound the time and the stack needed to
+// compile an expression, which matters when expressions come from untrusted input.
+const (
+	// MaxExpressionLength is the maximum length of an expression in bytes.
+	MaxExpressionLength = 1024
+	// MaxExpressionDepth is the maximum nesting depth of parentheses, function calls and unary
+	// minus signs in an expression.
+	MaxExpressionDepth = 64
+)
+
+// exprFuncs are the functions available to expressions, by name and number of arguments.
+var exprFuncs = map[string]struct {
+	args int
+	fn   func(args []float64) float64
+}{
+	"min":  {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":  {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+	"abs":  {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"sqrt": {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"pow":  {2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+}
+
+// exprOp is an operation of an expression.
+type exprOp uint8
+
+const (
+	exprConst exprOp = iota
+	exprS
+	exprD
+	exprAdd
+	exprSub
+	exprMul
+	exprDiv
+	exprMod
+	exprNeg
+	exprCall
+)
+
+// exprNode is a node of the syntax tree of an expression.
+type exprNode struct {
+	op   exprOp
+	val  float64                      // value of exprConst
+	fn   func(args []float64) float64 // function of exprCall
+	args []*exprNode
+}
+
+// newExprNode returns a node applying op to args. Nodes whose arguments are all constants are
+// folded into a constant.
+func newExprNode(op exprOp, fn func([]float64) float64, args ...*exprNode) *exprNode {
+	n := &exprNode{op: op, fn: fn, args: args}
+	for _, a := range args {
+		if a.op != exprConst {
+			return n
+		}
+	}
+	prog := compileExpr(n)
+	return &exprNode{op: exprConst, val: prog.eval(0, 0, make([]float64, prog.stack))}
+}
+
+// exprInstr is an instruction of a compiled expression.
+type exprInstr struct {
+	op   exprOp
+	val  float64
+	fn   func(args []float64) float64
+	args int // number of arguments of exprCall
+}
+
+// exprProgram is an expression compiled to instructions of a stack machine in postfix order,
+// which evaluates without recursion or allocations.
+type exprProgram struct {
+	code  []exprInstr
+	stack int // stack size needed by eval
+}
+
+func compileExpr(n *exprNode) *exprProgram {
+	p := &exprProgram{}
+	p.emit(n, 0)
+	return p
+}
+
+// emit appends the instructions of n, which starts with depth values on the stack.
+func (p *exprProgram) emit(n *exprNode, depth int) {
+	for i, a := range n.args {
+		p.emit(a, depth+i)
+	}
+	p.code = append(p.code, exprInstr{op: n.op, val: n.val, fn: n.fn, args: len(n.args)})
+	p.stack = max(p.stack, depth+1)
+}
+
+// eval evaluates the program for a source and destination value. stack must hold at least
+// p.stack values.
+func (p *exprProgram) eval(s, d float64, stack []float64) float64 {
+	sp := 0
+	for _, in := range p.code {
+		switch in.op {
+		case exprConst:
+			stack[sp] = in.val
+			sp++
+		case exprS:
+			stack[sp] = s
+			sp++
+		case exprD:
+			stack[sp] = d
+			sp++
+		case exprNeg:
+			stack[sp-1] = -stack[sp-1]
+		case exprCall:
+			sp -= in.args
+			stack[sp] = in.fn(stack[sp : sp+in.args])
+			sp++
+		default:
+			sp--
+			a, b := stack[sp-1], stack[sp]
+			switch in.op {
+			case exprAdd:
+				a += b
+			case exprSub:
+				a -= b
+			case exprMul:
+				a *= b
+			case exprDiv:
+				if b == 0 {
+					a = 0
+				} else {
+					a /= b
+				}
+			case exprMod:
+				if b == 0 {
+					a = 0
+				} else {
+					a = math.Mod(a, b)
+				}
+			}
+			stack[sp-1] = a
+		}
+	}
+	return stack[0]
+}
+
+// exprParser is a recursive descent parser for the expressions of CompileCustomBlend:
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/" | "%") unary }
+//	unary   = "-" unary | primary
+//	primary = number | "$S" | "$D" | name "(" expr { "," expr } ")" | "(" expr ")"
+type exprParser struct {
+	src   string
+	pos   int
+	depth int
+}
+
+func parseExpr(src string) (*exprProgram, error) {
+	if len(src) > MaxExpressionLength {
+		return nil, fmt.Errorf("custom blend expression: longer than %d bytes", MaxExpressionLength)
+	}
+	p := &exprParser{src: src}
+	n, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos])
+	}
+	return compileExpr(n), nil
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("custom blend expression: %s at offset %d", fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// accept consumes c if it is the next non-space character.
+func (p *exprParser) accept(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// enter increments the nesting depth, failing beyond MaxExpressionDepth. Every call must be
+// paired with a deferred leave.
+func (p *exprParser) enter() error {
+	p.depth++
+	if p.depth > MaxExpressionDepth {
+		return p.errorf("nested more than %d levels deep", MaxExpressionDepth)
+	}
+	return nil
+}
+
+func (p *exprParser) leave() {
+	p.depth--
+}
+
+func (p *exprParser) expr() (*exprNode, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op exprOp
+		switch {
+		case p.accept('+'):
+			op = exprAdd
+		case p.accept('-'):
+			op = exprSub
+		default:
+			return left, nil
+		}
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = newExprNode(op, nil, left, right)
+	}
+}
+
+func (p *exprParser) term() (*exprNode, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op exprOp
+		switch {
+		case p.accept('*'):
+			op = exprMul
+		case p.accept('/'):
+			op = exprDiv
+		case p.accept('%'):
+			op = exprMod
+		default:
+			return left, nil
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = newExprNode(op, nil, left, right)
+	}
+}
+
+func (p *exprParser) unary() (*exprNode, error) {
+	if p.accept('-') {
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return newExprNode(exprNeg, nil, x), nil
+	}
+	return p.primary()
+}
+
+func (p *exprParser) primary() (*exprNode, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '(':
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		p.pos++
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(')') {
+			return nil, p.errorf("missing )")
+		}
+		return x, nil
+	case c == '$':
+		if p.pos+1 < len(p.src) {
+			switch p.src[p.pos+1] {
+			case 'S':
+				p.pos += 2
+				return &exprNode{op: exprS}, nil
+			case 'D':
+				p.pos += 2
+				return &exprNode{op: exprD}, nil
+			}
+		}
+		return nil, p.errorf("unknown variable; use $S or $D")
+	case c >= '0' && c <= '9' || c == '.':
+		start := p.pos
+		for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		lit := p.src[start:p.pos]
+		v, err := strconv.ParseFloat(lit, 64)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("invalid number %q", lit)
+		}
+		return &exprNode{op: exprConst, val: v}, nil
+	case c >= 'a' && c <= 'z':
+		return p.call()
+	default:
+		return nil, p.errorf("unexpected %q", c)
+	}
+}
+
+func (p *exprParser) call() (*exprNode, error) {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] >= 'a' && p.src[p.pos] <= 'z' {
+		p.pos++
+	}
+	name := p.src[start:p.pos]
+	f, ok := exprFuncs[name]
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("unknown function %q", name)
+	}
+	if !p.accept('(') {
+		return nil, p.errorf("missing ( after %s", name)
+	}
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	var args []*exprNode
+	for {
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, x)
+		if !p.accept(',') {
+			break
+		}
+	}
+	if !p.accept(')') {
+		return nil, p.errorf("missing )")
+	}
+	if len(args) != f.args {
+		return nil, p.errorf("%s takes %d arguments, got %d", name, f.args, len(args))
+	}
+	return newExprNode(exprCall, f.fn, args...), nil
+}
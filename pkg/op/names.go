@@ -0,0 +1,235 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package op
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+// The names of modes are stable: they are used by MarshalText and recipes stored outside of
+// the program, so existing names must not change when modes are added.
+
+var blendModeNames = [...]string{
+	AdditiveSubtractive: "additive-subtractive",
+	Average:             "average",
+	ColorBurn:           "color-burn",
+	ColorDodge:          "color-dodge",
+	Darken:              "darken",
+	DarkerColor:         "darker-color",
+	Difference:          "difference",
+	Dissolve:            "dissolve",
+	Divide:              "divide",
+	Exclusion:           "exclusion",
+	Freeze:              "freeze",
+	GeometricMean:       "geometric-mean",
+	Glow:                "glow",
+	GrainExtract:        "grain-extract",
+	GrainMerge:          "grain-merge",
+	HardLight:           "hard-light",
+	HardMix:             "hard-mix",
+	Heat:                "heat",
+	Lighten:             "lighten",
+	LighterColor:        "lighter-color",
+	LinearBurn:          "linear-burn",
+	LinearDodge:         "linear-dodge",
+	LinearLight:         "linear-light",
+	Multiply:            "multiply",
+	Negation:            "negation",
+	Normal:              "normal",
+	Overlay:             "overlay",
+	Phoenix:             "phoenix",
+	PinLight:            "pin-light",
+	Reflect:             "reflect",
+	Screen:              "screen",
+	SoftLight:           "soft-light",
+	SoftLightIllusions:  "soft-light-illusions",
+	SoftLightPegtop:     "soft-light-pegtop",
+	SoftLightW3C:        "soft-light-w3c",
+	Subtract:            "subtract",
+	VividLight:          "vivid-light",
+	VividLightGIMP:      "vivid-light-gimp",
+}
+
+// blendModeAliases are accepted by ParseBlendMode in addition to the names of the modes.
+var blendModeAliases = map[string]BlendMode{
+	"add":               Add,
+	"linear-light-gimp": LinearLightGIMP,
+}
+
+// cssBlendModes maps the keywords of the CSS mix-blend-mode and background-blend-mode properties
+// to blend modes. CSS defines soft-light with the W3C formula, which is SoftLightW3C here.
+// The non-separable hue, saturation, color and luminosity keywords are not supported.
+var cssBlendModes = map[string]BlendMode{
+	"normal":      Normal,
+	"multiply":    Multiply,
+	"screen":      Screen,
+	"overlay":     Overlay,
+	"darken":      Darken,
+	"lighten":     Lighten,
+	"color-dodge": ColorDodge,
+	"color-burn":  ColorBurn,
+	"hard-light":  HardLight,
+	"soft-light":  SoftLightW3C,
+	"difference":  Difference,
+	"exclusion":   Exclusion,
+}
+
+// String returns the name of the mode, e.g. "color-dodge".
+func (m BlendMode) String() string {
+	if m >= 0 && m < _maxBlendMode {
+		return blendModeNames[m]
+	}
+	return fmt.Sprintf("BlendMode(%d)", int(m))
+}
+
+// MarshalText implements encoding.TextMarshaler. It fails for invalid modes.
+func (m BlendMode) MarshalText() ([]byte, error) {
+	if m < 0 || m >= _maxBlendMode {
+		return nil, fmt.Errorf("invalid blend mode %d", int(m))
+	}
+	return []byte(blendModeNames[m]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. See ParseBlendMode.
+func (m *BlendMode) UnmarshalText(text []byte) error {
+	v, err := ParseBlendMode(string(text))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// ParseBlendMode returns the blend mode with the given name, ignoring case. It accepts the names
+// returned by String and the aliases "add" and "linear-light-gimp".
+//
+// Note that "soft-light" is SoftLight; use ParseCSSBlendMode for CSS and SVG keywords.
+func ParseBlendMode(s string) (BlendMode, error) {
+	lower := strings.ToLower(s)
+	for m, name := range blendModeNames {
+		if lower == name {
+			return BlendMode(m), nil
+		}
+	}
+	if m, ok := blendModeAliases[lower]; ok {
+		return m, nil
+	}
+	return 0, fmt.Errorf("unknown blend mode %q", s)
+}
+
+// ParseCSSBlendMode returns the blend mode for a keyword of the CSS mix-blend-mode property,
+// ignoring case. SVG feBlend uses the same keywords for its mode attribute.
+func ParseCSSBlendMode(s string) (BlendMode, error) {
+	if m, ok := cssBlendModes[strings.ToLower(s)]; ok {
+		return m, nil
+	}
+	return 0, fmt.Errorf("unsupported CSS blend mode %q", s)
+}
+
+// CSSName returns the keyword of the CSS mix-blend-mode property and SVG feBlend mode attribute
+// for the mode, and false if CSS has no equivalent.
+func (m BlendMode) CSSName() (string, bool) {
+	for name, mode := range cssBlendModes {
+		if mode == m {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+var compositeModeNames = [...]string{
+	Clear:           "clear",
+	Source:          "source",
+	SourceOver:      "source-over",
+	SourceIn:        "source-in",
+	SourceOut:       "source-out",
+	SourceAtop:      "source-atop",
+	Destination:     "destination",
+	DestinationOver: "destination-over",
+	DestinationIn:   "destination-in",
+	DestinationOut:  "destination-out",
+	DestinationAtop: "destination-atop",
+	Xor:             "xor",
+}
+
+// svgCompositeOperators maps the operators of SVG feComposite to composite modes, with the
+// first input as source and the second as destination. arithmetic and lighter are not supported.
+var svgCompositeOperators = map[string]CompositeMode{
+	"over": SourceOver,
+	"in":   SourceIn,
+	"out":  SourceOut,
+	"atop": SourceAtop,
+	"xor":  Xor,
+}
+
+// String returns the name of the mode, e.g. "source-over".
+func (m CompositeMode) String() string {
+	if m >= 0 && m < _maxCompositeMode {
+		return compositeModeNames[m]
+	}
+	return fmt.Sprintf("CompositeMode(%d)", int(m))
+}
+
+// MarshalText implements encoding.TextMarshaler. It fails for invalid modes.
+func (m CompositeMode) MarshalText() ([]byte, error) {
+	if m < 0 || m >= _maxCompositeMode {
+		return nil, fmt.Errorf("invalid composite mode %d", int(m))
+	}
+	return []byte(compositeModeNames[m]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. See ParseCompositeMode.
+func (m *CompositeMode) UnmarshalText(text []byte) error {
+	v, err := ParseCompositeMode(string(text))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// ParseCompositeMode returns the composite mode with the given name, ignoring case. It accepts
+// the names returned by String, which are also the CSS compositing keywords, and "copy", the CSS
+// name of Source.
+func ParseCompositeMode(s string) (CompositeMode, error) {
+	lower := strings.ToLower(s)
+	if lower == "copy" {
+		return Source, nil
+	}
+	for m, name := range compositeModeNames {
+		if lower == name {
+			return CompositeMode(m), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown composite mode %q", s)
+}
+
+// ParseSVGCompositeOperator returns the composite mode for an operator of SVG feComposite,
+// ignoring case.
+func ParseSVGCompositeOperator(s string) (CompositeMode, error) {
+	if m, ok := svgCompositeOperators[strings.ToLower(s)]; ok {
+		return m, nil
+	}
+	return 0, fmt.Errorf("unsupported SVG composite operator %q", s)
+}
+
+// SVGOperator returns the operator of SVG feComposite for the mode, and false if SVG has no
+// equivalent.
+func (m CompositeMode) SVGOperator() (string, bool) {
+	for name, mode := range svgCompositeOperators {
+		if mode == m {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// ParseBlendCompositing returns the compositing with the given name, ignoring case: "all",
+// "blend-only", "blend-and-dst" or "blend-and-src".
+func ParseBlendCompositing(s string) (BlendCompositing, error) {
+	return internal.ParseBlendCompositing(s)
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package op
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBlendModeNames(t *testing.T) {
+	if len(blendModeNames) != int(_maxBlendMode) {
+		t.Fatalf("%d blend mode names for %d modes", len(blendModeNames), _maxBlendMode)
+	}
+	seen := make(map[string]bool)
+	for m := range _maxBlendMode {
+		name := m.String()
+		if name == "" || seen[name] {
+			t.Errorf("blend mode %d has the missing or duplicate name %q", m, name)
+		}
+		seen[name] = true
+
+		text, err := m.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%v) failed: %v", m, err)
+		}
+		var got BlendMode
+		if err := got.UnmarshalText(text); err != nil || got != m {
+			t.Errorf("UnmarshalText(%q) = %v, %v; want %v", text, got, err, m)
+		}
+	}
+
+	for s, want := range map[string]BlendMode{"Color-Dodge": ColorDodge, "add": LinearDodge, "LINEAR-LIGHT-GIMP": LinearLight, "soft-light": SoftLight} {
+		if got, err := ParseBlendMode(s); err != nil || got != want {
+			t.Errorf("ParseBlendMode(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "hue", "softlight"} {
+		if _, err := ParseBlendMode(s); err == nil {
+			t.Errorf("ParseBlendMode(%q) should fail", s)
+		}
+	}
+	if _, err := _maxBlendMode.MarshalText(); err == nil {
+		t.Error("MarshalText should fail for an invalid mode")
+	}
+	if s := BlendMode(-1).String(); s != "BlendMode(-1)" {
+		t.Errorf("String() = %q for an invalid mode", s)
+	}
+}
+
+func TestBlendModeValues(t *testing.T) {
+	// Modes are stored by value, so existing values must not change when modes are added.
+	for m, want := range map[BlendMode]int{ColorBurn: 0, Multiply: 12, VividLight: 18, Normal: 19} {
+		if int(m) != want {
+			t.Errorf("%v = %d, want %d", m, int(m), want)
+		}
+	}
+}
+
+func TestCSSBlendModes(t *testing.T) {
+	for s, want := range map[string]BlendMode{"normal": Normal, "Soft-Light": SoftLightW3C, "color-burn": ColorBurn} {
+		if got, err := ParseCSSBlendMode(s); err != nil || got != want {
+			t.Errorf("ParseCSSBlendMode(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"hue", "luminosity", "linear-dodge"} {
+		if _, err := ParseCSSBlendMode(s); err == nil {
+			t.Errorf("ParseCSSBlendMode(%q) should fail", s)
+		}
+	}
+	for name, m := range cssBlendModes {
+		if got, ok := m.CSSName(); !ok || got != name {
+			t.Errorf("%v.CSSName() = %q, %v; want %q", m, got, ok, name)
+		}
+	}
+	if _, ok := SoftLight.CSSName(); ok {
+		t.Error("SoftLight should not have a CSS name")
+	}
+}
+
+func TestCompositeModeNames(t *testing.T) {
+	if len(compositeModeNames) != int(_maxCompositeMode) {
+		t.Fatalf("%d composite mode names for %d modes", len(compositeModeNames), _maxCompositeMode)
+	}
+	for m := range _maxCompositeMode {
+		text, err := m.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%v) failed: %v", m, err)
+		}
+		var got CompositeMode
+		if err := got.UnmarshalText(text); err != nil || got != m {
+			t.Errorf("UnmarshalText(%q) = %v, %v; want %v", text, got, err, m)
+		}
+	}
+	if got, err := ParseCompositeMode("copy"); err != nil || got != Source {
+		t.Errorf("ParseCompositeMode(copy) = %v, %v", got, err)
+	}
+	if got, err := ParseSVGCompositeOperator("atop"); err != nil || got != SourceAtop {
+		t.Errorf("ParseSVGCompositeOperator(atop) = %v, %v", got, err)
+	}
+	if _, err := ParseSVGCompositeOperator("arithmetic"); err == nil {
+		t.Error("arithmetic should not be supported")
+	}
+	if name, ok := SourceOver.SVGOperator(); !ok || name != "over" {
+		t.Errorf("SourceOver.SVGOperator() = %q, %v", name, ok)
+	}
+	if _, ok := DestinationOver.SVGOperator(); ok {
+		t.Error("DestinationOver should not have an SVG operator")
+	}
+}
+
+func TestBlendCompositingNames(t *testing.T) {
+	for _, c := range []BlendCompositing{CompositeAll, CompositeBlendOnly, CompositeBlendAndDst, CompositeBlendAndSrc} {
+		got, err := ParseBlendCompositing(c.String())
+		if err != nil || got != c {
+			t.Errorf("ParseBlendCompositing(%q) = %v, %v; want %v", c.String(), got, err, c)
+		}
+	}
+	if _, err := BlendCompositing(0).MarshalText(); err == nil {
+		t.Error("MarshalText should fail for an invalid compositing")
+	}
+}
+
+func TestBlendOpJSON(t *testing.T) {
+	o := BlendOp{Mode: SoftLightW3C, Compositing: CompositeBlendAndDst, Seed: 7}
+	data, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Mode":"soft-light-w3c","Compositing":"blend-and-dst","Seed":7}`; string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+	var got BlendOp
+	if err := json.Unmarshal(data, &got); err != nil || got != o {
+		t.Errorf("Unmarshal = %+v, %v; want %+v", got, err, o)
+	}
+	if err := json.Unmarshal([]byte(`{"Mode":"bogus"}`), &got); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package magpie
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+// Pipeline chains per-pixel operations and runs them in a single pass over the image.
+//
+// Chaining Draw calls writes a full intermediate image between every step, and reads it back for
+// the next one. A Pipeline instead processes one row at a time: the row is loaded into a scratch
+// buffer, every stage is applied to it in turn, and only the final result is written to the output.
+// The scratch buffers are reused between rows, so the memory traffic and allocations no longer grow
+// with the number of stages.
+//
+// The row being processed is called the stream. It starts as the destination image passed to Run
+// and each stage replaces it:
+//
+//   - Draw applies an op with the stream as dst and another image as src.
+//   - DrawOnto applies an op with another image as dst and the stream as src, e.g. to composite
+//     the result over a background.
+//   - LUT, Levels and Map transform every pixel of the stream on its own.
+//
+// Any core.Op can be used as a stage. Ops see one row at a time and must not depend on pixels of
+// other rows, which holds for the blend and composite operations of the op package.
+//
+// A Pipeline is built once and can be run any number of times, also concurrently.
+type Pipeline struct {
+	stages []pipelineStage
+}
+
+// pipelineStage is a single step of a Pipeline. Op stages set op and img; point stages set fn.
+type pipelineStage struct {
+	op   core.Op
+	img  image.Image
+	pt   image.Point
+	onto bool
+	fn   func(c color.NRGBA) color.NRGBA
+	err  error
+}
+
+// NewPipeline creates an empty Pipeline. Running an empty Pipeline copies the destination to the output.
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// Draw adds a stage that applies op with the stream as dst and src as src.
+// The point sp of src is aligned with r.Min of Run, as in Draw.
+// Pixels outside of src are treated as transparent.
+func (p *Pipeline) Draw(op core.Op, src image.Image, sp image.Point) *Pipeline {
+	p.stages = append(p.stages, pipelineStage{op: op, img: src, pt: sp})
+	return p
+}
+
+// DrawOnto adds a stage that applies op with dst as dst and the stream as src.
+// The point dp of dst is aligned with r.Min of Run. Pixels outside of dst are treated as transparent.
+func (p *Pipeline) DrawOnto(op core.Op, dst image.Image, dp image.Point) *Pipeline {
+	p.stages = append(p.stages, pipelineStage{op: op, img: dst, pt: dp, onto: true})
+	return p
+}
+
+// LUT adds a stage that maps every channel of every pixel through a lookup table.
+// The tables apply to non-premultiplied values; a nil table leaves its channel unchanged.
+func (p *Pipeline) LUT(r, g, b, a *[256]uint8) *Pipeline {
+	var lut [4][256]uint8
+	for c, table := range [4]*[256]uint8{r, g, b, a} {
+		for i := range lut[c] {
+			lut[c][i] = uint8(i)
+		}
+		if table != nil {
+			lut[c] = *table
+		}
+	}
+	return p.Map(func(c color.NRGBA) color.NRGBA {
+		return color.NRGBA{R: lut[0][c.R], G: lut[1][c.G], B: lut[2][c.B], A: lut[3][c.A]}
+	})
+}
+
+// Levels adds a stage that stretches the color channels so that black maps to 0 and white to 255,
+// then applies a gamma correction. A gamma above 1 brightens the midtones. Alpha is unchanged.
+// Running the Pipeline fails if white is not greater than black or gamma is not positive.
+func (p *Pipeline) Levels(black, white uint8, gamma float64) *Pipeline {
+	if white <= black || !(gamma > 0) {
+		p.stages = append(p.stages, pipelineStage{err: fmt.Errorf("invalid levels %d, %d, %v", black, white, gamma)})
+		return p
+	}
+	var lut [256]uint8
+	for i := range lut {
+		v := (float64(i) - float64(black)) / float64(white-black)
+		v = math.Pow(core.Clamp(v, 0, 1), 1/gamma)
+		lut[i] = uint8(v*255 + 0.5)
+	}
+	return p.LUT(&lut, &lut, &lut, nil)
+}
+
+// Map adds a stage that replaces every pixel with the result of fn. The colors are not premultiplied.
+// fn may be called concurrently and should not depend on the order of the pixels.
+func (p *Pipeline) Map(fn func(c color.NRGBA) color.NRGBA) *Pipeline {
+	p.stages = append(p.stages, pipelineStage{fn: fn})
+	return p
+}
+
+// Run runs the Pipeline over the rectangle r of dst using the default context.
+// See RunWith for details.
+func (p *Pipeline) Run(dst image.Image, r image.Rectangle, output core.Output) (image.Image, error) {
+	return p.RunWith(defaultContext, dst, r, output)
+}
+
+// RunWith runs the Pipeline over the rectangle r of dst and writes the result to output,
+// which behaves as in Draw. The rows are distributed with the PixelIterator of cfg.
+//
+// The color model is the one of the output if it is supported, then the one of dst, then the
+// default color model of cfg. Images of another model are converted once per run. The output
+// image must not be the image of a stage, since rows of the output are written while the
+// following rows of the stages are still to be read; dst itself may be the output.
+func (p *Pipeline) RunWith(cfg core.Config, dst image.Image, r image.Rectangle, output core.Output) (image.Image, error) {
+	for _, s := range p.stages {
+		switch {
+		case s.err != nil:
+			return nil, s.err
+		case s.fn == nil && (s.op == nil || !s.op.IsValid()):
+			return nil, errors.New("invalid operation")
+		case s.fn == nil && s.img == nil:
+			return nil, errors.New("pipeline stage image is nil")
+		}
+	}
+
+	model := cfg.DefaultColorModel()
+	switch {
+	case output != nil && core.IsColorModelSupported(output.ColorModel()):
+		model = output.ColorModel()
+	case core.IsColorModelSupported(dst.ColorModel()):
+		model = dst.ColorModel()
+	}
+	out, outPt, err := core.ResolveOutput(output, cfg.DefaultOutputMode(), dst, r, model)
+	if err != nil {
+		return nil, err
+	}
+
+	run := pipelineRun{stages: make([]boundStage, len(p.stages))}
+	switch model {
+	case color.RGBAModel:
+		outRGBA, ok := out.(*image.RGBA)
+		if !ok {
+			return nil, fmt.Errorf("unsupported output color model %v", out.ColorModel())
+		}
+		run.premultiplied = true
+		run.dst = newPixPlane(AsRGBA(dst))
+		run.out = newPixPlane(outRGBA)
+		for i, s := range p.stages {
+			run.stages[i].pipelineStage = s
+			if s.img != nil {
+				run.stages[i].plane = newPixPlane(AsRGBA(s.img))
+			}
+		}
+	case color.NRGBAModel:
+		outNRGBA, ok := out.(*image.NRGBA)
+		if !ok {
+			return nil, fmt.Errorf("unsupported output color model %v", out.ColorModel())
+		}
+		run.dst = newPixPlane(AsNRGBA(dst))
+		run.out = newPixPlane(outNRGBA)
+		for i, s := range p.stages {
+			run.stages[i].pipelineStage = s
+			if s.img != nil {
+				run.stages[i].plane = newPixPlane(AsNRGBA(s.img))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported color model %v", model)
+	}
+
+	// Stage points and outPt are aligned with the requested r.Min, before clipping.
+	outOffset := outPt.Sub(r.Min)
+	for i := range run.stages {
+		run.stages[i].offset = run.stages[i].pt.Sub(r.Min)
+	}
+	r = r.Intersect(dst.Bounds()).Intersect(out.Bounds().Sub(outOffset))
+	if r.Empty() {
+		return out, nil
+	}
+	run.rect, run.outOffset = r, outOffset
+
+	n := r.Dx() * 4
+	run.buffers.New = func() any {
+		buf := make([]uint8, 2*n)
+		return &pipelineBuffers{cur: buf[:n:n], scratch: buf[n:]}
+	}
+	core.IterateRows(cfg.PixelIterator(), r.Dy(), run.row)
+	return out, nil
+}
+
+// pixPlane is the Pix slice of an NRGBA or RGBA image with its layout.
+type pixPlane struct {
+	pix    []uint8
+	stride int
+	rect   image.Rectangle
+}
+
+func newPixPlane(img image.Image) pixPlane {
+	switch img := img.(type) {
+	case *image.NRGBA:
+		return pixPlane{pix: img.Pix, stride: img.Stride, rect: img.Rect}
+	case *image.RGBA:
+		return pixPlane{pix: img.Pix, stride: img.Stride, rect: img.Rect}
+	}
+	return pixPlane{}
+}
+
+// offset returns the index of the pixel (x, y), which must be within rect.
+func (pl pixPlane) offset(x, y int) int {
+	return (y-pl.rect.Min.Y)*pl.stride + (x-pl.rect.Min.X)*4
+}
+
+// boundStage is a pipelineStage with its image converted to the color model of a run.
+type boundStage struct {
+	pipelineStage
+	plane  pixPlane
+	offset image.Point
+}
+
+// row returns the pixels of the stage image that line up with the row y of the run between x0 and x1.
+// When the row is only partially covered by the image, the pixels are copied to scratch, which is
+// cleared first so that the pixels outside of the image are transparent.
+func (s *boundStage) row(x0, x1, y int, scratch []uint8) []uint8 {
+	want := image.Rect(x0, y, x1, y+1).Add(s.offset)
+	have := want.Intersect(s.plane.rect)
+	if have == want {
+		i := s.plane.offset(want.Min.X, want.Min.Y)
+		return s.plane.pix[i : i+want.Dx()*4]
+	}
+	clear(scratch)
+	if !have.Empty() {
+		i := s.plane.offset(have.Min.X, have.Min.Y)
+		copy(scratch[(have.Min.X-want.Min.X)*4:], s.plane.pix[i:i+have.Dx()*4])
+	}
+	return scratch
+}
+
+// pipelineBuffers are the per-worker scratch buffers of a run.
+// The calculators are kept here so that running a stage does not allocate.
+type pipelineBuffers struct {
+	cur, scratch []uint8
+	nrgba        rowCalculator[*image.NRGBA]
+	rgba         rowCalculator[*image.RGBA]
+}
+
+// pipelineRun holds the state of a single Pipeline.RunWith call.
+type pipelineRun struct {
+	stages        []boundStage
+	dst, out      pixPlane
+	rect          image.Rectangle
+	outOffset     image.Point
+	premultiplied bool
+	buffers       sync.Pool
+}
+
+// row runs every stage over a single row, counted from the top of the run rectangle.
+func (run *pipelineRun) row(row int) {
+	bufs := run.buffers.Get().(*pipelineBuffers)
+	defer run.buffers.Put(bufs)
+
+	x0, x1, y := run.rect.Min.X, run.rect.Max.X, run.rect.Min.Y+row
+	cur := bufs.cur
+	i := run.dst.offset(x0, y)
+	copy(cur, run.dst.pix[i:i+len(cur)])
+
+	rect := image.Rect(x0, y, x1, y+1)
+	for s := range run.stages {
+		stage := &run.stages[s]
+		if stage.fn != nil {
+			run.mapRow(stage.fn, cur)
+			continue
+		}
+		dst, src := cur, stage.row(x0, x1, y, bufs.scratch)
+		if stage.onto {
+			dst, src = src, cur
+		}
+		if run.premultiplied {
+			bufs.rgba = rowCalculator[*image.RGBA]{rect: rect, dst: dst, src: src, out: cur}
+			stage.op.ApplyRGBA(core.SerialPixelIterator{}, &bufs.rgba)
+		} else {
+			bufs.nrgba = rowCalculator[*image.NRGBA]{rect: rect, dst: dst, src: src, out: cur}
+			stage.op.ApplyNRGBA(core.SerialPixelIterator{}, &bufs.nrgba)
+		}
+	}
+
+	o := rect.Min.Add(run.outOffset)
+	i = run.out.offset(o.X, o.Y)
+	copy(run.out.pix[i:i+len(cur)], cur)
+}
+
+// mapRow applies fn to every pixel of row, unpremultiplying the pixels first if needed.
+func (run *pipelineRun) mapRow(fn func(color.NRGBA) color.NRGBA, row []uint8) {
+	for i := 0; i < len(row); i += 4 {
+		px := row[i : i+4 : i+4]
+		c := color.NRGBA{R: px[0], G: px[1], B: px[2], A: px[3]}
+		if run.premultiplied {
+			a := uint32(c.A)
+			c.R = uint8(internal.Unpremultiply(uint32(c.R), a))
+			c.G = uint8(internal.Unpremultiply(uint32(c.G), a))
+			c.B = uint8(internal.Unpremultiply(uint32(c.B), a))
+		}
+		c = fn(c)
+		if run.premultiplied {
+			a := uint32(c.A)
+			c.R = uint8(internal.Md255(uint32(c.R), a))
+			c.G = uint8(internal.Md255(uint32(c.G), a))
+			c.B = uint8(internal.Md255(uint32(c.B), a))
+		}
+		px[0], px[1], px[2], px[3] = c.R, c.G, c.B, c.A
+	}
+}
+
+// rowCalculator is a core.PixCalculator over a single row of a Pipeline run.
+// Result returns nil since the row does not belong to an image.
+type rowCalculator[T image.Image] struct {
+	rect          image.Rectangle
+	dst, src, out []uint8
+}
+
+func (c *rowCalculator[T]) Rect() image.Rectangle {
+	return c.rect
+}
+
+func (c *rowCalculator[T]) Calculate(int) ([]uint8, []uint8, []uint8) {
+	return c.dst, c.src, c.out
+}
+
+func (c *rowCalculator[T]) Result() T {
+	var zero T
+	return zero
+}
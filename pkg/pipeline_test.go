@@ -0,0 +1,272 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package magpie
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+// noiseImage returns an image of the given model filled with random, partially transparent pixels.
+func noiseImage(model color.Model, r image.Rectangle, seed uint64) draw.Image {
+	img, _ := core.NewImage(model, r)
+	dimg := img.(draw.Image)
+	rng := rand.New(rand.NewPCG(seed, 0))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dimg.Set(x, y, color.NRGBA{R: uint8(rng.Uint32()), G: uint8(rng.Uint32()), B: uint8(rng.Uint32()), A: uint8(rng.Uint32())})
+		}
+	}
+	return dimg
+}
+
+func assertSameImage(t *testing.T, got, want image.Image) {
+	t.Helper()
+	if got.Bounds() != want.Bounds() {
+		t.Fatalf("bounds = %v, want %v", got.Bounds(), want.Bounds())
+	}
+	b := want.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if g, w := got.At(x, y), want.At(x, y); g != w {
+				t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, g, w)
+			}
+		}
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	serial := &context{config: internal.NewConfig(core.NewSerialPixelIterator(), core.DefaultOutputToNewImage, color.NRGBAModel)}
+	configs := map[string]core.Config{
+		"Serial":   serial,
+		"Parallel": internal.NewConfig(core.NewParallelPixelIterator(4), core.DefaultOutputToNewImage, color.NRGBAModel),
+	}
+	multiply := op.BlendOp{Mode: op.Multiply, Compositing: op.CompositeAll}
+	screen := op.BlendOp{Mode: op.Screen, Compositing: op.CompositeBlendAndDst}
+	over := op.CompositeOp{Mode: op.SourceOver}
+
+	for modelName, model := range map[string]color.Model{"NRGBA": color.NRGBAModel, "RGBA": color.RGBAModel} {
+		r := image.Rect(0, 0, 37, 23)
+		dst := noiseImage(model, r, 1)
+		// Draw expects images anchored at the origin; the offsets select a part of the larger ones.
+		sp, bp := image.Pt(10, 20), image.Pt(5, 7)
+		src1 := noiseImage(model, image.Rect(0, 0, 50, 50), 2)
+		src2 := noiseImage(model, r, 3)
+		bg := noiseImage(model, image.Rect(0, 0, 50, 50), 4)
+
+		// The same chain of operations with a full intermediate image after every step.
+		want, err := serial.Draw(dst, r, src1, sp, multiply, ToNewImage())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, err = serial.Draw(want, r, src2, r.Min, screen, ToNewImage()); err != nil {
+			t.Fatal(err)
+		}
+		final, _ := core.NewImage(model, r)
+		if want, err = serial.Draw(bg, r.Add(bp), want, r.Min, over, ToImage(final, r.Min)); err != nil {
+			t.Fatal(err)
+		}
+
+		p := NewPipeline().
+			Draw(multiply, src1, sp).
+			Draw(screen, src2, r.Min).
+			DrawOnto(over, bg, bp)
+
+		for name, cfg := range configs {
+			t.Run(modelName+"/"+name, func(t *testing.T) {
+				got, err := p.RunWith(cfg, dst, r, ToNewImage())
+				if err != nil {
+					t.Fatalf("RunWith failed: %v", err)
+				}
+				assertSameImage(t, got, want)
+			})
+		}
+	}
+}
+
+func TestPipeline_ToDst(t *testing.T) {
+	r := image.Rect(0, 0, 16, 16)
+	dst := noiseImage(color.RGBAModel, r, 5)
+	src := noiseImage(color.RGBAModel, r, 6)
+	o := op.BlendOp{Mode: op.Difference, Compositing: op.CompositeAll}
+
+	want, err := DrawToNewImage(dst, r, src, r.Min, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewPipeline().Draw(o, src, r.Min).Run(dst, r, ToDst())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if got != image.Image(dst) {
+		t.Fatal("expected the destination to be modified in place")
+	}
+	assertSameImage(t, got, want)
+}
+
+func TestPipeline_PartialSource(t *testing.T) {
+	r := image.Rect(0, 0, 8, 4)
+	dst := image.NewNRGBA(r)
+	draw.Draw(dst, r, image.NewUniform(color.NRGBA{R: 10, G: 20, B: 30, A: 255}), image.Point{}, draw.Src)
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	draw.Draw(src, src.Rect, image.NewUniform(color.NRGBA{R: 200, A: 255}), image.Point{}, draw.Src)
+
+	got, err := NewPipeline().
+		Draw(op.BlendOp{Mode: op.Normal, Compositing: op.CompositeAll}, src, image.Pt(-2, 0)).
+		Run(dst, r, ToNewImage())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	img := got.(*image.NRGBA)
+	for _, tt := range []struct {
+		x, y int
+		want color.NRGBA
+	}{
+		{0, 0, color.NRGBA{R: 10, G: 20, B: 30, A: 255}},
+		{2, 0, color.NRGBA{R: 200, A: 255}},
+		{3, 1, color.NRGBA{R: 200, A: 255}},
+		{2, 2, color.NRGBA{R: 10, G: 20, B: 30, A: 255}},
+		{7, 3, color.NRGBA{R: 10, G: 20, B: 30, A: 255}},
+	} {
+		if got := img.NRGBAAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("pixel (%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestPipeline_PointOps(t *testing.T) {
+	r := image.Rect(0, 0, 3, 1)
+	px := []color.NRGBA{{R: 50, G: 100, B: 150, A: 255}, {R: 200, G: 250, B: 0, A: 128}, {R: 90, A: 0}}
+
+	var invert [256]uint8
+	for i := range invert {
+		invert[i] = uint8(255 - i)
+	}
+	p := NewPipeline().
+		Levels(50, 250, 1).
+		LUT(nil, &invert, nil, nil).
+		Map(func(c color.NRGBA) color.NRGBA {
+			c.B = c.A
+			return c
+		})
+	want := []color.NRGBA{{R: 0, G: 191, B: 255, A: 255}, {R: 191, G: 0, B: 128, A: 128}, {R: 51, G: 255, B: 0, A: 0}}
+
+	t.Run("NRGBA", func(t *testing.T) {
+		dst := image.NewNRGBA(r)
+		for x, c := range px {
+			dst.SetNRGBA(x, 0, c)
+		}
+		got, err := p.Run(dst, r, ToNewImage())
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		for x, w := range want {
+			if c := got.(*image.NRGBA).NRGBAAt(x, 0); c != w {
+				t.Errorf("pixel %d = %v, want %v", x, c, w)
+			}
+		}
+	})
+
+	t.Run("RGBA", func(t *testing.T) {
+		// Only the opaque pixel round-trips exactly through premultiplication.
+		dst := image.NewRGBA(r)
+		dst.Set(0, 0, px[0])
+		got, err := p.Run(dst, image.Rect(0, 0, 1, 1), ToNewImage())
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if c := color.NRGBAModel.Convert(got.At(0, 0)); c != want[0] {
+			t.Errorf("pixel = %v, want %v", c, want[0])
+		}
+	})
+}
+
+func TestPipeline_Invalid(t *testing.T) {
+	r := image.Rect(0, 0, 2, 2)
+	dst := image.NewNRGBA(r)
+	tests := []struct {
+		name string
+		p    *Pipeline
+	}{
+		{"Nil op", NewPipeline().Draw(nil, dst, r.Min)},
+		{"Invalid op", NewPipeline().Draw(op.CustomBlendOp{Compositing: op.CompositeAll}, dst, r.Min)},
+		{"Nil image", NewPipeline().DrawOnto(op.CompositeOp{Mode: op.SourceOver}, nil, r.Min)},
+		{"Invalid levels", NewPipeline().Levels(200, 100, 1)},
+		{"Invalid gamma", NewPipeline().Levels(0, 255, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.p.Run(dst, r, ToNewImage()); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	t.Run("Unsupported output", func(t *testing.T) {
+		gray := image.NewGray(r)
+		if _, err := NewPipeline().Run(gray, r, ToDst()); err == nil {
+			t.Error("expected an error for an unsupported output image")
+		}
+	})
+}
+
+// --- Benchmarks ---
+
+// The benchmarks run three blend and composite steps over a 1024x1024 image, once as a Pipeline
+// and once as chained Draw calls with intermediate images. Bytes are per output pixel.
+
+const benchmarkSize = 1024
+
+func benchmarkImages() (dst, src1, src2, bg draw.Image) {
+	r := image.Rect(0, 0, benchmarkSize, benchmarkSize)
+	return noiseImage(color.NRGBAModel, r, 1), noiseImage(color.NRGBAModel, r, 2),
+		noiseImage(color.NRGBAModel, r, 3), noiseImage(color.NRGBAModel, r, 4)
+}
+
+func BenchmarkPipeline(b *testing.B) {
+	dst, src1, src2, bg := benchmarkImages()
+	r := dst.Bounds()
+	out := image.NewNRGBA(r)
+	p := NewPipeline().
+		Draw(op.BlendOp{Mode: op.Multiply, Compositing: op.CompositeAll}, src1, r.Min).
+		Draw(op.BlendOp{Mode: op.Screen, Compositing: op.CompositeAll}, src2, r.Min).
+		DrawOnto(op.CompositeOp{Mode: op.SourceOver}, bg, r.Min)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(out.Pix)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Run(dst, r, ToImage(out, r.Min)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDrawChain(b *testing.B) {
+	dst, src1, src2, bg := benchmarkImages()
+	r := dst.Bounds()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(r.Dx() * r.Dy() * 4))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		step, err := Draw(dst, r, src1, r.Min, op.BlendOp{Mode: op.Multiply, Compositing: op.CompositeAll}, ToNewImage())
+		if err != nil {
+			b.Fatal(err)
+		}
+		if step, err = Draw(step, r, src2, r.Min, op.BlendOp{Mode: op.Screen, Compositing: op.CompositeAll}, ToNewImage()); err != nil {
+			b.Fatal(err)
+		}
+		if _, err = Draw(bg, r, step, r.Min, op.CompositeOp{Mode: op.SourceOver}, ToNewImage()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package pyramid
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// MultiBand blends src into dst through mask using Burt–Adelson multi-band blending.
+// Each band of the Laplacian pyramids of dst and src is mixed using the matching level of
+// the Gaussian pyramid of the mask, so low frequencies transition over a wide area while
+// fine detail keeps a sharp seam. Mask coverage of 1 selects src and 0 selects dst; a nil
+// mask selects src everywhere.
+//
+// The arguments follow magpie.Draw: r is the region of dst, sp and mp are the points in src
+// and mask aligned with r.Min. A levels value of zero or less chooses the count automatically.
+func MultiBand(cfg core.Config, dst image.Image, r image.Rectangle, src image.Image, sp image.Point, mask image.Image, mp image.Point, levels int, output core.Output) (image.Image, error) {
+	orig := r.Min
+	r = r.Intersect(dst.Bounds())
+	r = r.Intersect(src.Bounds().Add(orig.Sub(sp)))
+	sp = sp.Add(r.Min.Sub(orig))
+	mp = mp.Add(r.Min.Sub(orig))
+
+	out, outPt, err := core.ResolveOutput(output, cfg.DefaultOutputMode(), dst, r, outputModel(cfg, dst, src))
+	if err != nil {
+		return nil, err
+	}
+	if r.Empty() {
+		return out, nil
+	}
+
+	pixIter := cfg.PixelIterator()
+	srcRect := image.Rectangle{Min: sp, Max: sp.Add(r.Size())}
+	la := Laplacian(pixIter, FromImage(pixIter, dst, r), levels)
+	lb := Laplacian(pixIter, FromImage(pixIter, src, srcRect), len(la))
+	gm := Gaussian(pixIter, maskLevel(pixIter, mask, mp, r.Dx(), r.Dy()), len(la))
+
+	blended := make(Pyramid, len(la))
+	for i := range la {
+		a, b, m := la[i], lb[i], gm[i]
+		core.IterateRows(pixIter, a.H, func(y int) {
+			ra, rb, rm := a.Row(y), b.Row(y), m.Row(y)
+			for x := range ra {
+				ra[x] += (rb[x] - ra[x]) * rm[x]
+			}
+		})
+		blended[i] = a
+	}
+
+	if err = blended.Collapse(pixIter).WriteTo(pixIter, out, outPt); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FusionWeights are the exponents applied to the Mertens quality measures.
+// A zero exponent disables the measure.
+type FusionWeights struct {
+	// Contrast favors pixels with strong local detail (absolute Laplacian response).
+	Contrast float64
+	// Saturation favors vivid pixels (standard deviation across the color channels).
+	Saturation float64
+	// Exposedness favors pixels close to mid-grey.
+	Exposedness float64
+}
+
+// DefaultFusionWeights weighs all quality measures equally, as in the original paper.
+var DefaultFusionWeights = FusionWeights{Contrast: 1, Saturation: 1, Exposedness: 1}
+
+// Fuse merges a bracketed exposure sequence into a single well exposed image using Mertens
+// exposure fusion. All images must cover r. Per-pixel weights are computed from the quality
+// measures in weights, normalized across the sequence and blended through Gaussian pyramids,
+// while the images themselves are blended through Laplacian pyramids.
+func Fuse(cfg core.Config, images []image.Image, r image.Rectangle, weights FusionWeights, levels int, output core.Output) (image.Image, error) {
+	if len(images) == 0 {
+		return nil, errors.New("no images to fuse")
+	}
+	for _, img := range images {
+		r = r.Intersect(img.Bounds())
+	}
+
+	out, outPt, err := core.ResolveOutput(output, cfg.DefaultOutputMode(), nil, r, outputModel(cfg, images[0], images[0]))
+	if err != nil {
+		return nil, err
+	}
+	if r.Empty() {
+		return out, nil
+	}
+
+	pixIter := cfg.PixelIterator()
+	bases := make([]*Level, len(images))
+	maps := make([]*Level, len(images))
+	for i, img := range images {
+		bases[i] = FromImage(pixIter, img, r)
+		maps[i] = qualityMap(pixIter, bases[i], weights)
+	}
+	normalizeWeights(pixIter, maps)
+
+	var fused Pyramid
+	for i := range images {
+		lp := Laplacian(pixIter, bases[i], levels)
+		gw := Gaussian(pixIter, maps[i], len(lp))
+		if fused == nil {
+			fused = make(Pyramid, len(lp))
+			for j, l := range lp {
+				fused[j] = NewLevel(l.W, l.H)
+			}
+		}
+		for j := range lp {
+			acc, band, wt := fused[j], lp[j], gw[j]
+			core.IterateRows(pixIter, acc.H, func(y int) {
+				ra, rb, rw := acc.Row(y), band.Row(y), wt.Row(y)
+				for x := range ra {
+					ra[x] += rb[x] * rw[x]
+				}
+			})
+		}
+	}
+
+	if err = fused.Collapse(pixIter).WriteTo(pixIter, out, outPt); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// qualityMap computes the unnormalized Mertens weight of every pixel of l.
+func qualityMap(pixIter core.PixelIterator, l *Level, weights FusionWeights) *Level {
+	const sigma = 0.2
+	const epsilon = 1e-12
+
+	gray := make([]float32, l.W*l.H)
+	for i := range gray {
+		p := l.Pix[i*4:]
+		gray[i] = (p[0] + p[1] + p[2]) / 3
+	}
+	grayAt := func(x, y int) float32 {
+		return gray[core.Clamp(y, 0, l.H-1)*l.W+core.Clamp(x, 0, l.W-1)]
+	}
+
+	m := NewLevel(l.W, l.H)
+	core.IterateRows(pixIter, l.H, func(y int) {
+		in, out := l.Row(y), m.Row(y)
+		for x := range l.W {
+			r, g, b := float64(in[x*4]), float64(in[x*4+1]), float64(in[x*4+2])
+			w := 1.0
+			if weights.Contrast != 0 {
+				lap := 4*grayAt(x, y) - grayAt(x-1, y) - grayAt(x+1, y) - grayAt(x, y-1) - grayAt(x, y+1)
+				w *= math.Pow(math.Abs(float64(lap)), weights.Contrast)
+			}
+			if weights.Saturation != 0 {
+				mu := (r + g + b) / 3
+				sd := math.Sqrt(((r-mu)*(r-mu) + (g-mu)*(g-mu) + (b-mu)*(b-mu)) / 3)
+				w *= math.Pow(sd, weights.Saturation)
+			}
+			if weights.Exposedness != 0 {
+				e := math.Exp(-((r-0.5)*(r-0.5) + (g-0.5)*(g-0.5) + (b-0.5)*(b-0.5)) / (2 * sigma * sigma))
+				w *= math.Pow(e, weights.Exposedness)
+			}
+			v := float32(w + epsilon)
+			out[x*4], out[x*4+1], out[x*4+2], out[x*4+3] = v, v, v, v
+		}
+	})
+	return m
+}
+
+// normalizeWeights scales the weight maps so they sum to one at every pixel.
+func normalizeWeights(pixIter core.PixelIterator, maps []*Level) {
+	core.IterateRows(pixIter, maps[0].H, func(y int) {
+		for x := 0; x < maps[0].W*4; x++ {
+			var sum float32
+			for _, m := range maps {
+				sum += m.Row(y)[x]
+			}
+			for _, m := range maps {
+				m.Row(y)[x] /= sum
+			}
+		}
+	})
+}
+
+// outputModel chooses the color model for new output images, preferring dst then src.
+func outputModel(cfg core.Config, dst, src image.Image) color.Model {
+	switch {
+	case core.IsColorModelSupported(dst.ColorModel()):
+		return dst.ColorModel()
+	case core.IsColorModelSupported(src.ColorModel()):
+		return src.ColorModel()
+	default:
+		return cfg.DefaultColorModel()
+	}
+}
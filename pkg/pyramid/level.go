@@ -0,0 +1,139 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package pyramid builds Gaussian and Laplacian image pyramids and uses them for
+// multi-band (Burt–Adelson) blending and Mertens exposure fusion.
+package pyramid
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// Level is a single pyramid level of premultiplied RGBA samples stored as float32.
+// Gaussian levels hold values in [0, 1]; Laplacian levels hold signed band-pass detail.
+type Level struct {
+	W, H int
+	// Pix holds the samples in R, G, B, A order; the pixel at (x, y) starts at Pix[(y*W+x)*4].
+	Pix []float32
+}
+
+// NewLevel creates a zeroed level with the given dimensions.
+func NewLevel(w, h int) *Level {
+	return &Level{W: w, H: h, Pix: make([]float32, w*h*4)}
+}
+
+// Row returns the samples for row y.
+func (l *Level) Row(y int) []float32 {
+	return l.Pix[y*l.W*4 : (y+1)*l.W*4]
+}
+
+// FromImage converts the region r of img to a level of premultiplied samples.
+func FromImage(pixIter core.PixelIterator, img image.Image, r image.Rectangle) *Level {
+	l := NewLevel(r.Dx(), r.Dy())
+	core.IterateRows(pixIter, r.Dy(), func(row int) {
+		out := l.Row(row)
+		y := r.Min.Y + row
+		switch src := img.(type) {
+		case *image.RGBA:
+			pix := src.Pix[src.PixOffset(r.Min.X, y):]
+			for i := range out {
+				out[i] = float32(pix[i]) / 255
+			}
+		case *image.NRGBA:
+			pix := src.Pix[src.PixOffset(r.Min.X, y):]
+			for i := 0; i < len(out); i += 4 {
+				a := float32(pix[i+3]) / 255
+				out[i] = float32(pix[i]) / 255 * a
+				out[i+1] = float32(pix[i+1]) / 255 * a
+				out[i+2] = float32(pix[i+2]) / 255 * a
+				out[i+3] = a
+			}
+		default:
+			for x := range l.W {
+				cr, cg, cb, ca := img.At(r.Min.X+x, y).RGBA()
+				out[x*4] = float32(cr) / 0xffff
+				out[x*4+1] = float32(cg) / 0xffff
+				out[x*4+2] = float32(cb) / 0xffff
+				out[x*4+3] = float32(ca) / 0xffff
+			}
+		}
+	})
+	return l
+}
+
+// maskLevel converts the alpha of the region of mask starting at mp to a level where every
+// channel holds the mask coverage. A nil mask is fully opaque.
+func maskLevel(pixIter core.PixelIterator, mask image.Image, mp image.Point, w, h int) *Level {
+	l := NewLevel(w, h)
+	core.IterateRows(pixIter, h, func(row int) {
+		out := l.Row(row)
+		for x := range w {
+			var a float32 = 1
+			if mask != nil {
+				_, _, _, ma := mask.At(mp.X+x, mp.Y+row).RGBA()
+				a = float32(ma) / 0xffff
+			}
+			out[x*4], out[x*4+1], out[x*4+2], out[x*4+3] = a, a, a, a
+		}
+	})
+	return l
+}
+
+// WriteTo writes the level to img with its top-left corner at pt, clamping samples to the
+// displayable range. The image must be an *image.NRGBA or *image.RGBA.
+func (l *Level) WriteTo(pixIter core.PixelIterator, img image.Image, pt image.Point) error {
+	r := image.Rect(pt.X, pt.Y, pt.X+l.W, pt.Y+l.H).Intersect(img.Bounds())
+	if r.Empty() {
+		return nil
+	}
+	var pix []uint8
+	var stride int
+	var premultiplied bool
+	switch out := img.(type) {
+	case *image.NRGBA:
+		pix, stride = out.Pix[out.PixOffset(r.Min.X, r.Min.Y):], out.Stride
+	case *image.RGBA:
+		pix, stride = out.Pix[out.PixOffset(r.Min.X, r.Min.Y):], out.Stride
+		premultiplied = true
+	default:
+		return fmt.Errorf("unsupported output color model %v", img.ColorModel())
+	}
+
+	off := r.Min.Sub(pt)
+	core.IterateRows(pixIter, r.Dy(), func(row int) {
+		in := l.Row(off.Y + row)[off.X*4:]
+		out := pix[row*stride : row*stride+r.Dx()*4]
+		for i := 0; i < len(out); i += 4 {
+			c := toNRGBA(in[i], in[i+1], in[i+2], in[i+3])
+			if premultiplied {
+				p := color.RGBAModel.Convert(c).(color.RGBA) //nolint:errcheck
+				out[i], out[i+1], out[i+2], out[i+3] = p.R, p.G, p.B, p.A
+			} else {
+				out[i], out[i+1], out[i+2], out[i+3] = c.R, c.G, c.B, c.A
+			}
+		}
+	})
+	return nil
+}
+
+// toNRGBA converts premultiplied float samples to a straight alpha color.
+func toNRGBA(r, g, b, a float32) color.NRGBA {
+	a = core.Clamp(a, 0, 1)
+	if a == 0 {
+		return color.NRGBA{}
+	}
+	return color.NRGBA{
+		R: quantize(r / a),
+		G: quantize(g / a),
+		B: quantize(b / a),
+		A: quantize(a),
+	}
+}
+
+func quantize(v float32) uint8 {
+	return uint8(core.Clamp(v, 0, 1)*255 + 0.5)
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package pyramid
+
+import (
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// minLevelSize is the smallest dimension a level may have when the number of levels is automatic.
+const minLevelSize = 8
+
+// binomial is the 5-tap generating kernel from Burt and Adelson (a = 0.375).
+var binomial = [5]float32{1.0 / 16, 4.0 / 16, 6.0 / 16, 4.0 / 16, 1.0 / 16}
+
+// Pyramid is a sequence of levels from full resolution (index 0) to the coarsest level.
+type Pyramid []*Level
+
+// Levels returns the number of levels to use for a w x h image. A requested count of zero
+// or less selects as many levels as possible while keeping every level at least 8 pixels
+// in each dimension; positive counts are capped so no level collapses below 1 pixel.
+func Levels(w, h, requested int) int {
+	limit := 1
+	for s := min(w, h); s > 1; s = (s + 1) / 2 {
+		limit++
+	}
+	if requested > 0 {
+		return min(requested, limit)
+	}
+	n := 1
+	for s := min(w, h); s >= 2*minLevelSize; s = (s + 1) / 2 {
+		n++
+	}
+	return n
+}
+
+// Reduce blurs l with the binomial kernel and subsamples it by a factor of two.
+func Reduce(pixIter core.PixelIterator, l *Level) *Level {
+	w2, h2 := (l.W+1)/2, (l.H+1)/2
+
+	// Horizontal pass: full height, half width.
+	tmp := NewLevel(w2, l.H)
+	core.IterateRows(pixIter, l.H, func(y int) {
+		in, out := l.Row(y), tmp.Row(y)
+		for x := range w2 {
+			for k, wt := range binomial {
+				sx := core.Clamp(2*x+k-2, 0, l.W-1) * 4
+				out[x*4] += in[sx] * wt
+				out[x*4+1] += in[sx+1] * wt
+				out[x*4+2] += in[sx+2] * wt
+				out[x*4+3] += in[sx+3] * wt
+			}
+		}
+	})
+
+	// Vertical pass: half height.
+	dst := NewLevel(w2, h2)
+	core.IterateRows(pixIter, h2, func(y int) {
+		out := dst.Row(y)
+		for k, wt := range binomial {
+			in := tmp.Row(core.Clamp(2*y+k-2, 0, l.H-1))
+			for i := range out {
+				out[i] += in[i] * wt
+			}
+		}
+	})
+	return dst
+}
+
+// Expand upsamples l to w x h by interpolating with the binomial kernel.
+// It is the inverse of Reduce for computing Laplacian levels.
+func Expand(pixIter core.PixelIterator, l *Level, w, h int) *Level {
+	// Horizontal pass: coarse height, full width.
+	tmp := NewLevel(w, l.H)
+	core.IterateRows(pixIter, l.H, func(y int) {
+		in, out := l.Row(y), tmp.Row(y)
+		for x := range w {
+			for k, wt := range binomial {
+				// Only taps landing on even (coarse) positions contribute, hence the factor of 2.
+				p := x + k - 2
+				if p&1 != 0 {
+					continue
+				}
+				sx := core.Clamp(p/2, 0, l.W-1) * 4
+				wt *= 2
+				out[x*4] += in[sx] * wt
+				out[x*4+1] += in[sx+1] * wt
+				out[x*4+2] += in[sx+2] * wt
+				out[x*4+3] += in[sx+3] * wt
+			}
+		}
+	})
+
+	dst := NewLevel(w, h)
+	core.IterateRows(pixIter, h, func(y int) {
+		out := dst.Row(y)
+		for k, wt := range binomial {
+			p := y + k - 2
+			if p&1 != 0 {
+				continue
+			}
+			in := tmp.Row(core.Clamp(p/2, 0, l.H-1))
+			wt *= 2
+			for i := range out {
+				out[i] += in[i] * wt
+			}
+		}
+	})
+	return dst
+}
+
+// Gaussian builds a Gaussian pyramid with the given number of levels starting from base.
+// See Levels for how the level count is interpreted.
+func Gaussian(pixIter core.PixelIterator, base *Level, levels int) Pyramid {
+	levels = Levels(base.W, base.H, levels)
+	p := make(Pyramid, levels)
+	p[0] = base
+	for i := 1; i < levels; i++ {
+		p[i] = Reduce(pixIter, p[i-1])
+	}
+	return p
+}
+
+// Laplacian builds a Laplacian pyramid with the given number of levels starting from base.
+// Every level except the last holds the band-pass detail lost by Reduce; the last level is
+// the coarsest Gaussian level. Collapse reverses the construction.
+func Laplacian(pixIter core.PixelIterator, base *Level, levels int) Pyramid {
+	g := Gaussian(pixIter, base, levels)
+	p := make(Pyramid, len(g))
+	for i := range len(g) - 1 {
+		up := Expand(pixIter, g[i+1], g[i].W, g[i].H)
+		core.IterateRows(pixIter, up.H, func(y int) {
+			fine, out := g[i].Row(y), up.Row(y)
+			for x := range out {
+				out[x] = fine[x] - out[x]
+			}
+		})
+		p[i] = up
+	}
+	p[len(g)-1] = g[len(g)-1]
+	return p
+}
+
+// Collapse reconstructs the full resolution level from a Laplacian pyramid.
+func (p Pyramid) Collapse(pixIter core.PixelIterator) *Level {
+	cur := p[len(p)-1]
+	for i := len(p) - 2; i >= 0; i-- {
+		up := Expand(pixIter, cur, p[i].W, p[i].H)
+		core.IterateRows(pixIter, up.H, func(y int) {
+			band, out := p[i].Row(y), up.Row(y)
+			for x := range out {
+				out[x] += band[x]
+			}
+		})
+		cur = up
+	}
+	return cur
+}
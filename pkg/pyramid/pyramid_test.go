@@ -0,0 +1,171 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package pyramid
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+func checker(w, h int, a, b color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			if (x/4+y/4)%2 == 0 {
+				img.SetNRGBA(x, y, a)
+			} else {
+				img.SetNRGBA(x, y, b)
+			}
+		}
+	}
+	return img
+}
+
+func solid(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestLevels(t *testing.T) {
+	tests := []struct {
+		w, h, requested, want int
+	}{
+		{64, 64, 0, 4},
+		{64, 16, 0, 2},
+		{7, 7, 0, 1},
+		{64, 64, 3, 3},
+		{4, 4, 10, 3},
+		{0, 0, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := Levels(tt.w, tt.h, tt.requested); got != tt.want {
+			t.Errorf("Levels(%d, %d, %d) = %d, want %d", tt.w, tt.h, tt.requested, got, tt.want)
+		}
+	}
+}
+
+func TestLaplacianCollapseRoundTrip(t *testing.T) {
+	pixIter := core.NewSerialPixelIterator()
+	img := checker(37, 23, color.NRGBA{R: 255, G: 10, B: 90, A: 255}, color.NRGBA{G: 200, B: 40, A: 128})
+	base := FromImage(pixIter, img, img.Bounds())
+
+	lp := Laplacian(pixIter, base, 0)
+	if len(lp) < 2 {
+		t.Fatalf("expected multiple levels, got %d", len(lp))
+	}
+	got := lp.Collapse(pixIter)
+	for i, v := range base.Pix {
+		if math.Abs(float64(v-got.Pix[i])) > 1e-5 {
+			t.Fatalf("collapse differs at %d: %v vs %v", i, got.Pix[i], v)
+		}
+	}
+}
+
+func TestReduceExpandConstant(t *testing.T) {
+	pixIter := core.NewParallelPixelIterator(3)
+	base := FromImage(pixIter, solid(9, 5, color.NRGBA{R: 100, G: 150, B: 200, A: 255}), image.Rect(0, 0, 9, 5))
+	small := Reduce(pixIter, base)
+	if small.W != 5 || small.H != 3 {
+		t.Fatalf("Reduce size = %dx%d, want 5x3", small.W, small.H)
+	}
+	big := Expand(pixIter, small, 9, 5)
+	for i, v := range big.Pix {
+		if math.Abs(float64(v-base.Pix[i])) > 1e-6 {
+			t.Fatalf("constant image not preserved at %d: %v vs %v", i, v, base.Pix[i])
+		}
+	}
+}
+
+func TestMultiBand(t *testing.T) {
+	cfg := internal.DefaultConfig
+	red := solid(64, 32, color.NRGBA{R: 255, A: 255})
+	blue := solid(64, 32, color.NRGBA{B: 255, A: 255})
+
+	// Left half selects src (blue), right half keeps dst (red).
+	mask := image.NewAlpha(image.Rect(0, 0, 64, 32))
+	for y := range 32 {
+		for x := range 32 {
+			mask.SetAlpha(x, y, color.Alpha{A: 255})
+		}
+	}
+
+	out, err := MultiBand(cfg, red, red.Bounds(), blue, image.Point{}, mask, image.Point{}, 0, core.ToNewImage())
+	if err != nil {
+		t.Fatalf("MultiBand failed: %v", err)
+	}
+	img := out.(*image.NRGBA)
+	if c := img.NRGBAAt(0, 16); c.B < 250 || c.R > 5 {
+		t.Errorf("far left should be src, got %v", c)
+	}
+	if c := img.NRGBAAt(63, 16); c.R < 250 || c.B > 5 {
+		t.Errorf("far right should be dst, got %v", c)
+	}
+	// The seam transitions smoothly instead of switching in a single pixel.
+	prev := 256
+	for x := 24; x < 40; x++ {
+		c := img.NRGBAAt(x, 16)
+		if int(c.B) > prev {
+			t.Fatalf("blend is not monotonic across the seam at x=%d", x)
+		}
+		prev = int(c.B)
+	}
+	if c := img.NRGBAAt(31, 16); c.B == 255 || c.R == 0 {
+		t.Errorf("seam should be blended, got %v", c)
+	}
+	if red.NRGBAAt(0, 0).R != 255 {
+		t.Error("dst should not be modified with ToNewImage")
+	}
+}
+
+func TestMultiBandToDstRGBA(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	src := solid(16, 16, color.NRGBA{G: 255, A: 255})
+	out, err := MultiBand(internal.DefaultConfig, dst, image.Rect(4, 4, 12, 12), src, image.Point{}, nil, image.Point{}, 2, core.ToDst())
+	if err != nil {
+		t.Fatalf("MultiBand failed: %v", err)
+	}
+	if out != dst {
+		t.Fatal("MultiBand should write to dst")
+	}
+	if c := dst.RGBAAt(8, 8); c.G != 255 || c.A != 255 {
+		t.Errorf("nil mask should select src, got %v", c)
+	}
+	if c := dst.RGBAAt(0, 0); c.A != 0 {
+		t.Errorf("pixels outside r should be untouched, got %v", c)
+	}
+}
+
+func TestFuse(t *testing.T) {
+	cfg := internal.DefaultConfig
+	under := checker(32, 32, color.NRGBA{R: 10, G: 10, B: 12, A: 255}, color.NRGBA{R: 20, G: 30, B: 25, A: 255})
+	over := checker(32, 32, color.NRGBA{R: 250, G: 250, B: 252, A: 255}, color.NRGBA{R: 245, G: 252, B: 250, A: 255})
+	mid := checker(32, 32, color.NRGBA{R: 90, G: 120, B: 60, A: 255}, color.NRGBA{R: 160, G: 200, B: 110, A: 255})
+
+	out, err := Fuse(cfg, []image.Image{under, over, mid}, under.Bounds(), DefaultFusionWeights, 0, core.ToNewNRGBAImage())
+	if err != nil {
+		t.Fatalf("Fuse failed: %v", err)
+	}
+	img := out.(*image.NRGBA)
+	// The well exposed frame dominates the result.
+	for _, pt := range []image.Point{{1, 1}, {5, 1}, {17, 22}} {
+		got, want := img.NRGBAAt(pt.X, pt.Y), mid.NRGBAAt(pt.X, pt.Y)
+		if d := int(got.G) - int(want.G); d < -20 || d > 20 {
+			t.Errorf("fused %v = %v, want close to %v", pt, got, want)
+		}
+	}
+
+	if _, err = Fuse(cfg, nil, under.Bounds(), DefaultFusionWeights, 0, nil); err == nil {
+		t.Error("Fuse with no images should fail")
+	}
+}
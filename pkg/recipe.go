@@ -0,0 +1,272 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package magpie
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+// RecipeVersion is the version of the recipe format written by this package.
+const RecipeVersion = 1
+
+// Recipe is a serializable sequence of draws. Recipes refer to images by name: the inputs are
+// provided when the recipe is executed, and the output of every step is stored under a name so
+// that later steps can use it. A recipe in JSON looks like this:
+//
+//	{
+//	  "version": 1,
+//	  "steps": [
+//	    {
+//	      "dst": "background",
+//	      "src": "photo",
+//	      "rect": {"Min": {"X": 0, "Y": 0}, "Max": {"X": 640, "Y": 480}},
+//	      "sp": {"X": 10, "Y": 20},
+//	      "op": {"blend": "multiply", "compositing": "all"},
+//	      "output": {"mode": "new", "name": "tinted"}
+//	    },
+//	    {
+//	      "dst": "tinted",
+//	      "src": "logo",
+//	      "op": {"composite": "source-over"},
+//	      "output": {"mode": "dst"}
+//	    }
+//	  ]
+//	}
+//
+// Modes and compositing use the names of op.BlendMode, op.CompositeMode and op.BlendCompositing.
+type Recipe struct {
+	Version int          `json:"version"`
+	Steps   []RecipeStep `json:"steps"`
+}
+
+// RecipeStep is a single draw of a Recipe. Rect defaults to the bounds of the dst image and
+// SrcPoint to the top-left corner of the src image.
+type RecipeStep struct {
+	Dst      string           `json:"dst"`
+	Src      string           `json:"src"`
+	Rect     *image.Rectangle `json:"rect,omitempty"`
+	SrcPoint *image.Point     `json:"sp,omitempty"`
+	Op       RecipeOp         `json:"op"`
+	Output   RecipeOutput     `json:"output"`
+}
+
+// RecipeOp describes the operation of a step. Exactly one of Blend, Composite and Expression
+// must be set. Compositing applies to blends and expressions, and Seed to the Dissolve blend.
+type RecipeOp struct {
+	Blend       *op.BlendMode        `json:"blend,omitempty"`
+	Composite   *op.CompositeMode    `json:"composite,omitempty"`
+	Expression  string               `json:"expression,omitempty"`
+	Compositing *op.BlendCompositing `json:"compositing,omitempty"`
+	Seed        uint64               `json:"seed,omitempty"`
+}
+
+// RecipeOutput describes where a step writes its result:
+//
+//   - "dst" writes to the dst image.
+//   - "new" writes to a new image, in the color model "nrgba" or "rgba" if Model is set.
+//   - "image" writes to the image named Image, at Point.
+//   - An empty mode uses the default output mode of the context.
+//
+// The result is stored under Name, which defaults to Image for "image" outputs and to the name
+// of the dst image otherwise. Steps that write to a new image must have a name.
+type RecipeOutput struct {
+	Mode  string       `json:"mode,omitempty"`
+	Name  string       `json:"name,omitempty"`
+	Model string       `json:"model,omitempty"`
+	Image string       `json:"image,omitempty"`
+	Point *image.Point `json:"point,omitempty"`
+}
+
+// LoadRecipe reads a JSON recipe and validates it.
+func LoadRecipe(r io.Reader) (*Recipe, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var recipe Recipe
+	if err := dec.Decode(&recipe); err != nil {
+		return nil, fmt.Errorf("decoding recipe: %w", err)
+	}
+	if err := recipe.Validate(); err != nil {
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+// Validate checks the recipe without executing it. The names of the input images are only
+// checked by Execute.
+func (rc *Recipe) Validate() error {
+	_, err := rc.ops()
+	return err
+}
+
+// ops validates the steps and returns their operations.
+func (rc *Recipe) ops() ([]core.Op, error) {
+	if rc.Version != RecipeVersion {
+		return nil, fmt.Errorf("unsupported recipe version %d", rc.Version)
+	}
+	ops := make([]core.Op, len(rc.Steps))
+	for i, step := range rc.Steps {
+		o, err := step.validate()
+		if err != nil {
+			return nil, fmt.Errorf("recipe step %d: %w", i, err)
+		}
+		ops[i] = o
+	}
+	return ops, nil
+}
+
+// Execute runs the steps of the recipe in order with ctx. images holds the inputs by name and
+// is not modified, although steps writing to "dst" or "image" outputs draw on the images
+// themselves. It returns the inputs together with the outputs of all steps.
+func (rc *Recipe) Execute(ctx Context, images map[string]image.Image) (map[string]image.Image, error) {
+	// All steps are validated first so that an invalid step doesn't leave the images half drawn.
+	ops, err := rc.ops()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]image.Image, len(images)+len(rc.Steps))
+	for name, img := range images {
+		result[name] = img
+	}
+	for i, step := range rc.Steps {
+		if err := step.execute(ctx, ops[i], result); err != nil {
+			return nil, fmt.Errorf("recipe step %d: %w", i, err)
+		}
+	}
+	return result, nil
+}
+
+// validate checks the step and returns its operation.
+func (step RecipeStep) validate() (core.Op, error) {
+	if step.Dst == "" || step.Src == "" {
+		return nil, errors.New("dst and src are required")
+	}
+	o, err := step.Op.Build()
+	if err != nil {
+		return nil, err
+	}
+	if !o.IsValid() {
+		return nil, errors.New("invalid operation")
+	}
+	out := step.Output
+	switch out.Mode {
+	case "", "dst":
+	case "new":
+		if out.Name == "" {
+			return nil, errors.New("outputs to a new image require a name")
+		}
+		if _, err := recipeColorModel(out.Model); err != nil {
+			return nil, err
+		}
+	case "image":
+		if out.Image == "" {
+			return nil, errors.New("image outputs require an image")
+		}
+	default:
+		return nil, fmt.Errorf("unknown output mode %q", out.Mode)
+	}
+	return o, nil
+}
+
+// execute runs the step with its operation o and stores the result in images.
+func (step RecipeStep) execute(ctx Context, o core.Op, images map[string]image.Image) error {
+	dst, ok := images[step.Dst]
+	if !ok {
+		return fmt.Errorf("unknown image %q", step.Dst)
+	}
+	src, ok := images[step.Src]
+	if !ok {
+		return fmt.Errorf("unknown image %q", step.Src)
+	}
+	r := dst.Bounds()
+	if step.Rect != nil {
+		r = *step.Rect
+	}
+	sp := src.Bounds().Min
+	if step.SrcPoint != nil {
+		sp = *step.SrcPoint
+	}
+
+	// Outputs that draw on an existing image are stored under its name by default.
+	var output core.Output
+	name := step.Dst
+	switch step.Output.Mode {
+	case "dst":
+		output = ToDst()
+	case "new":
+		model, _ := recipeColorModel(step.Output.Model)
+		switch model {
+		case color.RGBAModel:
+			output = core.ToNewRGBAImage()
+		case color.NRGBAModel:
+			output = core.ToNewNRGBAImage()
+		default:
+			output = ToNewImage()
+		}
+	case "image":
+		img, ok := images[step.Output.Image]
+		if !ok {
+			return fmt.Errorf("unknown image %q", step.Output.Image)
+		}
+		var pt image.Point
+		if step.Output.Point != nil {
+			pt = *step.Output.Point
+		}
+		output, name = ToImage(img, pt), step.Output.Image
+	}
+	if step.Output.Name != "" {
+		name = step.Output.Name
+	}
+
+	img, err := ctx.Draw(dst, r, src, sp, o, output)
+	if err != nil {
+		return err
+	}
+	images[name] = img
+	return nil
+}
+
+// Build returns the operation described by o. It fails if o doesn't set exactly one of Blend,
+// Composite and Expression, or if the expression doesn't compile.
+func (o RecipeOp) Build() (core.Op, error) {
+	compositing := op.CompositeAll
+	if o.Compositing != nil {
+		compositing = *o.Compositing
+	}
+	switch {
+	case o.Blend != nil && o.Composite == nil && o.Expression == "":
+		return op.BlendOp{Mode: *o.Blend, Compositing: compositing, Seed: o.Seed}, nil
+	case o.Composite != nil && o.Blend == nil && o.Expression == "":
+		return op.CompositeOp{Mode: *o.Composite}, nil
+	case o.Expression != "" && o.Blend == nil && o.Composite == nil:
+		b, err := op.CompileCustomBlend(o.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return op.CustomBlendOp{Blend: b, Compositing: compositing}, nil
+	default:
+		return nil, errors.New("exactly one of blend, composite and expression is required")
+	}
+}
+
+// recipeColorModel returns the color model with the given name, or nil for an empty name.
+func recipeColorModel(name string) (color.Model, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "nrgba":
+		return color.NRGBAModel, nil
+	case "rgba":
+		return color.RGBAModel, nil
+	default:
+		return nil, fmt.Errorf("unknown color model %q", name)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package magpie
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/op"
+)
+
+const testRecipe = `{
+  "version": 1,
+  "steps": [
+    {
+      "dst": "background",
+      "src": "photo",
+      "rect": {"Min": {"X": 0, "Y": 0}, "Max": {"X": 8, "Y": 8}},
+      "sp": {"X": 2, "Y": 2},
+      "op": {"blend": "multiply", "compositing": "all"},
+      "output": {"mode": "new", "name": "tinted", "model": "rgba"}
+    },
+    {
+      "dst": "tinted",
+      "src": "logo",
+      "op": {"composite": "source-over"},
+      "output": {"mode": "dst"}
+    },
+    {
+      "dst": "tinted",
+      "src": "photo",
+      "op": {"expression": "255 - $D", "compositing": "blend-and-dst"},
+      "output": {"mode": "new", "name": "inverted"}
+    }
+  ]
+}`
+
+func recipeImages() map[string]image.Image {
+	r := image.Rect(0, 0, 16, 16)
+	return map[string]image.Image{
+		"background": noiseImage(color.NRGBAModel, r, 1),
+		"photo":      noiseImage(color.NRGBAModel, r, 2),
+		"logo":       noiseImage(color.RGBAModel, image.Rect(0, 0, 8, 8), 3),
+	}
+}
+
+func TestRecipe(t *testing.T) {
+	recipe, err := LoadRecipe(strings.NewReader(testRecipe))
+	if err != nil {
+		t.Fatalf("LoadRecipe failed: %v", err)
+	}
+	ctx := NewContext()
+	got, err := recipe.Execute(ctx, recipeImages())
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// The same draws made directly.
+	images := recipeImages()
+	r := image.Rect(0, 0, 8, 8)
+	tinted, err := ctx.Draw(images["background"], r, images["photo"], image.Pt(2, 2),
+		op.BlendOp{Mode: op.Multiply, Compositing: op.CompositeAll}, core.ToNewRGBAImage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ctx.Draw(tinted, r, images["logo"], image.Point{}, op.CompositeOp{Mode: op.SourceOver}, ToDst()); err != nil {
+		t.Fatal(err)
+	}
+	invert, err := op.CompileCustomBlend("255 - $D")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inverted, err := ctx.Draw(tinted, r, images["photo"], image.Point{},
+		op.CustomBlendOp{Blend: invert, Compositing: op.CompositeBlendAndDst}, ToNewImage())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := got["tinted"].(*image.RGBA); !ok {
+		t.Fatalf("tinted is %T, want *image.RGBA", got["tinted"])
+	}
+	assertSameImage(t, got["inverted"], inverted)
+	for _, name := range []string{"background", "photo", "logo"} {
+		if got[name] == nil {
+			t.Errorf("inputs should be returned, %q is missing", name)
+		}
+	}
+}
+
+func TestRecipeRoundTrip(t *testing.T) {
+	recipe, err := LoadRecipe(strings.NewReader(testRecipe))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(recipe)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"blend":"multiply"`) || !strings.Contains(string(data), `"composite":"source-over"`) {
+		t.Errorf("modes should be marshaled by name: %s", data)
+	}
+	again, err := LoadRecipe(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("LoadRecipe of marshaled recipe failed: %v", err)
+	}
+	if len(again.Steps) != len(recipe.Steps) || *again.Steps[0].Op.Blend != op.Multiply || *again.Steps[1].Op.Composite != op.SourceOver {
+		t.Errorf("round trip = %+v, want %+v", again, recipe)
+	}
+}
+
+func TestRecipeInvalid(t *testing.T) {
+	step := func(s string) string {
+		return `{"version": 1, "steps": [` + s + `]}`
+	}
+	tests := []struct {
+		name, recipe string
+	}{
+		{"Version", `{"version": 2, "steps": []}`},
+		{"Unknown field", `{"version": 1, "steps": [], "extra": true}`},
+		{"Unknown blend", step(`{"dst": "a", "src": "b", "op": {"blend": "bogus"}}`)},
+		{"Unknown compositing", step(`{"dst": "a", "src": "b", "op": {"blend": "normal", "compositing": "some"}}`)},
+		{"No op", step(`{"dst": "a", "src": "b", "op": {}}`)},
+		{"Two ops", step(`{"dst": "a", "src": "b", "op": {"blend": "normal", "composite": "xor"}}`)},
+		{"Bad expression", step(`{"dst": "a", "src": "b", "op": {"expression": "$S +"}}`)},
+		{"Missing dst", step(`{"src": "b", "op": {"composite": "xor"}}`)},
+		{"Unnamed new image", step(`{"dst": "a", "src": "b", "op": {"composite": "xor"}, "output": {"mode": "new"}}`)},
+		{"Unknown model", step(`{"dst": "a", "src": "b", "op": {"composite": "xor"}, "output": {"mode": "new", "name": "c", "model": "gray"}}`)},
+		{"Unknown output", step(`{"dst": "a", "src": "b", "op": {"composite": "xor"}, "output": {"mode": "file"}}`)},
+		{"Image output without image", step(`{"dst": "a", "src": "b", "op": {"composite": "xor"}, "output": {"mode": "image"}}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadRecipe(strings.NewReader(tt.recipe)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	t.Run("Unknown image", func(t *testing.T) {
+		recipe, err := LoadRecipe(strings.NewReader(step(`{"dst": "background", "src": "missing", "op": {"composite": "xor"}}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := recipe.Execute(NewContext(), recipeImages()); err == nil {
+			t.Error("expected an error for an unknown image")
+		}
+	})
+
+	t.Run("Invalid step before drawing", func(t *testing.T) {
+		images := recipeImages()
+		bg := images["background"].(*image.NRGBA)
+		before := string(bg.Pix)
+		mode := op.BlendMode(-1)
+		recipe := &Recipe{Version: RecipeVersion, Steps: []RecipeStep{
+			{Dst: "background", Src: "photo", Op: RecipeOp{Composite: new(op.CompositeMode)}, Output: RecipeOutput{Mode: "dst"}},
+			{Dst: "background", Src: "photo", Op: RecipeOp{Blend: &mode}},
+		}}
+		if _, err := recipe.Execute(NewContext(), images); err == nil {
+			t.Fatal("expected an error for an invalid mode")
+		}
+		if string(bg.Pix) != before {
+			t.Error("no step should run when a later step is invalid")
+		}
+	})
+}
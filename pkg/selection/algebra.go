@@ -0,0 +1,64 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package selection
+
+import "image"
+
+// Union returns the pixels selected in either mask (the maximum of both).
+// The result covers the union of both bounds.
+func Union(a, b *image.Alpha) *image.Alpha {
+	return combine(a, b, func(x, y uint8) uint8 { return max(x, y) })
+}
+
+// Intersect returns the pixels selected in both masks (the minimum of both).
+// The result covers the union of both bounds.
+func Intersect(a, b *image.Alpha) *image.Alpha {
+	return combine(a, b, func(x, y uint8) uint8 { return min(x, y) })
+}
+
+// Subtract returns the pixels selected in a but not in b.
+// The result covers the union of both bounds.
+func Subtract(a, b *image.Alpha) *image.Alpha {
+	return combine(a, b, func(x, y uint8) uint8 { return min(x, 255-y) })
+}
+
+// Xor returns the pixels selected in exactly one of the masks.
+// The result covers the union of both bounds.
+func Xor(a, b *image.Alpha) *image.Alpha {
+	return combine(a, b, func(x, y uint8) uint8 { return max(min(x, 255-y), min(y, 255-x)) })
+}
+
+// Invert returns the pixels of r not selected in a. Pixels outside of a's bounds are
+// considered unselected, so they are selected in the result.
+func Invert(a *image.Alpha, r image.Rectangle) *image.Alpha {
+	out := image.NewAlpha(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		row := out.Pix[out.PixOffset(r.Min.X, y):]
+		for x := range r.Dx() {
+			row[x] = 255 - at(a, r.Min.X+x, y)
+		}
+	}
+	return out
+}
+
+// combine applies fn to each pixel of the union of the bounds of a and b.
+// Pixels outside of a mask's bounds are unselected.
+func combine(a, b *image.Alpha, fn func(x, y uint8) uint8) *image.Alpha {
+	r := a.Rect.Union(b.Rect)
+	out := image.NewAlpha(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		row := out.Pix[out.PixOffset(r.Min.X, y):]
+		for x := range r.Dx() {
+			row[x] = fn(at(a, r.Min.X+x, y), at(b, r.Min.X+x, y))
+		}
+	}
+	return out
+}
+
+func at(a *image.Alpha, x, y int) uint8 {
+	if !image.Pt(x, y).In(a.Rect) {
+		return 0
+	}
+	return a.Pix[a.PixOffset(x, y)]
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package selection
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+// regions returns a 12x8 image split into a red left half and a blue right half, with a red
+// island at (10, 1) inside the blue half and a red pixel diagonal to the left half at (6, 7).
+func regions() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 12, 8))
+	red := color.NRGBA{R: 250, A: 255}
+	for y := range 8 {
+		for x := range 12 {
+			c := color.NRGBA{B: 255, A: 255}
+			if x < 6 && y < 7 {
+				c = color.NRGBA{R: 240 + uint8(x), A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	img.SetNRGBA(10, 1, red)
+	img.SetNRGBA(6, 7, red)
+	return img
+}
+
+func count(m *image.Alpha) int {
+	n := 0
+	for _, v := range m.Pix {
+		if v == 255 {
+			n++
+		}
+	}
+	return n
+}
+
+func TestMagicWand(t *testing.T) {
+	cfg := internal.DefaultConfig
+	img := regions()
+	tests := []struct {
+		name string
+		wand Wand
+		want int
+		in   []image.Point
+		out  []image.Point
+	}{
+		{"contiguous", Wand{Tolerance: 10, Contiguous: true}, 42, []image.Point{{0, 0}, {5, 6}}, []image.Point{{10, 1}, {6, 7}}},
+		{"diagonal", Wand{Tolerance: 10, Contiguous: true, Diagonal: true}, 43, []image.Point{{6, 7}}, []image.Point{{10, 1}}},
+		{"global", Wand{Tolerance: 10}, 44, []image.Point{{10, 1}, {6, 7}}, []image.Point{{8, 4}}},
+		{"tight", Wand{Tolerance: 0, Contiguous: true}, 7, []image.Point{{0, 3}}, []image.Point{{1, 3}}},
+	}
+	for _, tt := range tests {
+		mask, err := MagicWand(cfg, img, img.Rect, image.Pt(0, 0), tt.wand)
+		if err != nil {
+			t.Fatalf("%s: MagicWand failed: %v", tt.name, err)
+		}
+		if n := count(mask); n != tt.want {
+			t.Errorf("%s: selected %d pixels, want %d", tt.name, n, tt.want)
+		}
+		for _, p := range tt.in {
+			if mask.AlphaAt(p.X, p.Y).A != 255 {
+				t.Errorf("%s: %v not selected", tt.name, p)
+			}
+		}
+		for _, p := range tt.out {
+			if mask.AlphaAt(p.X, p.Y).A != 0 {
+				t.Errorf("%s: %v selected", tt.name, p)
+			}
+		}
+	}
+
+	// RGBA sources are compared as straight alpha colors.
+	rgba := image.NewRGBA(img.Rect)
+	for y := range 8 {
+		for x := range 12 {
+			rgba.Set(x, y, img.At(x, y))
+		}
+	}
+	mask, err := MagicWand(cfg, rgba, rgba.Rect, image.Pt(0, 0), Wand{Tolerance: 10, Contiguous: true})
+	if err != nil {
+		t.Fatalf("MagicWand failed: %v", err)
+	}
+	if n := count(mask); n != 42 {
+		t.Errorf("RGBA: selected %d pixels, want 42", n)
+	}
+
+	if _, err := MagicWand(cfg, img, image.Rect(0, 0, 4, 4), image.Pt(8, 8), Wand{}); err == nil {
+		t.Error("expected error for seed outside region")
+	}
+}
+
+func TestFloodFillMatchesBFS(t *testing.T) {
+	// A spiral wall forces the fill to turn back through rows it has already visited.
+	wall := []string{
+		"#########",
+		"#.......#",
+		"#.#####.#",
+		"#.#...#.#",
+		"#.#.#.#.#",
+		"#.#.#...#",
+		"#.#.###.#",
+		"#...#..#.",
+	}
+	r := image.Rect(0, 0, 9, 8)
+	open := func(x, y int) bool { return wall[y][x] == '.' }
+	for _, diagonal := range []bool{false, true} {
+		mask := FloodFill(r, image.Pt(3, 3), diagonal, open)
+
+		want := map[image.Point]bool{{3, 3}: true}
+		queue := []image.Point{{3, 3}}
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					q := p.Add(image.Pt(dx, dy))
+					if (dx != 0 && dy != 0 && !diagonal) || !q.In(r) || want[q] || !open(q.X, q.Y) {
+						continue
+					}
+					want[q] = true
+					queue = append(queue, q)
+				}
+			}
+		}
+		for y := range 8 {
+			for x := range 9 {
+				if got := mask.AlphaAt(x, y).A == 255; got != want[image.Pt(x, y)] {
+					t.Errorf("diagonal %v: (%d, %d) selected = %v", diagonal, x, y, got)
+				}
+			}
+		}
+		// The bottom right pixel only touches the spiral through a corner.
+		if want[image.Pt(8, 7)] != diagonal {
+			t.Fatalf("diagonal %v: bad test fixture", diagonal)
+		}
+	}
+}
+
+func TestAlgebra(t *testing.T) {
+	a := Rect(image.Rect(0, 0, 4, 1), image.Rect(0, 0, 2, 1))
+	b := Rect(image.Rect(1, 0, 6, 1), image.Rect(1, 0, 3, 1))
+	tests := []struct {
+		name string
+		got  *image.Alpha
+		want []uint8
+	}{
+		{"union", Union(a, b), []uint8{255, 255, 255, 0, 0, 0}},
+		{"intersect", Intersect(a, b), []uint8{0, 255, 0, 0, 0, 0}},
+		{"subtract", Subtract(a, b), []uint8{255, 0, 0, 0, 0, 0}},
+		{"xor", Xor(a, b), []uint8{255, 0, 255, 0, 0, 0}},
+		{"invert", Invert(a, image.Rect(0, 0, 6, 1)), []uint8{0, 0, 255, 255, 255, 255}},
+	}
+	for _, tt := range tests {
+		if tt.got.Rect != image.Rect(0, 0, 6, 1) {
+			t.Errorf("%s: bounds = %v", tt.name, tt.got.Rect)
+		}
+		for x, v := range tt.want {
+			if got := tt.got.AlphaAt(x, 0).A; got != v {
+				t.Errorf("%s: pixel %d = %d, want %d", tt.name, x, got, v)
+			}
+		}
+	}
+}
+
+func TestShapes(t *testing.T) {
+	bounds := image.Rect(0, 0, 20, 20)
+	box := image.Rect(2, 4, 18, 16)
+
+	rect := Rect(bounds, box)
+	if n := count(rect); n != 16*12 {
+		t.Errorf("rect selected %d pixels, want %d", n, 16*12)
+	}
+
+	ellipse := Ellipse(bounds, box)
+	if a := ellipse.AlphaAt(10, 10).A; a != 255 {
+		t.Errorf("ellipse center = %d, want 255", a)
+	}
+	if a := ellipse.AlphaAt(2, 4).A; a != 0 {
+		t.Errorf("ellipse corner = %d, want 0", a)
+	}
+	if a := ellipse.AlphaAt(2, 10).A; a == 0 || a == 255 {
+		t.Errorf("ellipse edge = %d, want partial coverage", a)
+	}
+
+	rounded := RoundedRect(bounds, box, 4)
+	if a := rounded.AlphaAt(2, 4).A; a != 0 {
+		t.Errorf("rounded corner = %d, want 0", a)
+	}
+	if a := rounded.AlphaAt(2, 10).A; a != 255 {
+		t.Errorf("rounded side = %d, want 255", a)
+	}
+	square := RoundedRect(bounds, box, 0)
+	for i, v := range rect.Pix {
+		if square.Pix[i] != v {
+			t.Fatalf("zero radius differs from rect at %d: %d, want %d", i, square.Pix[i], v)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package selection
+
+import (
+	"image"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// Rect returns a mask with bounds that selects the pixels of rect.
+func Rect(bounds, rect image.Rectangle) *image.Alpha {
+	out := image.NewAlpha(bounds)
+	rect = rect.Intersect(bounds)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		row := out.Pix[out.PixOffset(rect.Min.X, y):]
+		for x := range rect.Dx() {
+			row[x] = 0xff
+		}
+	}
+	return out
+}
+
+// Ellipse returns a mask with bounds that selects the ellipse inscribed in box.
+// The edge is antialiased.
+func Ellipse(bounds, box image.Rectangle) *image.Alpha {
+	a, b := float64(box.Dx())/2, float64(box.Dy())/2
+	cx, cy := float64(box.Min.X)+a, float64(box.Min.Y)+b
+	return shape(bounds, func(x, y float64) float64 {
+		if a <= 0 || b <= 0 {
+			return math.Inf(1)
+		}
+		// Approximate distance to the ellipse from its implicit function and gradient. It is
+		// exact on the axes and accurate to a fraction of a pixel near the edge.
+		px, py := (x-cx)/a, (y-cy)/b
+		k0 := math.Hypot(px, py)
+		k1 := math.Hypot(px/a, py/b)
+		if k1 == 0 {
+			return -min(a, b)
+		}
+		return k0 * (k0 - 1) / k1
+	})
+}
+
+// RoundedRect returns a mask with bounds that selects box with corners rounded to radius.
+// The radius is limited to half of the smaller side of box. The edge is antialiased.
+func RoundedRect(bounds, box image.Rectangle, radius float64) *image.Alpha {
+	hw, hh := float64(box.Dx())/2, float64(box.Dy())/2
+	cx, cy := float64(box.Min.X)+hw, float64(box.Min.Y)+hh
+	radius = core.Clamp(radius, 0, min(hw, hh))
+	return shape(bounds, func(x, y float64) float64 {
+		if hw <= 0 || hh <= 0 {
+			return math.Inf(1)
+		}
+		qx := math.Abs(x-cx) - (hw - radius)
+		qy := math.Abs(y-cy) - (hh - radius)
+		outside := math.Hypot(max(qx, 0), max(qy, 0))
+		return outside + min(max(qx, qy), 0) - radius
+	})
+}
+
+// shape rasterizes a signed distance function, evaluated at pixel centers, into a mask.
+// Coverage ramps over one pixel centered on the edge.
+func shape(bounds image.Rectangle, dist func(x, y float64) float64) *image.Alpha {
+	out := image.NewAlpha(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := out.Pix[out.PixOffset(bounds.Min.X, y):]
+		for x := range bounds.Dx() {
+			d := dist(float64(bounds.Min.X+x)+0.5, float64(y)+0.5)
+			row[x] = uint8(core.Clamp(0.5-d, 0, 1)*255 + 0.5)
+		}
+	}
+	return out
+}
@@ -0,0 +1,289 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package tonemap converts high dynamic range images to 8-bit NRGBA or RGBA images.
+package tonemap
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/hdr"
+	"github.com/blazeroni/magpie/pkg/internal/plane"
+)
+
+// Mode defines the tone mapping curve.
+type Mode int
+
+const (
+	// Linear applies exposure and gamma only; values above 1 are clipped.
+	Linear Mode = iota
+	// Reinhard is the global photographic operator: L / (1 + L), with an optional white point.
+	Reinhard
+	// ReinhardLocal is the photographic operator with local dodging-and-burning.
+	ReinhardLocal
+	// ACES is Krzysztof Narkowicz's fit of the ACES filmic reference rendering transform.
+	ACES
+	// Hable is John Hable's filmic curve from Uncharted 2.
+	Hable
+
+	_maxMode
+)
+
+// Default values used when the corresponding Op fields are zero.
+const (
+	DefaultKey        = 0.18
+	DefaultHableWhite = 11.2
+)
+
+// Op describes a tone mapping operation.
+type Op struct {
+	Mode Mode
+	// Exposure is an exposure adjustment in stops applied before the curve.
+	// When AutoExposure is set it acts as exposure compensation.
+	Exposure float64
+	// AutoExposure scales the image so its log-average luminance maps to Key.
+	AutoExposure bool
+	// Key is the target middle grey for AutoExposure and ReinhardLocal. Zero means DefaultKey.
+	Key float64
+	// White is the smallest luminance mapped to pure white by Reinhard, or the linear white
+	// point for Hable. Zero disables the white point for Reinhard and uses DefaultHableWhite for Hable.
+	White float64
+	// Gamma is the display gamma used to encode the result. Zero encodes with the sRGB transfer function.
+	Gamma float64
+}
+
+// IsValid reports whether the operation can be applied.
+func (o Op) IsValid() bool {
+	return o.Mode >= 0 && o.Mode < _maxMode &&
+		o.Key >= 0 && o.White >= 0 && o.Gamma >= 0 &&
+		!math.IsNaN(o.Exposure) && !math.IsInf(o.Exposure, 0)
+}
+
+// Apply tone maps the region r of src to an 8-bit image using the iterator and defaults of cfg.
+// Since src cannot be written to, an output of ToDst (or a nil output with a default of ToDst)
+// creates a new image. The output must be an *image.NRGBA or *image.RGBA; the result is opaque.
+func Apply(cfg core.Config, src *hdr.Image, r image.Rectangle, o Op, output core.Output) (image.Image, error) {
+	if !o.IsValid() {
+		return nil, errors.New("invalid tone mapping operation")
+	}
+	r = r.Intersect(src.Rect)
+
+	out, outPt, err := core.ResolveOutput(output, cfg.DefaultOutputMode(), nil, r, cfg.DefaultColorModel())
+	if err != nil {
+		return nil, err
+	}
+	if out.Bounds().Empty() {
+		return out, nil
+	}
+	orig := r.Min
+	r = r.Intersect(out.Bounds().Add(r.Min.Sub(outPt)))
+	outPt = outPt.Add(r.Min.Sub(orig))
+
+	pixIter := cfg.PixelIterator()
+	scale := math.Exp2(o.Exposure)
+	if o.AutoExposure {
+		scale *= o.key() / LogAverageLuminance(pixIter, src, r)
+	}
+
+	curve := o.curve(pixIter, src, r, scale)
+	encode := o.encoder()
+
+	// The result is opaque, so NRGBA and RGBA share the same representation.
+	var pix []uint8
+	var stride int
+	switch img := out.(type) {
+	case *image.NRGBA:
+		pix, stride = img.Pix, img.Stride
+		outPt = outPt.Sub(img.Rect.Min)
+	case *image.RGBA:
+		pix, stride = img.Pix, img.Stride
+		outPt = outPt.Sub(img.Rect.Min)
+	default:
+		return nil, fmt.Errorf("unsupported output color model %v", out.ColorModel())
+	}
+
+	core.IterateRows(pixIter, r.Dy(), func(row int) {
+		y := r.Min.Y + row
+		oi := (outPt.Y+row)*stride + outPt.X*4
+		si := src.PixOffset(r.Min.X, y)
+		for x := range r.Dx() {
+			cr, cg, cb := curve(x, row, src.Pix[si]*float32(scale), src.Pix[si+1]*float32(scale), src.Pix[si+2]*float32(scale))
+			pix[oi], pix[oi+1], pix[oi+2], pix[oi+3] = encode(cr), encode(cg), encode(cb), 255
+			si += 3
+			oi += 4
+		}
+	})
+	return out, nil
+}
+
+// LogAverageLuminance returns the geometric mean of the luminance over r of src.
+// Rows are reduced in order so the result is independent of the iterator's concurrency.
+func LogAverageLuminance(pixIter core.PixelIterator, src *hdr.Image, r image.Rectangle) float64 {
+	const delta = 1e-4
+	r = r.Intersect(src.Rect)
+	if r.Empty() {
+		return DefaultKey
+	}
+	sums := make([]float64, r.Dy())
+	core.IterateRows(pixIter, r.Dy(), func(row int) {
+		var sum float64
+		si := src.PixOffset(r.Min.X, r.Min.Y+row)
+		for range r.Dx() {
+			l := float64(hdr.Luminance(src.Pix[si], src.Pix[si+1], src.Pix[si+2]))
+			sum += math.Log(delta + math.Max(l, 0))
+			si += 3
+		}
+		sums[row] = sum
+	})
+	var total float64
+	for _, s := range sums {
+		total += s
+	}
+	return math.Exp(total / float64(r.Dx()*r.Dy()))
+}
+
+func (o Op) key() float64 {
+	if o.Key == 0 {
+		return DefaultKey
+	}
+	return o.Key
+}
+
+// curveFunc maps exposed linear RGB at (x, y) relative to the mapped region to display-linear RGB.
+type curveFunc func(x, y int, r, g, b float32) (float32, float32, float32)
+
+func (o Op) curve(pixIter core.PixelIterator, src *hdr.Image, r image.Rectangle, scale float64) curveFunc {
+	switch o.Mode {
+	case Reinhard:
+		white2 := float32(o.White * o.White)
+		return func(_, _ int, cr, cg, cb float32) (float32, float32, float32) {
+			l := hdr.Luminance(cr, cg, cb)
+			if l <= 0 {
+				return 0, 0, 0
+			}
+			ld := l / (1 + l)
+			if white2 > 0 {
+				ld = l * (1 + l/white2) / (1 + l)
+			}
+			s := ld / l
+			return cr * s, cg * s, cb * s
+		}
+	case ReinhardLocal:
+		adapt := localAdaptation(pixIter, src, r, scale, o.key())
+		return func(x, y int, cr, cg, cb float32) (float32, float32, float32) {
+			l := hdr.Luminance(cr, cg, cb)
+			if l <= 0 {
+				return 0, 0, 0
+			}
+			s := 1 / (1 + adapt.Pix[y*adapt.W+x])
+			return cr * s, cg * s, cb * s
+		}
+	case ACES:
+		return func(_, _ int, cr, cg, cb float32) (float32, float32, float32) {
+			return aces(cr), aces(cg), aces(cb)
+		}
+	case Hable:
+		white := o.White
+		if white == 0 {
+			white = DefaultHableWhite
+		}
+		invWhite := float32(1 / hable(white))
+		return func(_, _ int, cr, cg, cb float32) (float32, float32, float32) {
+			return float32(hable(float64(cr))) * invWhite, float32(hable(float64(cg))) * invWhite, float32(hable(float64(cb))) * invWhite
+		}
+	case Linear, _maxMode:
+	}
+	return func(_, _ int, cr, cg, cb float32) (float32, float32, float32) {
+		return cr, cg, cb
+	}
+}
+
+// encoder returns the function used to quantize display-linear values to 8 bits.
+func (o Op) encoder() func(v float32) uint8 {
+	var lut [4096]uint8
+	for i := range lut {
+		v := float64(i) / float64(len(lut)-1)
+		if o.Gamma == 0 {
+			v = hdr.LinearToSRGB(v)
+		} else {
+			v = math.Pow(v, 1/o.Gamma)
+		}
+		lut[i] = uint8(v*255 + 0.5)
+	}
+	return func(v float32) uint8 {
+		if !(v > 0) {
+			return 0
+		}
+		if v >= 1 {
+			return 255
+		}
+		return lut[int(v*float32(len(lut)-1)+0.5)]
+	}
+}
+
+// aces is Narkowicz's approximation of the ACES filmic curve.
+func aces(x float32) float32 {
+	const a, b, c, d, e = 2.51, 0.03, 2.43, 0.59, 0.14
+	if x <= 0 {
+		return 0
+	}
+	return core.Clamp((x*(a*x+b))/(x*(c*x+d)+e), 0, 1)
+}
+
+// hable is John Hable's Uncharted 2 filmic curve before white point normalization.
+func hable(x float64) float64 {
+	const a, b, c, d, e, f = 0.15, 0.50, 0.10, 0.20, 0.02, 0.30
+	x = math.Max(x, 0)
+	return ((x*(a*x+c*b) + d*e) / (x*(a*x+b) + d*f)) - e/f
+}
+
+// localAdaptation computes Reinhard's local adaptation luminance for every pixel of r,
+// choosing the largest center-surround scale whose normalized difference stays below a threshold.
+func localAdaptation(pixIter core.PixelIterator, src *hdr.Image, r image.Rectangle, scale, key float64) *plane.Plane {
+	const (
+		scales    = 8
+		ratio     = 1.6
+		alpha     = 0.35355339 // 1 / (2 * sqrt(2))
+		sharpness = 8.0
+		epsilon   = 0.05
+	)
+
+	lum := plane.New(r.Dx(), r.Dy())
+	core.IterateRows(pixIter, r.Dy(), func(row int) {
+		si := src.PixOffset(r.Min.X, r.Min.Y+row)
+		dst := lum.Row(row)
+		for x := range dst {
+			dst[x] = hdr.Luminance(src.Pix[si], src.Pix[si+1], src.Pix[si+2]) * float32(scale)
+			si += 3
+		}
+	})
+
+	blurred := make([]*plane.Plane, scales+1)
+	for i := range blurred {
+		blurred[i] = plane.GaussianBlur(pixIter, lum, alpha*math.Pow(ratio, float64(i)))
+	}
+
+	adapt := plane.New(r.Dx(), r.Dy())
+	core.IterateRows(pixIter, r.Dy(), func(row int) {
+		out := adapt.Row(row)
+		for x := range out {
+			i := row*lum.W + x
+			v := blurred[0].Pix[i]
+			for s := range scales {
+				s1 := alpha * math.Pow(ratio, float64(s))
+				v1, v2 := blurred[s].Pix[i], blurred[s+1].Pix[i]
+				norm := float32(math.Exp2(sharpness)*key/(s1*s1)) + v1
+				if float32(math.Abs(float64((v1-v2)/norm))) >= epsilon {
+					break
+				}
+				v = v1
+			}
+			out[x] = v
+		}
+	})
+	return adapt
+}
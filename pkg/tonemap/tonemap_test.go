@@ -0,0 +1,156 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package tonemap
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/hdr"
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+func gradient() *hdr.Image {
+	img := hdr.NewImage(image.Rect(0, 0, 32, 8))
+	for y := range 8 {
+		for x := range 32 {
+			// Luminance spans several orders of magnitude.
+			v := float32(math.Pow(10, float64(x)/8-2))
+			img.SetRGB(x, y, v, v*0.8, v*0.5)
+		}
+	}
+	return img
+}
+
+func TestApplyModes(t *testing.T) {
+	src := gradient()
+	modes := map[string]Op{
+		"Linear":        {Mode: Linear},
+		"Reinhard":      {Mode: Reinhard},
+		"ReinhardWhite": {Mode: Reinhard, White: 4},
+		"ReinhardLocal": {Mode: ReinhardLocal, AutoExposure: true},
+		"ACES":          {Mode: ACES},
+		"Hable":         {Mode: Hable, Exposure: 1},
+		"Gamma":         {Mode: ACES, Gamma: 2.2},
+	}
+	for name, o := range modes {
+		t.Run(name, func(t *testing.T) {
+			out, err := Apply(internal.DefaultConfig, src, src.Rect, o, core.ToNewNRGBAImage())
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			img := out.(*image.NRGBA)
+			prev := -1
+			for x := range 32 {
+				c := img.NRGBAAt(x, 4)
+				if c.A != 255 {
+					t.Fatalf("alpha at %d = %d, want 255", x, c.A)
+				}
+				// Global curves are monotonic on a horizontal luminance ramp.
+				if o.Mode != ReinhardLocal && int(c.G) < prev {
+					t.Fatalf("output not monotonic at x=%d: %d < %d", x, c.G, prev)
+				}
+				prev = int(c.G)
+			}
+			if prev != 255 && o.Mode == Linear {
+				t.Errorf("linear mode should clip highlights, got %d", prev)
+			}
+		})
+	}
+}
+
+func TestReinhardValues(t *testing.T) {
+	src := hdr.NewImage(image.Rect(0, 0, 2, 1))
+	src.SetRGB(0, 0, 1, 1, 1)
+	src.SetRGB(1, 0, 4, 4, 4)
+
+	out, err := Apply(internal.DefaultConfig, src, src.Rect, Op{Mode: Reinhard, White: 4, Gamma: 1}, core.ToNewRGBAImage())
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	img := out.(*image.RGBA)
+	// L=1: 1 * (1 + 1/16) / 2 = 0.53125
+	if got := img.RGBAAt(0, 0); got != (color.RGBA{R: 135, G: 135, B: 135, A: 255}) {
+		t.Errorf("RGBAAt(0, 0) = %v, want 135", got)
+	}
+	// L equal to the white point maps to 1.
+	if got := img.RGBAAt(1, 0); got != (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Errorf("RGBAAt(1, 0) = %v, want 255", got)
+	}
+}
+
+func TestAutoExposureDeterministic(t *testing.T) {
+	src := gradient()
+	serial := LogAverageLuminance(core.NewSerialPixelIterator(), src, src.Rect)
+	parallel := LogAverageLuminance(core.NewParallelPixelIterator(4), src, src.Rect)
+	if serial != parallel {
+		t.Errorf("LogAverageLuminance differs: serial %v, parallel %v", serial, parallel)
+	}
+
+	uniform := hdr.NewImage(image.Rect(0, 0, 4, 4))
+	for i := range uniform.Pix {
+		uniform.Pix[i] = 2
+	}
+	if got := LogAverageLuminance(core.NewSerialPixelIterator(), uniform, uniform.Rect); math.Abs(got-2) > 1e-3 {
+		t.Errorf("LogAverageLuminance(uniform 2) = %v, want ~2", got)
+	}
+
+	out, err := Apply(internal.DefaultConfig, uniform, uniform.Rect, Op{Mode: Linear, AutoExposure: true, Gamma: 1}, core.ToNewNRGBAImage())
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	// The log-average maps to the default key of 0.18.
+	if got := out.(*image.NRGBA).NRGBAAt(0, 0).R; got < 45 || got > 47 {
+		t.Errorf("auto exposed value = %d, want ~46", got)
+	}
+}
+
+func TestApplyToProvidedImage(t *testing.T) {
+	src := gradient()
+	dst := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	out, err := Apply(internal.DefaultConfig, src, src.Rect, Op{Mode: ACES}, core.ToImage(dst, image.Pt(5, 5)))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if out != dst {
+		t.Fatal("Apply should write to the provided image")
+	}
+	if dst.NRGBAAt(4, 4).A != 0 || dst.NRGBAAt(9, 9).A != 255 {
+		t.Error("Apply wrote outside of the expected region")
+	}
+}
+
+func TestApplyInvalid(t *testing.T) {
+	src := gradient()
+	if _, err := Apply(internal.DefaultConfig, src, src.Rect, Op{Mode: -1}, nil); err == nil {
+		t.Error("Apply with invalid mode should fail")
+	}
+	if _, err := Apply(internal.DefaultConfig, src, src.Rect, Op{Mode: ACES, Gamma: -1}, nil); err == nil {
+		t.Error("Apply with negative gamma should fail")
+	}
+}
+
+func TestEndToEndFromRGBE(t *testing.T) {
+	src := gradient()
+	var buf bytes.Buffer
+	if err := hdr.Encode(&buf, src); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	decoded, err := hdr.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	want, _ := Apply(internal.DefaultConfig, src, src.Rect, Op{Mode: Hable}, core.ToNewNRGBAImage())
+	got, _ := Apply(internal.DefaultConfig, decoded, decoded.Rect, Op{Mode: Hable}, core.ToNewNRGBAImage())
+	w, g := want.(*image.NRGBA), got.(*image.NRGBA)
+	for i := range w.Pix {
+		if d := int(w.Pix[i]) - int(g.Pix[i]); d < -2 || d > 2 {
+			t.Fatalf("sample %d differs after RGBE round trip: %d vs %d", i, w.Pix[i], g.Pix[i])
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+// Fill draws src through the coverage of the path onto the region r of dst using o, which may
+// be any op.BlendOp or op.CompositeOp.
+//
+// The arguments follow magpie.Draw: path coordinates are in dst's coordinate space and sp is
+// the point in src aligned with r.Min. The source is masked by the path's coverage and the
+// operation is applied to all of r, so operations that affect the destination where the
+// source is transparent (e.g. composite.Source()) also do so outside the path.
+func Fill(cfg core.Config, dst image.Image, r image.Rectangle, p *Path, rule FillRule, src image.Image, sp image.Point, o core.Op, output core.Output) (image.Image, error) {
+	if o == nil || !o.IsValid() || !rule.IsValid() {
+		return nil, errors.New("invalid fill operation")
+	}
+	orig := r.Min
+	r = r.Intersect(dst.Bounds())
+	sp = sp.Add(r.Min.Sub(orig))
+
+	model := cfg.DefaultColorModel()
+	switch {
+	case output != nil && core.IsColorModelSupported(output.ColorModel()):
+		model = output.ColorModel()
+	case core.IsColorModelSupported(dst.ColorModel()):
+		model = dst.ColorModel()
+	}
+	out, outPt, err := core.ResolveOutput(output, cfg.DefaultOutputMode(), dst, r, model)
+	if err != nil {
+		return nil, err
+	}
+	if r.Empty() {
+		return out, nil
+	}
+
+	pixIter := cfg.PixelIterator()
+	mask := Rasterize(cfg, p, r, rule)
+	masked := image.NewNRGBA(r)
+	draw.Draw(masked, r, src, sp, draw.Src)
+	core.IterateRows(pixIter, r.Dy(), func(y int) {
+		pix := masked.Pix[y*masked.Stride:]
+		cov := mask.Pix[y*mask.Stride : y*mask.Stride+r.Dx()]
+		for x, c := range cov {
+			pix[x*4+3] = uint8(internal.Md255(uint32(pix[x*4+3]), uint32(c)))
+		}
+	})
+
+	switch model {
+	case color.NRGBAModel:
+		dstN, outN := internal.AsNRGBA(dst), internal.AsNRGBA(out)
+		return o.ApplyNRGBA(pixIter, core.NewPixCalculatorNRGBA(dstN, r, masked, r.Min, outN, outPt)), nil
+	case color.RGBAModel:
+		srcR := image.NewRGBA(r)
+		draw.Draw(srcR, r, masked, r.Min, draw.Src)
+		dstR, outR := internal.AsRGBA(dst), internal.AsRGBA(out)
+		return o.ApplyRGBA(pixIter, core.NewPixCalculatorRGBA(dstR, r, srcR, r.Min, outR, outPt)), nil
+	default:
+		return nil, fmt.Errorf("unsupported color model %v", model)
+	}
+}
+
+// FillColor draws the path filled with a solid color onto the region r of dst using o.
+// See Fill for details.
+func FillColor(cfg core.Config, dst image.Image, r image.Rectangle, p *Path, rule FillRule, c color.Color, o core.Op, output core.Output) (image.Image, error) {
+	return Fill(cfg, dst, r, p, rule, image.NewUniform(c), image.Point{}, o, output)
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+// Package vector rasterizes vector paths into anti-aliased coverage masks and fills them
+// through the magpie blend and composite operations.
+//
+// Coordinates are in pixels with the y-axis pointing down, so pixel (x, y) covers the square
+// from (x, y) to (x+1, y+1). Coverage is computed from the exact area of each pixel inside the
+// path rather than by supersampling.
+package vector
+
+import (
+	"math"
+
+	"github.com/blazeroni/magpie/pkg/core"
+)
+
+// Tolerance is the maximum distance in pixels between a curve and the line segments
+// used to approximate it.
+const Tolerance = 0.02
+
+// Point is a point in pixel coordinates.
+type Point struct {
+	X, Y float64
+}
+
+// Pt is shorthand for Point{X: x, Y: y}.
+func Pt(x, y float64) Point {
+	return Point{X: x, Y: y}
+}
+
+func (p Point) add(q Point) Point             { return Point{p.X + q.X, p.Y + q.Y} }
+func (p Point) sub(q Point) Point             { return Point{p.X - q.X, p.Y - q.Y} }
+func (p Point) mul(s float64) Point           { return Point{p.X * s, p.Y * s} }
+func (p Point) dot(q Point) float64           { return p.X*q.X + p.Y*q.Y }
+func (p Point) cross(q Point) float64         { return p.X*q.Y - p.Y*q.X }
+func (p Point) length() float64               { return math.Hypot(p.X, p.Y) }
+func (p Point) perp() Point                   { return Point{-p.Y, p.X} }
+func (p Point) lerp(q Point, t float64) Point { return p.add(q.sub(p).mul(t)) }
+
+func (p Point) normalize() Point {
+	l := p.length()
+	if l == 0 {
+		return Point{}
+	}
+	return p.mul(1 / l)
+}
+
+// Path is a sequence of subpaths built from lines and curves. The zero value is an empty path.
+type Path struct {
+	// subpaths holds the flattened polylines of the path.
+	subpaths []subpath
+	start    Point
+	hasStart bool
+}
+
+// subpath is a flattened polyline. Closed subpaths connect their last point to the first.
+type subpath struct {
+	points []Point
+	closed bool
+}
+
+// MoveTo starts a new subpath at (x, y).
+func (p *Path) MoveTo(x, y float64) {
+	p.start = Pt(x, y)
+	p.hasStart = true
+	p.subpaths = append(p.subpaths, subpath{points: []Point{p.start}})
+}
+
+// LineTo adds a straight line to (x, y). Without a current point it behaves as MoveTo.
+func (p *Path) LineTo(x, y float64) {
+	if !p.hasCurrent() {
+		p.MoveTo(x, y)
+		return
+	}
+	p.add(Pt(x, y))
+}
+
+// QuadTo adds a quadratic Bézier curve with control point (cx, cy) ending at (x, y).
+func (p *Path) QuadTo(cx, cy, x, y float64) {
+	if !p.hasCurrent() {
+		p.MoveTo(cx, cy)
+	}
+	p0, p1, p2 := p.current(), Pt(cx, cy), Pt(x, y)
+	dd := p0.sub(p1.mul(2)).add(p2).length()
+	n := segments(math.Sqrt(dd / (4 * Tolerance)))
+	for i := 1; i <= n; i++ {
+		t := float64(i) / float64(n)
+		p.add(p0.lerp(p1, t).lerp(p1.lerp(p2, t), t))
+	}
+}
+
+// CubicTo adds a cubic Bézier curve with control points (c1x, c1y) and (c2x, c2y)
+// ending at (x, y).
+func (p *Path) CubicTo(c1x, c1y, c2x, c2y, x, y float64) {
+	if !p.hasCurrent() {
+		p.MoveTo(c1x, c1y)
+	}
+	p0, p1, p2, p3 := p.current(), Pt(c1x, c1y), Pt(c2x, c2y), Pt(x, y)
+	dd := max(p0.sub(p1.mul(2)).add(p2).length(), p1.sub(p2.mul(2)).add(p3).length())
+	n := segments(math.Sqrt(3 * dd / (4 * Tolerance)))
+	for i := 1; i <= n; i++ {
+		t := float64(i) / float64(n)
+		a, b, c := p0.lerp(p1, t), p1.lerp(p2, t), p2.lerp(p3, t)
+		p.add(a.lerp(b, t).lerp(b.lerp(c, t), t))
+	}
+}
+
+// Arc adds a circular arc centered at (cx, cy) from angle start to angle end, in radians.
+// Angles increase clockwise on screen, and the arc is drawn in the direction from start to
+// end. A line connects the current point, if any, to the start of the arc.
+func (p *Path) Arc(cx, cy, radius, start, end float64) {
+	begin := Pt(cx+radius*math.Cos(start), cy+radius*math.Sin(start))
+	switch {
+	case !p.hasCurrent():
+		p.MoveTo(begin.X, begin.Y)
+	case p.current() != begin:
+		p.add(begin)
+	}
+	sweep := end - start
+	// The chord of a step of angle a deviates from the arc by r(1-cos(a/2)).
+	step := 2 * math.Acos(max(1-Tolerance/max(radius, Tolerance), -1))
+	n := segments(math.Abs(sweep) / step)
+	for i := 1; i <= n; i++ {
+		a := start + sweep*float64(i)/float64(n)
+		p.add(Pt(cx+radius*math.Cos(a), cy+radius*math.Sin(a)))
+	}
+}
+
+// Close closes the current subpath with a straight line back to its start.
+// The next segment starts a new subpath at the same point.
+func (p *Path) Close() {
+	if !p.hasCurrent() {
+		return
+	}
+	p.subpaths[len(p.subpaths)-1].closed = true
+	p.MoveTo(p.start.X, p.start.Y)
+}
+
+// Rect adds a closed rectangle with corners at (x, y) and (x+w, y+h).
+func (p *Path) Rect(x, y, w, h float64) {
+	p.MoveTo(x, y)
+	p.LineTo(x+w, y)
+	p.LineTo(x+w, y+h)
+	p.LineTo(x, y+h)
+	p.Close()
+}
+
+// RoundedRect adds a closed rectangle with corners rounded to radius. The radius is limited
+// to half of the smaller side.
+func (p *Path) RoundedRect(x, y, w, h, radius float64) {
+	radius = min(max(radius, 0), math.Abs(w)/2, math.Abs(h)/2)
+	if radius == 0 {
+		p.Rect(x, y, w, h)
+		return
+	}
+	p.MoveTo(x+radius, y)
+	p.Arc(x+w-radius, y+radius, radius, -math.Pi/2, 0)
+	p.Arc(x+w-radius, y+h-radius, radius, 0, math.Pi/2)
+	p.Arc(x+radius, y+h-radius, radius, math.Pi/2, math.Pi)
+	p.Arc(x+radius, y+radius, radius, math.Pi, 3*math.Pi/2)
+	p.Close()
+}
+
+// Circle adds a closed circle.
+func (p *Path) Circle(cx, cy, radius float64) {
+	p.MoveTo(cx+radius, cy)
+	p.Arc(cx, cy, radius, 0, 2*math.Pi)
+	p.Close()
+}
+
+// Bounds returns the smallest rectangle containing all points of the path, in floating point
+// coordinates as min and max corners.
+func (p *Path) Bounds() (Point, Point) {
+	mn := Pt(math.Inf(1), math.Inf(1))
+	mx := Pt(math.Inf(-1), math.Inf(-1))
+	for _, sp := range p.subpaths {
+		for _, q := range sp.points {
+			mn = Pt(min(mn.X, q.X), min(mn.Y, q.Y))
+			mx = Pt(max(mx.X, q.X), max(mx.Y, q.Y))
+		}
+	}
+	return mn, mx
+}
+
+func (p *Path) hasCurrent() bool {
+	return p.hasStart && len(p.subpaths) > 0
+}
+
+func (p *Path) current() Point {
+	pts := p.subpaths[len(p.subpaths)-1].points
+	return pts[len(pts)-1]
+}
+
+// add appends a point to the current subpath.
+func (p *Path) add(q Point) {
+	sp := &p.subpaths[len(p.subpaths)-1]
+	sp.points = append(sp.points, q)
+}
+
+// segments rounds an estimated number of segments up and limits it to a sensible range.
+func segments(n float64) int {
+	return int(core.Clamp(math.Ceil(n), 1, 1000))
+}
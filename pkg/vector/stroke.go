@@ -0,0 +1,284 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package vector
+
+import "math"
+
+// Join defines the shape drawn where two segments of a stroke meet.
+type Join int
+
+const (
+	// MiterJoin extends the outer edges until they meet, falling back to a bevel when the
+	// miter would exceed the miter limit.
+	MiterJoin Join = iota
+	// RoundJoin rounds the outer corner with a circular arc.
+	RoundJoin
+	// BevelJoin cuts the outer corner with a straight line.
+	BevelJoin
+
+	_maxJoin
+)
+
+// Cap defines the shape drawn at the ends of open strokes.
+type Cap int
+
+const (
+	// ButtCap ends the stroke exactly at the end point.
+	ButtCap Cap = iota
+	// RoundCap adds a half circle around the end point.
+	RoundCap
+	// SquareCap extends the stroke by half its width past the end point.
+	SquareCap
+
+	_maxCap
+)
+
+// DefaultMiterLimit is the miter limit used when Stroke.MiterLimit is zero.
+const DefaultMiterLimit = 4
+
+// Stroke describes how to outline a path.
+type Stroke struct {
+	Width float64
+	Join  Join
+	Cap   Cap
+	// MiterLimit is the largest ratio of miter length to stroke width drawn as a miter.
+	MiterLimit float64
+	// Dash alternates lengths of drawn and skipped stroke, starting with a drawn one. An odd
+	// number of lengths is repeated to make it even. No dashing is applied when it is empty.
+	Dash []float64
+	// DashOffset is the distance into the dash pattern at which the stroke starts.
+	DashOffset float64
+}
+
+// IsValid reports whether the stroke can be applied.
+func (s Stroke) IsValid() bool {
+	if s.Width < 0 || s.MiterLimit < 0 || s.Join < 0 || s.Join >= _maxJoin || s.Cap < 0 || s.Cap >= _maxCap {
+		return false
+	}
+	for _, d := range s.Dash {
+		if d < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Outline returns a path whose NonZero fill covers the stroke of p. The outline is built from
+// one positively oriented polygon per segment, join and cap, so overlapping pieces merge.
+func (s Stroke) Outline(p *Path) *Path {
+	out := &Path{}
+	hw := s.Width / 2
+	if hw <= 0 || !s.IsValid() {
+		return out
+	}
+	limit := s.MiterLimit
+	if limit == 0 {
+		limit = DefaultMiterLimit
+	}
+	pattern := dashPattern(s.Dash)
+
+	for _, sp := range p.subpaths {
+		pts, closed := dedupe(sp.points, sp.closed)
+		lines := [][]Point{pts}
+		if pattern != nil {
+			lines = dash(pts, closed, pattern, s.DashOffset)
+			closed = false
+		}
+		for _, line := range lines {
+			if len(line) >= 2 {
+				s.outlineLine(out, line, closed, hw, limit)
+			}
+		}
+	}
+	return out
+}
+
+// outlineLine adds the outline of a single polyline.
+func (s Stroke) outlineLine(out *Path, pts []Point, closed bool, hw, limit float64) {
+	n := len(pts)
+	segs := n - 1
+	if closed {
+		segs = n
+	}
+	dirs := make([]Point, segs)
+	for i := range segs {
+		a, b := pts[i], pts[(i+1)%n]
+		d := b.sub(a).normalize()
+		dirs[i] = d
+		off := d.perp().mul(hw)
+		polygon(out, a.add(off), b.add(off), b.sub(off), a.sub(off))
+	}
+
+	for i := range segs {
+		if i == 0 && !closed {
+			continue
+		}
+		prev := (i - 1 + segs) % segs
+		s.join(out, pts[i], dirs[prev], dirs[i], hw, limit)
+	}
+
+	if !closed {
+		s.capEnd(out, pts[0], dirs[0].mul(-1), hw)
+		s.capEnd(out, pts[n-1], dirs[segs-1], hw)
+	}
+}
+
+// join adds the join at p between segments with directions d0 and d1.
+func (s Stroke) join(out *Path, p, d0, d1 Point, hw, limit float64) {
+	cross := d0.cross(d1)
+	if math.Abs(cross) < 1e-9 && d0.dot(d1) > 0 {
+		return
+	}
+	// The outer side of the corner is opposite to the direction of the turn.
+	sign := 1.0
+	if cross > 0 {
+		sign = -1
+	}
+	n0, n1 := d0.perp().mul(sign), d1.perp().mul(sign)
+	switch s.Join {
+	case RoundJoin:
+		a0 := math.Atan2(n0.Y, n0.X)
+		sweep := math.Atan2(n0.cross(n1), n0.dot(n1))
+		wedge(out, p, hw, a0, a0+sweep)
+	case MiterJoin:
+		bisector := n0.add(n1).normalize()
+		if cosHalf := bisector.dot(n0); cosHalf > 1e-9 && 1/cosHalf <= limit {
+			polygon(out, p, p.add(n0.mul(hw)), p.add(bisector.mul(hw/cosHalf)), p.add(n1.mul(hw)))
+			return
+		}
+		fallthrough
+	case BevelJoin, _maxJoin:
+		polygon(out, p, p.add(n0.mul(hw)), p.add(n1.mul(hw)))
+	}
+}
+
+// capEnd adds the cap at the end point p of a stroke leaving in direction d.
+func (s Stroke) capEnd(out *Path, p, d Point, hw float64) {
+	n := d.perp().mul(hw)
+	switch s.Cap {
+	case RoundCap:
+		a := math.Atan2(n.Y, n.X)
+		wedge(out, p, hw, a, a-math.Pi)
+	case SquareCap:
+		e := d.mul(hw)
+		polygon(out, p.add(n), p.add(n).add(e), p.sub(n).add(e), p.sub(n))
+	case ButtCap, _maxCap:
+	}
+}
+
+// wedge adds a circular sector centered at c from angle a0 to a1.
+func wedge(out *Path, c Point, r, a0, a1 float64) {
+	step := 2 * math.Acos(max(1-Tolerance/max(r, Tolerance), -1))
+	n := segments(math.Abs(a1-a0) / step)
+	pts := make([]Point, 0, n+2)
+	pts = append(pts, c)
+	for i := 0; i <= n; i++ {
+		a := a0 + (a1-a0)*float64(i)/float64(n)
+		pts = append(pts, Pt(c.X+r*math.Cos(a), c.Y+r*math.Sin(a)))
+	}
+	polygon(out, pts...)
+}
+
+// polygon adds a closed polygon to out, reversed if needed so that its signed area is
+// positive. Consistent orientation makes overlapping polygons union under NonZero.
+func polygon(out *Path, pts ...Point) {
+	var area float64
+	for i, a := range pts {
+		area += a.cross(pts[(i+1)%len(pts)])
+	}
+	if area == 0 {
+		return
+	}
+	sp := subpath{points: make([]Point, len(pts)), closed: true}
+	for i, q := range pts {
+		if area < 0 {
+			q = pts[len(pts)-1-i]
+		}
+		sp.points[i] = q
+	}
+	out.subpaths = append(out.subpaths, sp)
+}
+
+// dedupe removes consecutive duplicate points, including a closing point equal to the first.
+func dedupe(pts []Point, closed bool) ([]Point, bool) {
+	out := make([]Point, 0, len(pts))
+	for _, q := range pts {
+		if len(out) == 0 || out[len(out)-1] != q {
+			out = append(out, q)
+		}
+	}
+	if closed && len(out) > 1 && out[0] == out[len(out)-1] {
+		out = out[:len(out)-1]
+	}
+	return out, closed && len(out) > 2
+}
+
+// dashPattern returns the dash lengths doubled to an even count, or nil if no dashing applies.
+func dashPattern(d []float64) []float64 {
+	var total float64
+	for _, v := range d {
+		total += v
+	}
+	if len(d) == 0 || total <= 0 {
+		return nil
+	}
+	if len(d)%2 == 1 {
+		return append(append([]float64{}, d...), d...)
+	}
+	return d
+}
+
+// dash splits a polyline into the open polylines drawn by the dash pattern.
+func dash(pts []Point, closed bool, pattern []float64, offset float64) [][]Point {
+	var total float64
+	for _, v := range pattern {
+		total += v
+	}
+	offset = math.Mod(offset, total)
+	if offset < 0 {
+		offset += total
+	}
+	i := 0
+	for offset >= pattern[i] {
+		offset -= pattern[i]
+		i = (i + 1) % len(pattern)
+	}
+	remaining := pattern[i] - offset
+
+	var lines [][]Point
+	var current []Point
+	if i%2 == 0 {
+		current = []Point{pts[0]}
+	}
+	n := len(pts)
+	segs := n - 1
+	if closed {
+		segs = n
+	}
+	for s := range segs {
+		a, b := pts[s], pts[(s+1)%n]
+		length := b.sub(a).length()
+		t := 0.0
+		for length-t > remaining {
+			t += remaining
+			q := a.lerp(b, t/length)
+			if i%2 == 0 {
+				lines = append(lines, append(current, q))
+				current = nil
+			} else {
+				current = []Point{q}
+			}
+			i = (i + 1) % len(pattern)
+			remaining = pattern[i]
+		}
+		remaining -= length - t
+		if current != nil {
+			current = append(current, b)
+		}
+	}
+	if current != nil {
+		lines = append(lines, current)
+	}
+	return lines
+}
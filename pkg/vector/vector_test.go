@@ -0,0 +1,266 @@
+// Copyright 2025 Magpie Contributors
+// SPDX-License-Identifier: MIT
+
+package vector
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+
+	"github.com/blazeroni/magpie/pkg/blend"
+	"github.com/blazeroni/magpie/pkg/composite"
+	"github.com/blazeroni/magpie/pkg/core"
+	"github.com/blazeroni/magpie/pkg/internal"
+)
+
+// area returns the total coverage of a mask in pixels.
+func area(m *image.Alpha) float64 {
+	var sum float64
+	for _, v := range m.Pix {
+		sum += float64(v) / 255
+	}
+	return sum
+}
+
+func rasterize(p *Path, rule FillRule) *image.Alpha {
+	return Rasterize(internal.DefaultConfig, p, image.Rect(0, 0, 40, 40), rule)
+}
+
+func TestRasterizeExactArea(t *testing.T) {
+	var p Path
+	p.Rect(1.5, 1.25, 3, 2)
+	m := rasterize(&p, NonZero)
+	tests := []struct {
+		x, y int
+		want uint8
+	}{
+		{1, 1, 96},  // 0.5 * 0.75
+		{2, 1, 191}, // 0.75
+		{2, 2, 255},
+		{4, 3, 32}, // 0.5 * 0.25
+		{5, 2, 0},
+		{0, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := m.AlphaAt(tt.x, tt.y).A; got != tt.want {
+			t.Errorf("coverage at (%d, %d) = %d, want %d", tt.x, tt.y, got, tt.want)
+		}
+	}
+	if a := area(m); math.Abs(a-6) > 0.02 {
+		t.Errorf("area = %v, want 6", a)
+	}
+}
+
+func TestRasterizeCurves(t *testing.T) {
+	tests := []struct {
+		name string
+		path func(p *Path)
+		want float64
+	}{
+		{"circle", func(p *Path) { p.Circle(20, 20, 15) }, math.Pi * 15 * 15},
+		{"quad", func(p *Path) {
+			p.MoveTo(5, 5)
+			p.QuadTo(15, 35, 25, 5)
+			p.Close()
+		}, 2.0 / 3 * 20 * 30 / 2},
+		{"cubic circle", func(p *Path) {
+			const k = 0.5522847498 * 10
+			p.MoveTo(30, 20)
+			p.CubicTo(30, 20+k, 20+k, 30, 20, 30)
+			p.CubicTo(20-k, 30, 10, 20+k, 10, 20)
+			p.CubicTo(10, 20-k, 20-k, 10, 20, 10)
+			p.CubicTo(20+k, 10, 30, 20-k, 30, 20)
+			p.Close()
+		}, math.Pi * 10 * 10},
+		{"rounded rect", func(p *Path) { p.RoundedRect(5, 5, 30, 20, 5) }, 30*20 - (4-math.Pi)*25},
+	}
+	for _, tt := range tests {
+		var p Path
+		tt.path(&p)
+		if a := area(rasterize(&p, NonZero)); math.Abs(a-tt.want)/tt.want > 0.005 {
+			t.Errorf("%s: area = %v, want %v", tt.name, a, tt.want)
+		}
+	}
+}
+
+func TestFillRules(t *testing.T) {
+	var p Path
+	p.Rect(5, 5, 30, 30)
+	p.Rect(15, 15, 10, 10)
+
+	nonZero := rasterize(&p, NonZero)
+	evenOdd := rasterize(&p, EvenOdd)
+	if a := nonZero.AlphaAt(20, 20).A; a != 255 {
+		t.Errorf("non-zero inner = %d, want 255", a)
+	}
+	if a := evenOdd.AlphaAt(20, 20).A; a != 0 {
+		t.Errorf("even-odd inner = %d, want 0", a)
+	}
+	if a := evenOdd.AlphaAt(10, 10).A; a != 255 {
+		t.Errorf("even-odd outer = %d, want 255", a)
+	}
+
+	// A reversed inner rectangle is a hole under both rules.
+	var q Path
+	q.Rect(5, 5, 30, 30)
+	q.MoveTo(15, 15)
+	q.LineTo(15, 25)
+	q.LineTo(25, 25)
+	q.LineTo(25, 15)
+	q.Close()
+	if a := rasterize(&q, NonZero).AlphaAt(20, 20).A; a != 0 {
+		t.Errorf("non-zero reversed inner = %d, want 0", a)
+	}
+}
+
+func strokeArea(s Stroke, build func(p *Path)) (float64, *image.Alpha) {
+	var p Path
+	build(&p)
+	m := rasterize(s.Outline(&p), NonZero)
+	return area(m), m
+}
+
+func TestStrokeCaps(t *testing.T) {
+	line := func(p *Path) {
+		p.MoveTo(10, 20)
+		p.LineTo(30, 20)
+	}
+	tests := []struct {
+		c    Cap
+		want float64
+	}{
+		{ButtCap, 20 * 4},
+		{SquareCap, 24 * 4},
+		{RoundCap, 20*4 + math.Pi*4},
+	}
+	for _, tt := range tests {
+		if a, _ := strokeArea(Stroke{Width: 4, Cap: tt.c}, line); math.Abs(a-tt.want) > 0.5 {
+			t.Errorf("cap %d: area = %v, want %v", tt.c, a, tt.want)
+		}
+	}
+}
+
+func TestStrokeJoins(t *testing.T) {
+	corner := func(p *Path) {
+		p.MoveTo(10, 30)
+		p.LineTo(10, 10)
+		p.LineTo(30, 10)
+	}
+	// Two 20x4 arms overlapping in a 2x2 square at the corner, plus the outer corner piece.
+	arms := 2*20*4 - 2*2.0
+	tests := []struct {
+		j    Join
+		want float64
+	}{
+		{MiterJoin, arms + 4},
+		{BevelJoin, arms + 2},
+		{RoundJoin, arms + math.Pi},
+	}
+	for _, tt := range tests {
+		a, m := strokeArea(Stroke{Width: 4, Join: tt.j}, corner)
+		if math.Abs(a-tt.want) > 0.5 {
+			t.Errorf("join %d: area = %v, want %v", tt.j, a, tt.want)
+		}
+		if c := m.AlphaAt(10, 10).A; c != 255 {
+			t.Errorf("join %d: inner corner coverage = %d, want 255", tt.j, c)
+		}
+	}
+
+	// A sharp miter beyond the limit falls back to a bevel.
+	sharp := func(p *Path) {
+		p.MoveTo(5, 30)
+		p.LineTo(20, 10)
+		p.LineTo(35, 30)
+	}
+	miter, _ := strokeArea(Stroke{Width: 4, Join: MiterJoin, MiterLimit: 1.5}, sharp)
+	bevel, _ := strokeArea(Stroke{Width: 4, Join: BevelJoin}, sharp)
+	if math.Abs(miter-bevel) > 0.01 {
+		t.Errorf("limited miter area = %v, want bevel area %v", miter, bevel)
+	}
+}
+
+func TestStrokeDash(t *testing.T) {
+	s := Stroke{Width: 2, Dash: []float64{5, 5}, DashOffset: 2}
+	_, m := strokeArea(s, func(p *Path) {
+		p.MoveTo(0, 20)
+		p.LineTo(40, 20)
+	})
+	for x := range 40 {
+		want := uint8(0)
+		if (x+2)%10 < 5 {
+			want = 255
+		}
+		if got := m.AlphaAt(x, 20).A; got != want {
+			t.Errorf("dash coverage at x=%d = %d, want %d", x, got, want)
+		}
+	}
+
+	// Closed subpaths are dashed along the closing segment too.
+	a, _ := strokeArea(Stroke{Width: 2, Dash: []float64{10}}, func(p *Path) { p.Rect(5, 5, 20, 20) })
+	if math.Abs(a-80*2/2) > 1 {
+		t.Errorf("dashed square area = %v, want 80", a)
+	}
+}
+
+func TestFill(t *testing.T) {
+	cfg := internal.DefaultConfig
+	white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	red := color.NRGBA{R: 255, A: 255}
+	newDst := func() *image.NRGBA {
+		dst := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+		draw.Draw(dst, dst.Rect, image.NewUniform(white), image.Point{}, draw.Src)
+		return dst
+	}
+
+	var p Path
+	p.Rect(4.5, 4, 10, 10)
+	out, err := FillColor(cfg, newDst(), image.Rect(0, 0, 20, 20), &p, NonZero, red, composite.SourceOver(), core.ToNewImage())
+	if err != nil {
+		t.Fatalf("FillColor failed: %v", err)
+	}
+	img := out.(*image.NRGBA)
+	if c := img.NRGBAAt(8, 8); c != red {
+		t.Errorf("inside = %v, want %v", c, red)
+	}
+	if c := img.NRGBAAt(1, 1); c != white {
+		t.Errorf("outside = %v, want %v", c, white)
+	}
+	if c := img.NRGBAAt(4, 8); c.G < 120 || c.G > 135 {
+		t.Errorf("half covered = %v, want half red", c)
+	}
+
+	// Blend modes only affect covered pixels.
+	dst := newDst()
+	out, err = FillColor(cfg, dst, dst.Rect, &p, NonZero, color.NRGBA{R: 255, G: 128, A: 255}, blend.Multiply(), core.ToDst())
+	if err != nil {
+		t.Fatalf("FillColor failed: %v", err)
+	}
+	if c := dst.NRGBAAt(8, 8); c.B != 0 || c.R != 255 {
+		t.Errorf("multiplied = %v", c)
+	}
+	if c := dst.NRGBAAt(18, 18); c != white {
+		t.Errorf("outside multiplied = %v, want %v", c, white)
+	}
+	if out != dst {
+		t.Error("ToDst should return dst")
+	}
+
+	// Image sources are aligned with sp and RGBA destinations are supported.
+	rgba := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	pattern := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+	pattern.SetNRGBA(13, 13, red)
+	_, err = Fill(cfg, rgba, rgba.Rect, &p, EvenOdd, pattern, image.Pt(5, 5), composite.SourceOver(), core.ToDst())
+	if err != nil {
+		t.Fatalf("Fill failed: %v", err)
+	}
+	if c := rgba.RGBAAt(8, 8); c != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("image fill = %v, want red", c)
+	}
+
+	if _, err = Fill(cfg, rgba, rgba.Rect, &p, FillRule(9), pattern, image.Point{}, composite.SourceOver(), nil); err == nil {
+		t.Error("expected error for invalid fill rule")
+	}
+}